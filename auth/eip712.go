@@ -52,6 +52,31 @@ func BuildClobEip712Signature(privateKey *ecdsa.PrivateKey, chainID int64, times
 	// Get address from private key
 	address := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
 
+	hash, err := ClobAuthHash(address, chainID, timestamp, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	// Sign the hash
+	signature, err := crypto.Sign(hash.Bytes(), privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign hash: %w", err)
+	}
+
+	// Adjust v value from 0/1 to 27/28 (Ethereum standard)
+	if signature[64] < 27 {
+		signature[64] += 27
+	}
+
+	// Convert signature to hex string
+	signatureHex := hexutil.Encode(signature)
+
+	return signatureHex, nil
+}
+
+// ClobAuthHash returns the EIP712 digest an L1 signature covers: the ClobAuth
+// struct for address, timestamp and nonce under the ClobAuthDomain of chainID
+func ClobAuthHash(address string, chainID int64, timestamp int64, nonce uint64) (common.Hash, error) {
 	// Create domain
 	domain := EIP712Domain{
 		Name:    "ClobAuthDomain",
@@ -80,17 +105,17 @@ func BuildClobEip712Signature(privateKey *ecdsa.PrivateKey, chainID int64, times
 	// Generate the sign hash according to EIP-712
 	domainSeparator, err := getDomainSeparator(domain)
 	if err != nil {
-		return "", fmt.Errorf("failed to get domain separator: %w", err)
+		return common.Hash{}, fmt.Errorf("failed to get domain separator: %w", err)
 	}
 
 	typeHash, err := getTypeHash(types["ClobAuth"])
 	if err != nil {
-		return "", fmt.Errorf("failed to get type hash: %w", err)
+		return common.Hash{}, fmt.Errorf("failed to get type hash: %w", err)
 	}
 
 	encodeData, err := encodeClobAuthData(message)
 	if err != nil {
-		return "", fmt.Errorf("failed to encode data: %w", err)
+		return common.Hash{}, fmt.Errorf("failed to encode data: %w", err)
 	}
 
 	// Hash the struct: keccak256(typeHash || encodeData)
@@ -101,21 +126,7 @@ func BuildClobEip712Signature(privateKey *ecdsa.PrivateKey, chainID int64, times
 		append(append([]byte("\x19\x01"), domainSeparator.Bytes()...), structHash.Bytes()...),
 	)
 
-	// Sign the hash
-	signature, err := crypto.Sign(hash.Bytes(), privateKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to sign hash: %w", err)
-	}
-
-	// Adjust v value from 0/1 to 27/28 (Ethereum standard)
-	if signature[64] < 27 {
-		signature[64] += 27
-	}
-
-	// Convert signature to hex string
-	signatureHex := hexutil.Encode(signature)
-
-	return signatureHex, nil
+	return hash, nil
 }
 
 // getDomainSeparator creates the domain separator hash according to EIP-712
@@ -240,9 +251,9 @@ func RecoverAddress(hash common.Hash, signature string) (common.Address, error)
 		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
 	}
 
-	// Adjust v value if needed (go-ethereum expects 27 or 28)
-	if sig[64] != 27 && sig[64] != 28 {
-		sig[64] += 27
+	// Adjust v value if needed (go-ethereum expects 0 or 1)
+	if sig[64] >= 27 {
+		sig[64] -= 27
 	}
 
 	pubkey, err := crypto.SigToPub(hash.Bytes(), sig)
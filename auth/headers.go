@@ -3,9 +3,11 @@ package auth
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ybina/polymarket-sdk-go/types"
@@ -90,6 +92,15 @@ type BuilderConfig struct {
 	Passphrase string `json:"passphrase"`
 }
 
+// LogValue keeps the secret and passphrase out of logs
+func (bc BuilderConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("apiKey", bc.APIKey),
+		slog.String("secret", "[REDACTED]"),
+		slog.String("passphrase", "[REDACTED]"),
+	)
+}
+
 // IsValid checks if the builder config is valid
 func (bc *BuilderConfig) IsValid() bool {
 	return bc != nil && bc.APIKey != "" && bc.Secret != "" && bc.Passphrase != ""
@@ -97,12 +108,16 @@ func (bc *BuilderConfig) IsValid() bool {
 
 // GenerateBuilderHeaders generates builder headers
 func (bc *BuilderConfig) GenerateBuilderHeaders(method string, path string, body *string) (*L2WithBuilderHeader, error) {
+	return bc.GenerateBuilderHeadersAt(method, path, body, time.Now().Unix())
+}
+
+// GenerateBuilderHeadersAt generates builder headers signed with timestamp,
+// e.g. the server time
+func (bc *BuilderConfig) GenerateBuilderHeadersAt(method string, path string, body *string, ts int64) (*L2WithBuilderHeader, error) {
 	if !bc.IsValid() {
 		return nil, fmt.Errorf("invalid builder config")
 	}
 
-	ts := time.Now().Unix()
-
 	var bodyStr *string
 	if body != nil {
 		bodyStr = body
@@ -133,43 +148,19 @@ func InjectBuilderHeaders(l2Headers *types.L2PolyHeader, builderHeaders *L2WithB
 	return combined
 }
 
-// VerifyEIP712Signature verifies an EIP712 signature
+// VerifyEIP712Signature verifies an L1 ClobAuth signature, as sent in the
+// POLY_SIGNATURE header, against the address that claims to have made it
 func VerifyEIP712Signature(address string, signature string, timestamp int64, nonce uint64, chainID types.Chain) (bool, error) {
 	// Parse the signature
 	_, err := hexutil.Decode(signature)
 	if err != nil {
 		return false, fmt.Errorf("failed to decode signature: %w", err)
 	}
-
-	// Create the typed data hash
-	domain := EIP712Domain{
-		Name:    "ClobAuthDomain",
-		Version: "1",
-		ChainID: int64(chainID),
-	}
-
-	message := ClobAuthData{
-		Address:   address,
-		Timestamp: fmt.Sprintf("%d", timestamp),
-		Nonce:     nonce,
-		Message:   MSG_TO_SIGN,
-	}
-
-	typedData := TypedData{
-		Types: map[string][]EIP712Type{
-			"ClobAuth": {
-				{Name: "address", Type: "address"},
-				{Name: "timestamp", Type: "string"},
-				{Name: "nonce", Type: "uint256"},
-				{Name: "message", Type: "string"},
-			},
-		},
-		PrimaryType: "ClobAuth",
-		Domain:      domain,
-		Message:     message,
+	if !common.IsHexAddress(address) {
+		return false, fmt.Errorf("invalid address %q", address)
 	}
 
-	hash, err := getTypedDataHash(typedData)
+	hash, err := ClobAuthHash(address, int64(chainID), timestamp, nonce)
 	if err != nil {
 		return false, fmt.Errorf("failed to get typed data hash: %w", err)
 	}
@@ -180,5 +171,5 @@ func VerifyEIP712Signature(address string, signature string, timestamp int64, no
 		return false, fmt.Errorf("failed to recover address: %w", err)
 	}
 
-	return recoveredAddress.Hex() == address, nil
+	return recoveredAddress == common.HexToAddress(address), nil
 }
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+func TestVerifyEIP712SignatureRoundTrip(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := uint64(7)
+	ts := int64(1700000000)
+
+	headers, err := CreateL1Headers(key, types.ChainAmoy, &nonce, &ts)
+	if err != nil {
+		t.Fatalf("CreateL1Headers() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		address string
+		ts      int64
+		nonce   uint64
+		chain   types.Chain
+		want    bool
+	}{
+		{name: "valid", address: headers.POLYAddress, ts: ts, nonce: nonce, chain: types.ChainAmoy, want: true},
+		{name: "lowercase address", address: strings.ToLower(headers.POLYAddress), ts: ts, nonce: nonce, chain: types.ChainAmoy, want: true},
+		{name: "other timestamp", address: headers.POLYAddress, ts: ts + 1, nonce: nonce, chain: types.ChainAmoy},
+		{name: "other nonce", address: headers.POLYAddress, ts: ts, nonce: 0, chain: types.ChainAmoy},
+		{name: "other chain", address: headers.POLYAddress, ts: ts, nonce: nonce, chain: types.ChainPolygon},
+		{name: "other address", address: "0x0000000000000000000000000000000000000001", ts: ts, nonce: nonce, chain: types.ChainAmoy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyEIP712Signature(tt.address, headers.POLYSignature, tt.ts, tt.nonce, tt.chain)
+			if err != nil {
+				t.Fatalf("VerifyEIP712Signature() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyEIP712Signature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if headers.POLYTimestamp != strconv.FormatInt(ts, 10) || headers.POLYNonce != "7" {
+		t.Errorf("unexpected headers %+v", headers)
+	}
+}
+
+func TestVerifyHmacSignatureMatchesL2Headers(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := &types.ApiKeyCreds{Key: "key", Secret: "c2VjcmV0LXNlY3JldC1zZWNyZXQ=", Passphrase: "pass"}
+	ts := int64(1700000000)
+	args := &types.L2HeaderArgs{Method: "POST", RequestPath: "/order", Body: `{"a":1}`}
+
+	headers, err := CreateL2Headers(key, creds, args, &ts)
+	if err != nil {
+		t.Fatalf("CreateL2Headers() error = %v", err)
+	}
+
+	if !VerifyHmacSignature(creds.Secret, ts, "POST", "/order", &args.Body, headers.POLYSignature) {
+		t.Error("VerifyHmacSignature() = false for the signed request")
+	}
+	other := `{"a":2}`
+	if VerifyHmacSignature(creds.Secret, ts, "POST", "/order", &other, headers.POLYSignature) {
+		t.Error("VerifyHmacSignature() = true for a different body")
+	}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+const (
+	// ExchangeDomainName is the EIP712 domain name of the CTF exchanges
+	ExchangeDomainName = "Polymarket CTF Exchange"
+	// ExchangeDomainVersion is the EIP712 domain version of the CTF exchanges
+	ExchangeDomainVersion = "1"
+)
+
+var (
+	exchangeDomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	orderTypeHash          = crypto.Keccak256Hash([]byte("Order(uint256 salt,address maker,address signer,address taker,uint256 tokenId,uint256 makerAmount,uint256 takerAmount,uint256 expiration,uint256 nonce,uint256 feeRateBps,uint8 side,uint8 signatureType)"))
+)
+
+// OrderHash returns the EIP712 digest an order signature covers, for the
+// exchange contract at exchange on chainID
+func OrderHash(order *types.SignedOrder, chainID int64, exchange string) (common.Hash, error) {
+	if !common.IsHexAddress(exchange) {
+		return common.Hash{}, fmt.Errorf("invalid exchange address %q", exchange)
+	}
+
+	// Domain separator: name, version, chainId and verifying contract
+	domain := append(exchangeDomainTypeHash.Bytes(), crypto.Keccak256([]byte(ExchangeDomainName))...)
+	domain = append(domain, crypto.Keccak256([]byte(ExchangeDomainVersion))...)
+	domain = append(domain, word(big.NewInt(chainID))...)
+	domain = append(domain, addressWord(exchange)...)
+	domainSeparator := crypto.Keccak256(domain)
+
+	var side int64
+	switch order.Side {
+	case types.SideBuy:
+		side = 0
+	case types.SideSell:
+		side = 1
+	default:
+		return common.Hash{}, fmt.Errorf("invalid order side %q", order.Side)
+	}
+
+	salt, err := uint256("salt", order.Salt)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	encoded := append(orderTypeHash.Bytes(), word(salt)...)
+	for _, address := range []string{order.Maker, order.Signer, order.Taker} {
+		if !common.IsHexAddress(address) {
+			return common.Hash{}, fmt.Errorf("invalid order address %q", address)
+		}
+		encoded = append(encoded, addressWord(address)...)
+	}
+	tokenID, err := uint256("tokenId", order.TokenID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	encoded = append(encoded, word(tokenID)...)
+	if order.MakerAmount == nil || order.TakerAmount == nil {
+		return common.Hash{}, fmt.Errorf("order amounts are required")
+	}
+	encoded = append(encoded, word(order.MakerAmount)...)
+	encoded = append(encoded, word(order.TakerAmount)...)
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"expiration", order.Expiration},
+		{"nonce", order.Nonce},
+		{"feeRateBps", order.FeeRateBps},
+	} {
+		n, err := uint256(field.name, field.value)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		encoded = append(encoded, word(n)...)
+	}
+	encoded = append(encoded, word(big.NewInt(side))...)
+	encoded = append(encoded, word(big.NewInt(int64(order.SignatureType)))...)
+	structHash := crypto.Keccak256(encoded)
+
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator, structHash), nil
+}
+
+// SignOrder signs order for the exchange contract at exchange on chainID and
+// returns the hex signature with v as 27 or 28
+func SignOrder(privateKey *ecdsa.PrivateKey, order *types.SignedOrder, chainID int64, exchange string) (string, error) {
+	hash, err := OrderHash(order, chainID, exchange)
+	if err != nil {
+		return "", err
+	}
+	signature, err := crypto.Sign(hash.Bytes(), privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign order: %w", err)
+	}
+	signature[64] += 27
+	return hexutil.Encode(signature), nil
+}
+
+// uint256 parses a decimal field of an order, empty meaning 0
+func uint256(name, value string) (*big.Int, error) {
+	if value == "" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid order %s %q", name, value)
+	}
+	return n, nil
+}
+
+// word encodes n as a 32 byte big-endian ABI word
+func word(n *big.Int) []byte {
+	return common.LeftPadBytes(n.Bytes(), 32)
+}
+
+// addressWord encodes a hex address as a left-padded ABI word
+func addressWord(address string) []byte {
+	return common.LeftPadBytes(common.HexToAddress(address).Bytes(), 32)
+}
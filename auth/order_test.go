@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+func TestOrderHashMatchesEIP712(t *testing.T) {
+	const exchange = "0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E"
+	order := &types.SignedOrder{
+		Salt:          "479249096354",
+		Maker:         "0x1111111111111111111111111111111111111111",
+		Signer:        "0x1111111111111111111111111111111111111111",
+		Taker:         "0x0000000000000000000000000000000000000000",
+		TokenID:       "71321045679252212594626385532706912750332728571942532289631379312455583992563",
+		MakerAmount:   big.NewInt(50_000_000),
+		TakerAmount:   big.NewInt(100_000_000),
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    "0",
+		Side:          types.SideSell,
+		SignatureType: types.SignatureTypeEIP712,
+	}
+
+	typed := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Order": {
+				{Name: "salt", Type: "uint256"},
+				{Name: "maker", Type: "address"},
+				{Name: "signer", Type: "address"},
+				{Name: "taker", Type: "address"},
+				{Name: "tokenId", Type: "uint256"},
+				{Name: "makerAmount", Type: "uint256"},
+				{Name: "takerAmount", Type: "uint256"},
+				{Name: "expiration", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "feeRateBps", Type: "uint256"},
+				{Name: "side", Type: "uint8"},
+				{Name: "signatureType", Type: "uint8"},
+			},
+		},
+		PrimaryType: "Order",
+		Domain: apitypes.TypedDataDomain{
+			Name:              ExchangeDomainName,
+			Version:           ExchangeDomainVersion,
+			ChainId:           math.NewHexOrDecimal256(int64(types.ChainPolygon)),
+			VerifyingContract: exchange,
+		},
+		Message: apitypes.TypedDataMessage{
+			"salt":          order.Salt,
+			"maker":         order.Maker,
+			"signer":        order.Signer,
+			"taker":         order.Taker,
+			"tokenId":       order.TokenID,
+			"makerAmount":   order.MakerAmount.String(),
+			"takerAmount":   order.TakerAmount.String(),
+			"expiration":    order.Expiration,
+			"nonce":         order.Nonce,
+			"feeRateBps":    order.FeeRateBps,
+			"side":          "1",
+			"signatureType": "0",
+		},
+	}
+	want, _, err := apitypes.TypedDataAndHash(typed)
+	if err != nil {
+		t.Fatalf("TypedDataAndHash() error = %v", err)
+	}
+
+	got, err := OrderHash(order, int64(types.ChainPolygon), exchange)
+	if err != nil {
+		t.Fatalf("OrderHash() error = %v", err)
+	}
+	if got != common.BytesToHash(want) {
+		t.Errorf("OrderHash() = %s, want %x", got, want)
+	}
+}
+
+func TestSignOrder(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	order := &types.SignedOrder{
+		Salt:        "1",
+		Maker:       address,
+		Signer:      address,
+		Taker:       common.Address{}.Hex(),
+		TokenID:     "1234",
+		MakerAmount: big.NewInt(1_000_000),
+		TakerAmount: big.NewInt(2_000_000),
+		Side:        types.SideBuy,
+	}
+	const exchange = "0xdFE02Eb6733538f8Ea35D585af8DE5958AD99E40"
+
+	signature, err := SignOrder(key, order, int64(types.ChainAmoy), exchange)
+	if err != nil {
+		t.Fatalf("SignOrder() error = %v", err)
+	}
+	hash, _ := OrderHash(order, int64(types.ChainAmoy), exchange)
+	recovered, err := RecoverAddress(hash, signature)
+	if err != nil {
+		t.Fatalf("RecoverAddress() error = %v", err)
+	}
+	if recovered != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("recovered %s, want %s", recovered.Hex(), address)
+	}
+
+	order.Side = "HOLD"
+	if _, err := SignOrder(key, order, int64(types.ChainAmoy), exchange); err == nil {
+		t.Error("SignOrder() with an invalid side succeeded")
+	}
+}
@@ -2,8 +2,11 @@ package auth
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -39,6 +42,53 @@ func NewWalletFromHex(privateKeyHex string) (*Wallet, error) {
 	return NewWalletFromPrivateKey(privateKey), nil
 }
 
+// keystoreV3 is the part of an Ethereum JSON keystore (V3) needed to
+// decrypt its key
+type keystoreV3 struct {
+	Address string              `json:"address"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	Version int                 `json:"version"`
+}
+
+// NewWalletFromKeystore creates a wallet from an Ethereum JSON keystore (V3)
+// encrypted with passphrase. The decrypted key bytes are zeroed once the
+// private key is parsed.
+func NewWalletFromKeystore(keystoreJSON []byte, passphrase string) (*Wallet, error) {
+	var k keystoreV3
+	if err := json.Unmarshal(keystoreJSON, &k); err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	if k.Version != 3 {
+		return nil, fmt.Errorf("failed to decrypt keystore: unsupported version %d", k.Version)
+	}
+	keyBytes, err := keystore.DecryptDataV3(k.Crypto, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	defer clear(keyBytes)
+
+	privateKey, err := crypto.ToECDSA(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	wallet := NewWalletFromPrivateKey(privateKey)
+	if k.Address != "" && common.HexToAddress(k.Address) != wallet.address {
+		wallet.Zero()
+		return nil, fmt.Errorf("failed to decrypt keystore: key does not match address %s", k.Address)
+	}
+	return wallet, nil
+}
+
+// Zero overwrites the private key in memory. The wallet keeps its address
+// but can no longer sign. Zero must not run while the wallet signs; the CLOB
+// client's Close waits for its own signers.
+func (w *Wallet) Zero() {
+	if w.privateKey != nil && w.privateKey.D != nil {
+		clear(w.privateKey.D.Bits())
+		w.privateKey.D.SetInt64(0)
+	}
+}
+
 // GetAddress returns the wallet address
 func (w *Wallet) GetAddress() common.Address {
 	return w.address
@@ -49,6 +99,11 @@ func (w *Wallet) GetAddressHex() string {
 	return w.address.Hex()
 }
 
+// LogValue logs the wallet as its address, never its private key
+func (w *Wallet) LogValue() slog.Value {
+	return slog.StringValue(w.address.Hex())
+}
+
 // GetPrivateKey returns the private key
 func (w *Wallet) GetPrivateKey() *ecdsa.PrivateKey {
 	return w.privateKey
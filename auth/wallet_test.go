@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestNewWalletFromKeystore(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	keystoreJSON, err := keystore.EncryptKey(&keystore.Key{Address: address, PrivateKey: key}, "hunter2", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		json       []byte
+		passphrase string
+		wantErr    bool
+	}{
+		{name: "valid", json: keystoreJSON, passphrase: "hunter2"},
+		{name: "wrong passphrase", json: keystoreJSON, passphrase: "hunter3", wantErr: true},
+		{name: "not a keystore", json: []byte(`{}`), passphrase: "hunter2", wantErr: true},
+		{name: "other address", json: []byte(strings.Replace(string(keystoreJSON), strings.ToLower(address.Hex()[2:]), "1111111111111111111111111111111111111111", 1)), passphrase: "hunter2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet, err := NewWalletFromKeystore(tt.json, tt.passphrase)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("NewWalletFromKeystore() should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewWalletFromKeystore() error = %v", err)
+			}
+			if wallet.GetAddress() != address {
+				t.Errorf("address = %s, want %s", wallet.GetAddressHex(), address.Hex())
+			}
+		})
+	}
+}
+
+func TestWalletZero(t *testing.T) {
+	wallet, err := NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := wallet.GetAddress()
+
+	wallet.Zero()
+	if wallet.GetPrivateKey().D.Sign() != 0 {
+		t.Error("private key not zeroed")
+	}
+	if wallet.GetAddress() != address {
+		t.Error("Zero() changed the address")
+	}
+	wallet.Zero()
+}
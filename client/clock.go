@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Defaults of ClockOptions
+const (
+	DefaultClockInterval = time.Minute
+	DefaultMaxClockDrift = 10 * time.Second
+)
+
+// ClockOptions configures how a client tracks the server clock when
+// ClientConfig.UseServerTime is set
+type ClockOptions struct {
+	// Interval between measurements of the offset (default DefaultClockInterval)
+	Interval time.Duration
+
+	// MaxDrift is the offset from the server clock above which signatures made
+	// with the local clock risk being rejected (default DefaultMaxClockDrift)
+	MaxDrift time.Duration
+
+	// OnDrift is called after a measurement whose offset exceeds MaxDrift.
+	// The offset is always applied; OnDrift only alerts.
+	OnDrift func(offset time.Duration)
+
+	// Now is the local clock (default time.Now)
+	Now func() time.Time
+}
+
+// ServerClock tracks the offset of the local clock from the server's, so that
+// signed timestamps follow the server without fetching its time per request.
+// The offset is measured on first use and again once older than Interval,
+// or every Interval while Run is running.
+type ServerClock struct {
+	fetch   func() (int64, error) // server time in Unix seconds
+	options ClockOptions
+	logger  *slog.Logger
+
+	// syncMu serializes measurements
+	syncMu   sync.Mutex
+	mu       sync.RWMutex
+	offset   time.Duration
+	rtt      time.Duration
+	syncedAt time.Time // local time of the last measurement, zero before the first
+	// triedAt is the local time of the last attempt, successful or not, so
+	// that a failing refresh is retried after Interval rather than per request
+	triedAt time.Time
+}
+
+// newServerClock returns a clock measuring the server time with fetch
+func newServerClock(fetch func() (int64, error), options *ClockOptions, logger *slog.Logger) *ServerClock {
+	var opts ClockOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultClockInterval
+	}
+	if opts.MaxDrift <= 0 {
+		opts.MaxDrift = DefaultMaxClockDrift
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &ServerClock{fetch: fetch, options: opts, logger: logger}
+}
+
+// Now returns the estimated server time, measuring the offset first if it
+// is missing or stale. A failed refresh keeps the previous offset until the
+// next attempt an Interval later; only the first measurement failing is an
+// error.
+func (s *ServerClock) Now() (time.Time, error) {
+	s.mu.RLock()
+	offset, syncedAt, triedAt := s.offset, s.syncedAt, s.triedAt
+	s.mu.RUnlock()
+
+	now := s.options.Now()
+	if s.stale(now, syncedAt, triedAt) {
+		if err := s.syncIfStale(); err != nil {
+			if syncedAt.IsZero() {
+				return time.Time{}, err
+			}
+			s.logger.Warn("server clock sync failed, keeping the previous offset", "error", err, "offset", offset)
+		}
+		s.mu.RLock()
+		offset = s.offset
+		s.mu.RUnlock()
+		now = s.options.Now()
+	}
+	return now.Add(offset), nil
+}
+
+// stale reports whether the offset needs measuring at now: always before the
+// first measurement, then once the last attempt is an Interval old
+func (s *ServerClock) stale(now, syncedAt, triedAt time.Time) bool {
+	return syncedAt.IsZero() || now.Sub(triedAt) >= s.options.Interval
+}
+
+// syncIfStale measures the offset unless a concurrent caller just tried
+func (s *ServerClock) syncIfStale() error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	s.mu.RLock()
+	syncedAt, triedAt := s.syncedAt, s.triedAt
+	s.mu.RUnlock()
+	if !s.stale(s.options.Now(), syncedAt, triedAt) {
+		return nil
+	}
+	return s.measure()
+}
+
+// Sync measures the offset and round trip now
+func (s *ServerClock) Sync() error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	return s.measure()
+}
+
+// measure fetches the server time and records the offset; s.syncMu must be held
+func (s *ServerClock) measure() error {
+	start := s.options.Now()
+	serverTime, err := s.fetch()
+	end := s.options.Now()
+	s.mu.Lock()
+	s.triedAt = end
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to get server time: %w", err)
+	}
+
+	// The server answered halfway through the round trip, with a time
+	// truncated to the second
+	rtt := end.Sub(start)
+	server := time.Unix(serverTime, 0).Add(time.Second / 2)
+	offset := server.Sub(start.Add(rtt / 2)).Round(time.Millisecond)
+
+	s.mu.Lock()
+	s.offset, s.rtt, s.syncedAt = offset, rtt, end
+	s.mu.Unlock()
+	s.logger.Debug("server clock synced", "offset", offset, "rtt", rtt)
+
+	if offset.Abs() > s.options.MaxDrift {
+		s.logger.Warn("local clock drifts from the server", "offset", offset, "maxDrift", s.options.MaxDrift)
+		if s.options.OnDrift != nil {
+			s.options.OnDrift(offset)
+		}
+	}
+	return nil
+}
+
+// Run measures the offset every Interval until ctx is done, so that requests
+// never wait for a measurement. Errors are logged and retried on the next tick.
+func (s *ServerClock) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Sync(); err != nil {
+			s.logger.Warn("server clock sync failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Offset returns how far the server clock is ahead of the local one, as of
+// the last measurement
+func (s *ServerClock) Offset() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.offset
+}
+
+// RTT returns the round trip of the last measurement
+func (s *ServerClock) RTT() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rtt
+}
+
+// SyncedAt returns the local time of the last measurement, zero before the first
+func (s *ServerClock) SyncedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.syncedAt
+}
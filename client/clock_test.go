@@ -0,0 +1,109 @@
+package client
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeClock is a local clock advanced by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func TestServerClockMeasure(t *testing.T) {
+	local := time.Unix(1700000000, 0)
+	tests := []struct {
+		name       string
+		serverTime int64
+		rtt        time.Duration
+		wantOffset time.Duration
+		wantDrift  bool
+	}{
+		{name: "in sync", serverTime: 1700000000, wantOffset: 500 * time.Millisecond},
+		{name: "round trip", serverTime: 1700000000, rtt: 400 * time.Millisecond, wantOffset: 300 * time.Millisecond},
+		{name: "server ahead", serverTime: 1700000030, wantOffset: 30500 * time.Millisecond, wantDrift: true},
+		{name: "server behind", serverTime: 1699999990, wantOffset: -9500 * time.Millisecond},
+		{name: "server far behind", serverTime: 1699999980, wantOffset: -19500 * time.Millisecond, wantDrift: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: local}
+			var drift *time.Duration
+			s := newServerClock(func() (int64, error) {
+				clock.now = clock.now.Add(tt.rtt)
+				return tt.serverTime, nil
+			}, &ClockOptions{
+				Now:     clock.Now,
+				OnDrift: func(offset time.Duration) { drift = &offset },
+			}, slog.New(slog.DiscardHandler))
+
+			now, err := s.Now()
+			if err != nil {
+				t.Fatalf("Now() error = %v", err)
+			}
+			if s.Offset() != tt.wantOffset || s.RTT() != tt.rtt {
+				t.Errorf("Offset() = %v, RTT() = %v, want %v, %v", s.Offset(), s.RTT(), tt.wantOffset, tt.rtt)
+			}
+			if want := clock.now.Add(tt.wantOffset); !now.Equal(want) {
+				t.Errorf("Now() = %v, want %v", now, want)
+			}
+			if (drift != nil) != tt.wantDrift || drift != nil && *drift != tt.wantOffset {
+				t.Errorf("OnDrift() called with %v, want drift %v", drift, tt.wantDrift)
+			}
+		})
+	}
+}
+
+func TestServerClockRefresh(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	fetches := 0
+	var fetchErr error
+	s := newServerClock(func() (int64, error) {
+		fetches++
+		return clock.now.Unix() + 5, fetchErr
+	}, &ClockOptions{Interval: time.Minute, Now: clock.Now}, slog.New(slog.DiscardHandler))
+
+	for range 3 {
+		if _, err := s.Now(); err != nil {
+			t.Fatalf("Now() error = %v", err)
+		}
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+	if fetches != 1 {
+		t.Errorf("fetched %d times within the interval, want 1", fetches)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	fetchErr = errors.New("unreachable")
+	if _, err := s.Now(); err != nil {
+		t.Errorf("Now() with a stale offset and a failing refresh error = %v", err)
+	}
+	if fetches != 2 || s.Offset() != 5500*time.Millisecond {
+		t.Errorf("fetches = %d, Offset() = %v, want 2, 5.5s", fetches, s.Offset())
+	}
+
+	// The failed attempt is not retried per request but an interval later
+	for range 3 {
+		clock.now = clock.now.Add(10 * time.Second)
+		s.Now()
+	}
+	if fetches != 2 {
+		t.Errorf("fetched %d times after a failed refresh, want 2", fetches)
+	}
+	clock.now = clock.now.Add(30 * time.Second)
+	fetchErr = nil
+	s.Now()
+	if fetches != 3 || !s.SyncedAt().Equal(clock.now) {
+		t.Errorf("fetches = %d, SyncedAt() = %v, want a refresh an interval after the failure", fetches, s.SyncedAt())
+	}
+
+	failing := newServerClock(func() (int64, error) { return 0, errors.New("unreachable") }, nil, slog.New(slog.DiscardHandler))
+	if _, err := failing.Now(); err == nil {
+		t.Error("Now() without any measurement should fail")
+	}
+}
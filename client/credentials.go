@@ -0,0 +1,171 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// Environment variables read by NewClobClientFromEnv and
+// NewClobClientFromDotenv, shared with the polymarket CLI
+const (
+	EnvPrivateKey         = "POLYMARKET_PRIVATE_KEY"         // hex private key
+	EnvKeystore           = "POLYMARKET_KEYSTORE"            // path of a JSON keystore, instead of the private key
+	EnvKeystorePassphrase = "POLYMARKET_KEYSTORE_PASSPHRASE" // passphrase of the keystore
+	EnvAPIKey             = "POLYMARKET_API_KEY"
+	EnvAPISecret          = "POLYMARKET_API_SECRET"
+	EnvAPIPassphrase      = "POLYMARKET_API_PASSPHRASE"
+	EnvAPIKeyNonce        = "POLYMARKET_API_KEY_NONCE" // nonce of the API key, see ClientConfig.APIKeyNonce
+	EnvChainID            = "POLYMARKET_CHAIN_ID"
+	EnvClobURL            = "POLYMARKET_CLOB_URL"
+	EnvSignatureType      = "POLYMARKET_SIGNATURE_TYPE" // 0 (EOA), 1 (proxy wallet) or 2 (Safe)
+	EnvFunderAddress      = "POLYMARKET_FUNDER_ADDRESS" // maker of the orders, see ClientConfig.FunderAddress
+)
+
+// CredentialsFile is the content of a credentials file read by
+// NewClobClientFromCredentialsFile
+type CredentialsFile struct {
+	PrivateKey string             `json:"privateKey,omitempty"`
+	APIKey     *types.ApiKeyCreds `json:"apiKey,omitempty"`
+}
+
+// NewClobClientFromKeystore creates a CLOB client signing with the key of
+// the Ethereum JSON keystore (V3) at path. The remaining settings come from
+// config, which may be nil.
+func NewClobClientFromKeystore(path, passphrase string, config *ClientConfig) (*ClobClient, error) {
+	keystoreJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	wallet, err := auth.NewWalletFromKeystore(keystoreJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := copyConfig(config)
+	cfg.Wallet = wallet
+	return NewClobClient(cfg)
+}
+
+// NewClobClientFromEnv creates a CLOB client from the POLYMARKET_*
+// environment variables, which override the settings of config, which may
+// be nil
+func NewClobClientFromEnv(config *ClientConfig) (*ClobClient, error) {
+	return newClobClientFromLookup(os.Getenv, config)
+}
+
+// NewClobClientFromDotenv creates a CLOB client from the POLYMARKET_*
+// variables of the KEY=VALUE file at path. Variables set in the environment
+// take precedence over the file, and both over the settings of config,
+// which may be nil.
+func NewClobClientFromDotenv(path string, config *ClientConfig) (*ClobClient, error) {
+	file, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+	return newClobClientFromLookup(func(key string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return file[key]
+	}, config)
+}
+
+// NewClobClientFromCredentialsFile creates a CLOB client from the JSON
+// CredentialsFile at path. On Unix the file must not be accessible to the
+// group or others, like an SSH key.
+func NewClobClientFromCredentialsFile(path string, config *ClientConfig) (*ClobClient, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("credentials file %s has mode %v, want 0600 or stricter", path, info.Mode().Perm())
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	var file CredentialsFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+
+	cfg := copyConfig(config)
+	if file.PrivateKey != "" {
+		cfg.PrivateKey = file.PrivateKey
+		cfg.Wallet = nil
+	}
+	if file.APIKey != nil {
+		cfg.APIKey = file.APIKey
+	}
+	return NewClobClient(cfg)
+}
+
+// newClobClientFromLookup creates a CLOB client from the variables lookup
+// returns, falling back to config
+func newClobClientFromLookup(lookup func(string) string, config *ClientConfig) (*ClobClient, error) {
+	cfg := copyConfig(config)
+	if host := lookup(EnvClobURL); host != "" {
+		cfg.Host = host
+	}
+	if chainID := lookup(EnvChainID); chainID != "" {
+		id, err := strconv.Atoi(chainID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", EnvChainID, chainID)
+		}
+		cfg.ChainID = types.Chain(id)
+	}
+	if nonce := lookup(EnvAPIKeyNonce); nonce != "" {
+		n, err := strconv.ParseUint(nonce, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", EnvAPIKeyNonce, nonce)
+		}
+		cfg.APIKeyNonce = n
+	}
+	if signatureType := lookup(EnvSignatureType); signatureType != "" {
+		n, err := strconv.Atoi(signatureType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", EnvSignatureType, signatureType)
+		}
+		cfg.SignatureType = types.SignatureType(n)
+	}
+	if funder := lookup(EnvFunderAddress); funder != "" {
+		cfg.FunderAddress = funder
+	}
+	if key := lookup(EnvAPIKey); key != "" {
+		cfg.APIKey = &types.ApiKeyCreds{
+			Key:        key,
+			Secret:     lookup(EnvAPISecret),
+			Passphrase: lookup(EnvAPIPassphrase),
+		}
+	}
+
+	switch {
+	case lookup(EnvKeystore) != "":
+		return NewClobClientFromKeystore(lookup(EnvKeystore), lookup(EnvKeystorePassphrase), cfg)
+	case lookup(EnvPrivateKey) != "":
+		cfg.PrivateKey = lookup(EnvPrivateKey)
+		cfg.Wallet = nil
+	}
+	return NewClobClient(cfg)
+}
+
+// copyConfig returns a copy of config, which may be nil, with the Polygon
+// chain unless it sets one
+func copyConfig(config *ClientConfig) *ClientConfig {
+	var cfg ClientConfig
+	if config != nil {
+		cfg = *config
+	}
+	if cfg.ChainID == 0 {
+		cfg.ChainID = types.ChainPolygon
+	}
+	return &cfg
+}
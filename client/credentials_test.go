@@ -0,0 +1,220 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// clearEnv unsets the variables read by the loaders for the test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{EnvPrivateKey, EnvKeystore, EnvKeystorePassphrase, EnvAPIKey, EnvAPISecret, EnvAPIPassphrase, EnvChainID, EnvClobURL} {
+		t.Setenv(key, "")
+	}
+}
+
+// writeFile writes content to name in a temporary directory with mode perm
+func writeFile(t *testing.T, name string, content []byte, perm os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCredentialLoaders(t *testing.T) {
+	clearEnv(t)
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := auth.PrivateKeyToHex(wallet.GetPrivateKey())
+	creds := &types.ApiKeyCreds{Key: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+
+	keystoreJSON, err := keystore.EncryptKey(&keystore.Key{Address: wallet.GetAddress(), PrivateKey: wallet.GetPrivateKey()}, "hunter2", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystorePath := writeFile(t, "keystore.json", keystoreJSON, 0o600)
+	dotenv := "POLYMARKET_PRIVATE_KEY=" + privateKey + "\nPOLYMARKET_API_KEY=key\nPOLYMARKET_API_SECRET=c2VjcmV0\nPOLYMARKET_API_PASSPHRASE=pass\nPOLYMARKET_CHAIN_ID=80002\n"
+	dotenvPath := writeFile(t, ".env", []byte(dotenv), 0o600)
+	credentialsJSON, _ := json.Marshal(CredentialsFile{PrivateKey: privateKey, APIKey: creds})
+	credentialsPath := writeFile(t, "credentials.json", credentialsJSON, 0o600)
+	sharedPath := writeFile(t, "shared.json", credentialsJSON, 0o644)
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		load      func() (*ClobClient, error)
+		wantChain types.Chain
+		wantCreds *types.ApiKeyCreds
+		wantErr   bool
+	}{
+		{
+			name:      "keystore",
+			load:      func() (*ClobClient, error) { return NewClobClientFromKeystore(keystorePath, "hunter2", nil) },
+			wantChain: types.ChainPolygon,
+		},
+		{
+			name:    "keystore with wrong passphrase",
+			load:    func() (*ClobClient, error) { return NewClobClientFromKeystore(keystorePath, "hunter3", nil) },
+			wantErr: true,
+		},
+		{
+			name:      "env",
+			env:       map[string]string{EnvPrivateKey: privateKey, EnvAPIKey: "key", EnvAPISecret: "c2VjcmV0", EnvAPIPassphrase: "pass", EnvChainID: "80002"},
+			load:      func() (*ClobClient, error) { return NewClobClientFromEnv(nil) },
+			wantChain: types.ChainAmoy,
+			wantCreds: creds,
+		},
+		{
+			name:      "env with keystore",
+			env:       map[string]string{EnvKeystore: keystorePath, EnvKeystorePassphrase: "hunter2"},
+			load:      func() (*ClobClient, error) { return NewClobClientFromEnv(&ClientConfig{ChainID: types.ChainAmoy}) },
+			wantChain: types.ChainAmoy,
+		},
+		{
+			name:    "env with invalid chain",
+			env:     map[string]string{EnvPrivateKey: privateKey, EnvChainID: "amoy"},
+			load:    func() (*ClobClient, error) { return NewClobClientFromEnv(nil) },
+			wantErr: true,
+		},
+		{
+			name:      "dotenv",
+			load:      func() (*ClobClient, error) { return NewClobClientFromDotenv(dotenvPath, nil) },
+			wantChain: types.ChainAmoy,
+			wantCreds: creds,
+		},
+		{
+			name:      "env overrides dotenv",
+			env:       map[string]string{EnvChainID: "137"},
+			load:      func() (*ClobClient, error) { return NewClobClientFromDotenv(dotenvPath, nil) },
+			wantChain: types.ChainPolygon,
+			wantCreds: creds,
+		},
+		{
+			name:    "missing dotenv",
+			load:    func() (*ClobClient, error) { return NewClobClientFromDotenv(dotenvPath+".missing", nil) },
+			wantErr: true,
+		},
+		{
+			name:      "credentials file",
+			load:      func() (*ClobClient, error) { return NewClobClientFromCredentialsFile(credentialsPath, nil) },
+			wantChain: types.ChainPolygon,
+			wantCreds: creds,
+		},
+		{
+			name:    "credentials file readable by others",
+			load:    func() (*ClobClient, error) { return NewClobClientFromCredentialsFile(sharedPath, nil) },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			c, err := tt.load()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("loader should fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loader error = %v", err)
+			}
+			if c.chainID != tt.wantChain {
+				t.Errorf("chain = %d, want %d", c.chainID, tt.wantChain)
+			}
+			if c.currentWallet() == nil || c.currentWallet().GetAddress() != wallet.GetAddress() {
+				t.Errorf("wallet = %v, want %s", c.currentWallet(), wallet.GetAddressHex())
+			}
+			if got := c.Credentials(); (got == nil) != (tt.wantCreds == nil) || got != nil && *got != *tt.wantCreds {
+				t.Errorf("Credentials() = %+v, want %+v", got, tt.wantCreds)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := &types.ApiKeyCreds{Key: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	c, err := NewClobClient(&ClientConfig{ChainID: types.ChainAmoy, Wallet: wallet, APIKey: creds})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if wallet.GetPrivateKey().D.Sign() != 0 {
+		t.Error("Close() left the private key in memory")
+	}
+	if c.Credentials() != nil {
+		t.Error("Close() kept the API credentials")
+	}
+	if *creds != (types.ApiKeyCreds{Key: "key", Secret: "c2VjcmV0", Passphrase: "pass"}) {
+		t.Error("Close() modified the configured credentials")
+	}
+	if _, err := c.GetApiKeys(); err == nil {
+		t.Error("GetApiKeys() after Close() should fail")
+	}
+	if _, err := c.DeriveApiKey(nil); err == nil {
+		t.Error("DeriveApiKey() after Close() should fail")
+	}
+}
+
+func TestCloseWaitsForSigners(t *testing.T) {
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewClobClient(&ClientConfig{ChainID: types.ChainAmoy, Wallet: wallet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := &types.ApiKeyCreds{Key: "key", Secret: "c2VjcmV0", Passphrase: "pass"}
+	negRisk, feeRate := false, 0
+
+	// Signers racing Close either sign with the whole key or fail; run with
+	// -race to check that Close does not zero the key under them
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				_, err := c.CreateOrder(
+					&types.UserOrder{TokenID: "1111", Price: 0.5, Size: 10, Side: types.SideBuy, FeeRateBps: &feeRate},
+					&types.CreateOrderOptions{TickSize: types.TickSize001, NegRisk: &negRisk},
+				)
+				if err != nil {
+					return
+				}
+				if _, err := c.createL2Headers(creds, &types.L2HeaderArgs{Method: "GET", RequestPath: GetApiKeys}); err != nil {
+					return
+				}
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	c.Close()
+	wg.Wait()
+}
@@ -0,0 +1,320 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// zeroAddress is the taker of public orders
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// CreateOrder builds and signs a limit order. The tick size and neg risk flag
+// are fetched unless options set them, and the fee rate unless userOrder does.
+// The wallet signs for the configured FunderAddress and SignatureType.
+func (c *ClobClient) CreateOrder(userOrder *types.UserOrder, options *types.CreateOrderOptions) (*types.SignedOrder, error) {
+	wallet := c.currentWallet()
+	if wallet == nil {
+		return nil, fmt.Errorf("wallet is required to create orders")
+	}
+	contracts, err := types.GetContractConfig(c.chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts types.CreateOrderOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.TickSize == "" {
+		if opts.TickSize, err = c.GetTickSize(userOrder.TokenID); err != nil {
+			return nil, fmt.Errorf("failed to get tick size: %w", err)
+		}
+	}
+	if opts.NegRisk == nil {
+		negRisk, err := c.GetNegRisk(userOrder.TokenID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get neg risk: %w", err)
+		}
+		opts.NegRisk = &negRisk
+	}
+	feeRateBps := 0
+	if userOrder.FeeRateBps != nil {
+		feeRateBps = *userOrder.FeeRateBps
+	} else if feeRateBps, err = c.GetFeeRateBps(userOrder.TokenID); err != nil {
+		return nil, fmt.Errorf("failed to get fee rate: %w", err)
+	}
+
+	rounding, err := opts.TickSize.RoundingConfig()
+	if err != nil {
+		return nil, err
+	}
+	tick, _ := strconv.ParseFloat(string(opts.TickSize), 64)
+	if userOrder.Price < tick || userOrder.Price > 1-tick {
+		return nil, fmt.Errorf("invalid price %v, min: %v - max: %v", userOrder.Price, tick, 1-tick)
+	}
+	makerAmount, takerAmount, err := orderAmounts(userOrder.Side, userOrder.Size, userOrder.Price, rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := rand.Int(rand.Reader, big.NewInt(1<<53))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	address := wallet.GetAddressHex()
+	maker := address
+	if c.funder != "" {
+		maker = c.funder
+	}
+	order := &types.SignedOrder{
+		Salt:          salt.String(),
+		Maker:         maker,
+		Signer:        address,
+		Taker:         zeroAddress,
+		TokenID:       userOrder.TokenID,
+		MakerAmount:   makerAmount,
+		TakerAmount:   takerAmount,
+		Expiration:    "0",
+		Nonce:         "0",
+		FeeRateBps:    strconv.Itoa(feeRateBps),
+		Side:          userOrder.Side,
+		SignatureType: c.signatureType,
+	}
+	if userOrder.Taker != "" {
+		order.Taker = userOrder.Taker
+	}
+	if userOrder.Expiration != nil {
+		order.Expiration = strconv.Itoa(*userOrder.Expiration)
+	}
+	if userOrder.Nonce != nil {
+		order.Nonce = strconv.Itoa(*userOrder.Nonce)
+	}
+
+	exchange := contracts.Exchange
+	if *opts.NegRisk {
+		exchange = contracts.NegRiskExchange
+	}
+	err = c.withPrivateKey(func(key *ecdsa.PrivateKey) (err error) {
+		order.Signature, err = auth.SignOrder(key, order, int64(c.chainID), exchange)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// PostOrder submits a signed order
+func (c *ClobClient) PostOrder(order *types.SignedOrder, orderType types.OrderType) (*types.OrderResponse, error) {
+	var result types.OrderResponse
+	err := c.withCreds(func(creds *types.ApiKeyCreds) error {
+		// The owner is the API key, so the body changes with the credentials
+		body, err := json.Marshal(types.NewOrder{
+			Order:     *order,
+			Owner:     creds.Key,
+			OrderType: orderType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal order: %w", err)
+		}
+
+		headers, err := c.createL2Headers(creds, &types.L2HeaderArgs{
+			Method:      "POST",
+			RequestPath: PostOrder,
+			Body:        string(body),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create L2 headers: %w", err)
+		}
+		c.mu.RLock()
+		builder := c.builderConfig
+		c.mu.RUnlock()
+		if builder.IsValid() {
+			bodyStr := string(body)
+			ts := time.Now().Unix()
+			if timestamp, err := c.timestamp(); err != nil {
+				return err
+			} else if timestamp != nil {
+				ts = *timestamp
+			}
+			builderHeaders, err := builder.GenerateBuilderHeadersAt("POST", PostOrder, &bodyStr, ts)
+			if err != nil {
+				return fmt.Errorf("failed to create builder headers: %w", err)
+			}
+			headers = auth.InjectBuilderHeaders(headers.(*types.L2PolyHeader), builderHeaders)
+		}
+
+		return c.postJSONWithHeaders(PostOrder, headers, json.RawMessage(body), &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateAndPostOrder creates, signs and submits a limit order
+func (c *ClobClient) CreateAndPostOrder(userOrder *types.UserOrder, options *types.CreateOrderOptions, orderType types.OrderType) (*types.OrderResponse, error) {
+	order, err := c.CreateOrder(userOrder, options)
+	if err != nil {
+		return nil, err
+	}
+	return c.PostOrder(order, orderType)
+}
+
+// CancelOrder cancels an order by ID
+func (c *ClobClient) CancelOrder(orderID string) (*types.CancelOrdersResponse, error) {
+	return c.cancel(CancelOrder, types.OrderPayload{OrderID: orderID})
+}
+
+// CancelOrders cancels several orders by ID
+func (c *ClobClient) CancelOrders(orderIDs []string) (*types.CancelOrdersResponse, error) {
+	return c.cancel(CancelOrders, orderIDs)
+}
+
+// CancelAll cancels every open order of the API key
+func (c *ClobClient) CancelAll() (*types.CancelOrdersResponse, error) {
+	return c.cancel(CancelAll, nil)
+}
+
+// cancel sends an authenticated DELETE with payload to endpoint
+func (c *ClobClient) cancel(endpoint string, payload interface{}) (*types.CancelOrdersResponse, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("failed to marshal request data: %w", err)
+		}
+	}
+
+	var data interface{}
+	if body != nil {
+		data = json.RawMessage(body)
+	}
+	var result types.CancelOrdersResponse
+	err := c.withCreds(func(creds *types.ApiKeyCreds) error {
+		headers, err := c.createL2Headers(creds, &types.L2HeaderArgs{
+			Method:      "DELETE",
+			RequestPath: endpoint,
+			Body:        string(body),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create L2 headers: %w", err)
+		}
+		return c.deleteJSONWithHeaders(endpoint, headers, data, &result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetOpenOrders gets the open orders of the API key, following every page
+func (c *ClobClient) GetOpenOrders(params *types.OpenOrderParams) ([]types.OpenOrder, error) {
+	queryParams := url.Values{}
+	if params != nil {
+		if params.ID != nil {
+			queryParams.Add("id", *params.ID)
+		}
+		if params.Market != nil {
+			queryParams.Add("market", *params.Market)
+		}
+		if params.AssetID != nil {
+			queryParams.Add("asset_id", *params.AssetID)
+		}
+	}
+
+	var orders []types.OpenOrder
+	nextCursor := types.INITIAL_CURSOR
+	for nextCursor != types.END_CURSOR && nextCursor != "" {
+		queryParams.Set("next_cursor", nextCursor)
+
+		var result struct {
+			Data       []types.OpenOrder `json:"data"`
+			NextCursor string            `json:"next_cursor"`
+		}
+		err := c.withCreds(func(creds *types.ApiKeyCreds) error {
+			headers, err := c.createL2Headers(creds, &types.L2HeaderArgs{
+				Method:      "GET",
+				RequestPath: GetOpenOrders,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create L2 headers: %w", err)
+			}
+			return c.getJSONWithHeadersAndParams(GetOpenOrders, headers, queryParams, &result)
+		})
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, result.Data...)
+		nextCursor = result.NextCursor
+	}
+	return orders, nil
+}
+
+// orderAmounts returns the maker and taker amounts, in on-chain units with 6
+// decimals, of an order for size tokens at price, rounded as the exchange
+// expects: a BUY pays USDC for tokens and a SELL gives tokens for USDC
+func orderAmounts(side types.Side, size, price float64, rounding types.RoundConfig) (*big.Int, *big.Int, error) {
+	price = roundNormal(price, rounding.Price)
+	tokens := roundDown(size, rounding.Size)
+	if tokens <= 0 {
+		return nil, nil, fmt.Errorf("invalid size %v", size)
+	}
+
+	usdc := tokens * price
+	if decimalPlaces(usdc) > rounding.Amount {
+		usdc = roundUp(usdc, rounding.Amount+4)
+		if decimalPlaces(usdc) > rounding.Amount {
+			usdc = roundDown(usdc, rounding.Amount)
+		}
+	}
+
+	switch side {
+	case types.SideBuy:
+		return toTokenDecimals(usdc), toTokenDecimals(tokens), nil
+	case types.SideSell:
+		return toTokenDecimals(tokens), toTokenDecimals(usdc), nil
+	}
+	return nil, nil, fmt.Errorf("invalid order side %q", side)
+}
+
+func roundNormal(x, digits float64) float64 {
+	scale := math.Pow(10, digits)
+	return math.Round(x*scale) / scale
+}
+
+func roundDown(x, digits float64) float64 {
+	scale := math.Pow(10, digits)
+	return math.Floor(x*scale) / scale
+}
+
+func roundUp(x, digits float64) float64 {
+	scale := math.Pow(10, digits)
+	return math.Ceil(x*scale) / scale
+}
+
+// decimalPlaces returns the number of decimals x is printed with
+func decimalPlaces(x float64) float64 {
+	s := strconv.FormatFloat(x, 'f', -1, 64)
+	for i := range s {
+		if s[i] == '.' {
+			return float64(len(s) - i - 1)
+		}
+	}
+	return 0
+}
+
+// toTokenDecimals converts an amount of tokens or USDC to on-chain units
+func toTokenDecimals(x float64) *big.Int {
+	return big.NewInt(int64(math.Round(x * 1e6)))
+}
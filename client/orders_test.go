@@ -0,0 +1,106 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+func TestOrderAmounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		side      types.Side
+		size      float64
+		price     float64
+		tick      types.TickSize
+		wantMaker string
+		wantTaker string
+	}{
+		{name: "buy", side: types.SideBuy, size: 10, price: 0.53, tick: types.TickSize001, wantMaker: "5300000", wantTaker: "10000000"},
+		{name: "sell", side: types.SideSell, size: 10, price: 0.53, tick: types.TickSize001, wantMaker: "10000000", wantTaker: "5300000"},
+		{name: "size rounds down", side: types.SideBuy, size: 12.349, price: 0.45, tick: types.TickSize001, wantMaker: "5553000", wantTaker: "12340000"},
+		{name: "price rounds to tick", side: types.SideBuy, size: 5, price: 0.1234, tick: types.TickSize0001, wantMaker: "615000", wantTaker: "5000000"},
+		{name: "amount keeps tick decimals", side: types.SideSell, size: 33.33, price: 0.333, tick: types.TickSize0001, wantMaker: "33330000", wantTaker: "11098890"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounding, err := tt.tick.RoundingConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			maker, taker, err := orderAmounts(tt.side, tt.size, tt.price, rounding)
+			if err != nil {
+				t.Fatalf("orderAmounts() error = %v", err)
+			}
+			if maker.String() != tt.wantMaker || taker.String() != tt.wantTaker {
+				t.Errorf("orderAmounts() = %s, %s, want %s, %s", maker, taker, tt.wantMaker, tt.wantTaker)
+			}
+		})
+	}
+
+	if _, _, err := orderAmounts(types.SideBuy, 0.001, 0.5, types.RoundConfig{Price: 2, Size: 2, Amount: 4}); err == nil {
+		t.Error("orderAmounts() with a size that rounds to 0 should fail")
+	}
+}
+
+func TestCreateOrderSignsForFunder(t *testing.T) {
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	funder := "0x1111111111111111111111111111111111111111"
+	negRisk, feeRate := false, 0
+	contracts, _ := types.GetContractConfig(types.ChainAmoy)
+
+	tests := []struct {
+		name          string
+		config        ClientConfig
+		wantMaker     string
+		wantSignature types.SignatureType
+	}{
+		{name: "eoa", config: ClientConfig{}, wantMaker: wallet.GetAddressHex(), wantSignature: types.SignatureTypeEIP712},
+		{name: "proxy", config: ClientConfig{SignatureType: types.SignatureTypePolyProxy, FunderAddress: funder}, wantMaker: funder, wantSignature: types.SignatureTypePolyProxy},
+		{name: "safe", config: ClientConfig{SignatureType: types.SignatureTypePolyGnosisSafe, FunderAddress: funder}, wantMaker: funder, wantSignature: types.SignatureTypePolyGnosisSafe},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ChainID = types.ChainAmoy
+			tt.config.Wallet = wallet
+			c, err := NewClobClient(&tt.config)
+			if err != nil {
+				t.Fatalf("NewClobClient() error = %v", err)
+			}
+			order, err := c.CreateOrder(
+				&types.UserOrder{TokenID: "1111", Price: 0.5, Size: 10, Side: types.SideBuy, FeeRateBps: &feeRate},
+				&types.CreateOrderOptions{TickSize: types.TickSize001, NegRisk: &negRisk},
+			)
+			if err != nil {
+				t.Fatalf("CreateOrder() error = %v", err)
+			}
+			if order.Maker != tt.wantMaker || order.Signer != wallet.GetAddressHex() || order.SignatureType != tt.wantSignature {
+				t.Errorf("maker %s signer %s signature type %d", order.Maker, order.Signer, order.SignatureType)
+			}
+
+			// The wallet signs the order with the funder as maker
+			hash, err := auth.OrderHash(order, int64(types.ChainAmoy), contracts.Exchange)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signer, err := auth.RecoverAddress(hash, order.Signature); err != nil || signer != wallet.GetAddress() {
+				t.Errorf("signature recovers %s, %v", signer.Hex(), err)
+			}
+		})
+	}
+
+	for _, config := range []ClientConfig{
+		{SignatureType: types.SignatureTypePolyProxy},
+		{FunderAddress: "not an address"},
+	} {
+		if _, err := NewClobClient(&config); err == nil {
+			t.Errorf("NewClobClient(%+v) should fail", config)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// recordingMagic identifies a WebSocket frame recording file
+const recordingMagic = "PMWSREC1"
+
+// maxRecordedFrameSize guards the reader against corrupt length prefixes
+const maxRecordedFrameSize = 64 << 20
+
+// Recording format: the magic header followed by one record per frame.
+// Each record is the receive time as big-endian int64 Unix nanoseconds,
+// the frame length as a uvarint and the raw frame bytes.
+
+// RecordedFrame is a raw WebSocket frame together with its receive time
+type RecordedFrame struct {
+	ReceivedAt time.Time
+	Data       []byte
+}
+
+// FrameRecorder appends raw WebSocket frames to a recording
+type FrameRecorder struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+}
+
+// NewFrameRecorder opens path for appending, creating it if needed. An
+// existing recording is read through first: a last record cut short by a
+// crash is truncated away so new frames follow a complete one, and a
+// recording corrupt elsewhere is refused.
+func NewFrameRecorder(path string) (*FrameRecorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat recording: %w", err)
+	}
+
+	if info.Size() > 0 {
+		header := make([]byte, len(recordingMagic))
+		if _, err := f.ReadAt(header, 0); err != nil || string(header) != recordingMagic {
+			f.Close()
+			return nil, fmt.Errorf("%s is not a frame recording", path)
+		}
+		end, err := completeLength(io.NewSectionReader(f, 0, info.Size()))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("cannot append to corrupt recording %s: %w", path, err)
+		}
+		if end < info.Size() {
+			if err := f.Truncate(end); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("failed to truncate the partial last frame: %w", err)
+			}
+		}
+		return &FrameRecorder{w: bufio.NewWriter(f), closer: f}, nil
+	}
+
+	recorder := &FrameRecorder{w: bufio.NewWriter(f), closer: f}
+	if err := recorder.writeHeader(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to write recording header: %w", err)
+	}
+	return recorder, nil
+}
+
+// completeLength returns the length of the header and the complete records of
+// a recording, which ends with a truncated record after a crash
+func completeLength(r io.Reader) (int64, error) {
+	reader, err := NewFrameReader(r)
+	if err != nil {
+		return 0, err
+	}
+	end := int64(len(recordingMagic))
+	for {
+		frame, err := reader.Next()
+		switch {
+		case err == io.EOF:
+			return end, nil
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			return end, nil
+		case err != nil:
+			return 0, err
+		}
+		var size [binary.MaxVarintLen64]byte
+		end += 8 + int64(binary.PutUvarint(size[:], uint64(len(frame.Data)))) + int64(len(frame.Data))
+	}
+}
+
+// NewFrameRecorderWriter records into w, writing the header first
+func NewFrameRecorderWriter(w io.Writer) (*FrameRecorder, error) {
+	recorder := &FrameRecorder{w: bufio.NewWriter(w)}
+	if err := recorder.writeHeader(); err != nil {
+		return nil, fmt.Errorf("failed to write recording header: %w", err)
+	}
+	return recorder, nil
+}
+
+func (r *FrameRecorder) writeHeader() error {
+	if _, err := r.w.WriteString(recordingMagic); err != nil {
+		return err
+	}
+	return r.w.Flush()
+}
+
+// Record appends a frame received at t. Every frame is flushed to the
+// underlying writer, so a crash loses at most the frame being written.
+func (r *FrameRecorder) Record(t time.Time, frame []byte) error {
+	var header [8 + binary.MaxVarintLen64]byte
+	binary.BigEndian.PutUint64(header[:8], uint64(t.UnixNano()))
+	n := binary.PutUvarint(header[8:], uint64(len(frame)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.w.Write(header[:8+n]); err != nil {
+		return err
+	}
+	if _, err := r.w.Write(frame); err != nil {
+		return err
+	}
+	return r.w.Flush()
+}
+
+// Flush writes buffered data to the underlying writer
+func (r *FrameRecorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Flush()
+}
+
+// Close flushes buffered frames and closes the file opened by NewFrameRecorder
+func (r *FrameRecorder) Close() error {
+	err := r.Flush()
+	if r.closer != nil {
+		if closeErr := r.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// FrameReader reads frames from a recording
+type FrameReader struct {
+	r *bufio.Reader
+}
+
+// NewFrameReader validates the recording header and returns a reader positioned at the first frame
+func NewFrameReader(r io.Reader) (*FrameReader, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("failed to read recording header: %w", err)
+	}
+	if string(header) != recordingMagic {
+		return nil, fmt.Errorf("not a frame recording")
+	}
+	return &FrameReader{r: br}, nil
+}
+
+// Next returns the next frame, or io.EOF at the end of the recording
+func (fr *FrameReader) Next() (*RecordedFrame, error) {
+	var ts [8]byte
+	if _, err := io.ReadFull(fr.r, ts[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("truncated frame timestamp: %w", err)
+		}
+		return nil, err
+	}
+
+	size, err := binary.ReadUvarint(fr.r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read frame length: %w", err)
+	}
+	if size > maxRecordedFrameSize {
+		return nil, fmt.Errorf("frame length %d exceeds limit", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(fr.r, data); err != nil {
+		return nil, fmt.Errorf("truncated frame: %w", err)
+	}
+
+	return &RecordedFrame{
+		ReceivedAt: time.Unix(0, int64(binary.BigEndian.Uint64(ts[:]))),
+		Data:       data,
+	}, nil
+}
+
+// ReplayOptions controls the pace of a replay
+type ReplayOptions struct {
+	// Speed multiplies the original pace: 1 replays in real time, 10 ten times
+	// faster. 0 replays as fast as possible.
+	Speed float64
+}
+
+// Replay feeds a recording through the same parsing and dispatch path as a
+// live connection, so callbacks, Messages and All behave exactly as they do
+// on live data. The client stops when the recording ends, which closes
+// Messages and releases Wait. Replay must not be used on a connected client.
+func (ws *WebSocketClient) Replay(ctx context.Context, r io.Reader, options *ReplayOptions) error {
+	ws.mu.Lock()
+	ws.started = true
+	ws.mu.Unlock()
+	err := ws.replay(ctx, r, options)
+	ws.stop(err)
+	return err
+}
+
+// ReplayFile replays the recording stored at path
+func (ws *WebSocketClient) ReplayFile(ctx context.Context, path string, options *ReplayOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		ws.stop(err)
+		return fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+
+	return ws.Replay(ctx, f, options)
+}
+
+func (ws *WebSocketClient) replay(ctx context.Context, r io.Reader, options *ReplayOptions) error {
+	if ws.IsConnected() {
+		return fmt.Errorf("cannot replay on a connected client")
+	}
+
+	speed := 0.0
+	if options != nil {
+		speed = options.Speed
+	}
+
+	reader, err := NewFrameReader(r)
+	if err != nil {
+		return err
+	}
+
+	var first time.Time
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if speed > 0 {
+			if first.IsZero() {
+				first = frame.ReceivedAt
+			}
+			due := start.Add(time.Duration(float64(frame.ReceivedAt.Sub(first)) / speed))
+			if wait := time.Until(due); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ws.processMessage(frame.Data)
+	}
+}
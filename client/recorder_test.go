@@ -0,0 +1,214 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// recording returns a recording of frames received gap apart
+func recording(t *testing.T, gap time.Duration, frames ...[]byte) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	recorder, err := NewFrameRecorderWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1700000000, 0)
+	for i, frame := range frames {
+		if err := recorder.Record(start.Add(time.Duration(i)*gap), frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestFrameRecorderRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frames.rec")
+	recorder, err := NewFrameRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1700000000, 0)
+	for i := range 2 {
+		if err := recorder.Record(start.Add(time.Duration(i)*time.Second), bookFrame(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Frames reach the file without Flush or Close, so a crash keeps them
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reader, err := NewFrameReader(f)
+	if err != nil {
+		t.Fatalf("NewFrameReader() error = %v", err)
+	}
+	for i := range 2 {
+		frame, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Next() frame %d error = %v", i, err)
+		}
+		if !frame.ReceivedAt.Equal(start.Add(time.Duration(i)*time.Second)) || string(frame.Data) != string(bookFrame(i)) {
+			t.Errorf("frame %d = %s at %v", i, frame.Data, frame.ReceivedAt)
+		}
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening appends after the existing frames
+	recorder, err = NewFrameRecorder(path)
+	if err != nil {
+		t.Fatalf("NewFrameRecorder() on an existing recording error = %v", err)
+	}
+	if err := recorder.Record(start.Add(2*time.Second), bookFrame(2)); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+
+	ws := NewWebSocketClient(nil, nil)
+	messages := ws.Messages()
+	go ws.ReplayFile(context.Background(), path, nil)
+	if got := hashes(t, messages); strings.Join(got, ",") != "0,1,2" {
+		t.Errorf("replayed %v, want 0,1,2", got)
+	}
+	if err := ws.Wait(); err != nil {
+		t.Errorf("Wait() after replay = %v", err)
+	}
+}
+
+func TestFrameRecorderAppendsAfterTruncation(t *testing.T) {
+	// A crash cut the second frame short at each of its parts
+	complete := recording(t, time.Millisecond, bookFrame(0)).Bytes()
+	data := recording(t, time.Millisecond, bookFrame(0), bookFrame(1)).Bytes()
+	for _, cut := range []int{len(complete) + 3, len(complete) + 8, len(data) - 5} {
+		t.Run(fmt.Sprint(cut), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "frames.rec")
+			if err := os.WriteFile(path, data[:cut], 0o644); err != nil {
+				t.Fatal(err)
+			}
+			recorder, err := NewFrameRecorder(path)
+			if err != nil {
+				t.Fatalf("NewFrameRecorder() error = %v", err)
+			}
+			if err := recorder.Record(time.Unix(1700000000, 0), bookFrame(2)); err != nil {
+				t.Fatal(err)
+			}
+			recorder.Close()
+
+			ws := NewWebSocketClient(nil, nil)
+			messages := ws.Messages()
+			go ws.ReplayFile(context.Background(), path, nil)
+			if got := hashes(t, messages); strings.Join(got, ",") != "0,2" {
+				t.Errorf("replayed %v, want 0,2", got)
+			}
+			if err := ws.Wait(); err != nil {
+				t.Errorf("Wait() after replay = %v", err)
+			}
+		})
+	}
+
+	// A corrupt frame is not a crash and is not truncated away
+	path := filepath.Join(t.TempDir(), "corrupt.rec")
+	os.WriteFile(path, append(slices.Clone(data), 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0x7f), 0o644)
+	if _, err := NewFrameRecorder(path); err == nil {
+		t.Error("NewFrameRecorder() on a corrupt recording should fail")
+	}
+}
+
+func TestReplaySpeed(t *testing.T) {
+	// Three frames 100ms apart span 200ms
+	data := recording(t, 100*time.Millisecond, bookFrame(0), bookFrame(1), bookFrame(2)).Bytes()
+
+	tests := []struct {
+		speed    float64
+		min, max time.Duration
+	}{
+		{speed: 0, max: 50 * time.Millisecond},
+		{speed: 10, min: 20 * time.Millisecond, max: 150 * time.Millisecond},
+		{speed: 1, min: 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		ws := NewWebSocketClient(nil, nil)
+		count := 0
+		ws.On(&WebSocketCallbacks{OnMessage: func(types.MarketChannelMessage) { count++ }})
+		begin := time.Now()
+		err := ws.Replay(context.Background(), bytes.NewReader(data), &ReplayOptions{Speed: tt.speed})
+		elapsed := time.Since(begin)
+		if err != nil || count != 3 {
+			t.Fatalf("Replay(speed %v) = %v after %d messages, want 3", tt.speed, err, count)
+		}
+		if elapsed < tt.min || (tt.max > 0 && elapsed > tt.max) {
+			t.Errorf("Replay(speed %v) took %v, want between %v and %v", tt.speed, elapsed, tt.min, tt.max)
+		}
+	}
+
+	// Cancelling stops a paced replay while it waits for the next frame
+	ws := NewWebSocketClient(nil, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := ws.Replay(ctx, bytes.NewReader(data), &ReplayOptions{Speed: 1}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Replay() with a canceled context = %v", err)
+	}
+	if err := ws.Wait(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() after a canceled replay = %v", err)
+	}
+}
+
+func TestReplayRejectsCorruptRecordings(t *testing.T) {
+	data := recording(t, time.Millisecond, bookFrame(0), bookFrame(1)).Bytes()
+
+	tests := []struct {
+		name      string
+		data      []byte
+		wantCount int
+		wantErr   string
+	}{
+		{name: "bad magic", data: append([]byte("NOTAREC1"), data[len(recordingMagic):]...), wantErr: "not a frame recording"},
+		{name: "short header", data: data[:4], wantErr: "failed to read recording header"},
+		{name: "truncated frame", data: data[:len(data)-1], wantCount: 1, wantErr: "truncated frame"},
+		{name: "truncated timestamp", data: append(slices.Clone(data), 0, 1, 2), wantCount: 2, wantErr: "truncated frame timestamp"},
+		{name: "oversized frame", data: append(slices.Clone(data), 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0x7f), wantCount: 2, wantErr: "exceeds limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := NewWebSocketClient(nil, nil)
+			count := 0
+			ws.On(&WebSocketCallbacks{OnMessage: func(types.MarketChannelMessage) { count++ }})
+			err := ws.Replay(context.Background(), bytes.NewReader(tt.data), nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Replay() = %v, want %q", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("dispatched %d messages before the error, want %d", count, tt.wantCount)
+			}
+			if werr := ws.Wait(); werr != err {
+				t.Errorf("Wait() = %v, want the replay error", werr)
+			}
+		})
+	}
+
+	// A file that is not a recording is not appended to
+	path := filepath.Join(t.TempDir(), "other.txt")
+	os.WriteFile(path, []byte("hello"), 0o644)
+	if _, err := NewFrameRecorder(path); err == nil {
+		t.Error("NewFrameRecorder() on a file without the header should fail")
+	}
+}
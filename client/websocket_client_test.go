@@ -0,0 +1,331 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// bookFrame returns a book message whose hash is the frame's index
+func bookFrame(i int) []byte {
+	return fmt.Appendf(nil, `{"event_type":"book","asset_id":"1","market":"0xm","bids":[],"asks":[],"timestamp":"%d","hash":"%d"}`, 1700000000000+i, i)
+}
+
+// hashes drains messages until the channel closes and returns the book hashes
+func hashes(t *testing.T, messages <-chan types.MarketChannelMessage) []string {
+	t.Helper()
+	var got []string
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return got
+			}
+			got = append(got, msg.(*types.BookMessage).Hash)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out after %v waiting for the channel to close", got)
+		}
+	}
+}
+
+func TestOverflowPolicies(t *testing.T) {
+	frames := [][]byte{bookFrame(0), bookFrame(1), bookFrame(2)}
+
+	tests := []struct {
+		name        string
+		policy      OverflowPolicy
+		consume     bool // read while receiving instead of after
+		wantHashes  []string
+		wantDropped uint64
+		wantErr     error
+	}{
+		{name: "block", policy: OverflowBlock, consume: true, wantHashes: []string{"0", "1", "2"}},
+		{name: "drop oldest", policy: OverflowDropOldest, wantHashes: []string{"2"}, wantDropped: 2},
+		{name: "disconnect", policy: OverflowDisconnect, wantHashes: []string{"0"}, wantErr: ErrSlowConsumer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := NewWebSocketClient(nil, &WebSocketClientOptions{MessageBufferSize: 1, OverflowPolicy: tt.policy})
+			messages := ws.Messages()
+
+			// Feed the frames the way the read loop would, then end the session
+			received := make(chan struct{})
+			go func() {
+				defer close(received)
+				for _, frame := range frames {
+					ws.processMessage(frame)
+				}
+				ws.Disconnect()
+			}()
+			if !tt.consume {
+				<-received
+			}
+
+			if got := hashes(t, messages); fmt.Sprint(got) != fmt.Sprint(tt.wantHashes) {
+				t.Errorf("messages = %v, want %v", got, tt.wantHashes)
+			}
+			<-received
+			if got := ws.DroppedMessages(); got != tt.wantDropped {
+				t.Errorf("DroppedMessages() = %d, want %d", got, tt.wantDropped)
+			}
+			if err := ws.Wait(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Wait() = %v, want %v", err, tt.wantErr)
+			}
+			if err := ws.Err(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Err() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStoppedClient(t *testing.T) {
+	ws := NewWebSocketClient(nil, nil)
+	if err := ws.Err(); err != nil {
+		t.Errorf("Err() before stopping = %v, want nil", err)
+	}
+	ws.Disconnect()
+
+	if err := ws.Wait(); err != nil {
+		t.Errorf("Wait() after Disconnect = %v, want nil", err)
+	}
+	if state := ws.State(); state != StateStopped {
+		t.Errorf("State() = %v, want %v", state, StateStopped)
+	}
+	// A stream requested after the stop is closed rather than left open
+	if got := hashes(t, ws.Messages()); len(got) != 0 {
+		t.Errorf("Messages() after stop delivered %v", got)
+	}
+	for range ws.All() {
+		t.Error("All() yielded after stop")
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  WebSocketClientOptions
+		attempt  int
+		min, max time.Duration
+	}{
+		{name: "first", options: WebSocketClientOptions{ReconnectDelay: time.Second, ReconnectJitter: -1}, attempt: 1, min: time.Second, max: time.Second},
+		{name: "doubled", options: WebSocketClientOptions{ReconnectDelay: time.Second, ReconnectJitter: -1}, attempt: 4, min: 8 * time.Second, max: 8 * time.Second},
+		{name: "capped", options: WebSocketClientOptions{ReconnectDelay: time.Second, ReconnectJitter: -1}, attempt: 30, min: time.Minute, max: time.Minute},
+		{name: "custom cap", options: WebSocketClientOptions{ReconnectDelay: time.Second, MaxReconnectDelay: 3 * time.Second, ReconnectJitter: -1}, attempt: 5, min: 3 * time.Second, max: 3 * time.Second},
+		{name: "jitter", options: WebSocketClientOptions{ReconnectDelay: 10 * time.Second, ReconnectJitter: 0.5}, attempt: 1, min: 5 * time.Second, max: 15 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.options
+			ws := NewWebSocketClient(nil, &options)
+			for range 20 {
+				if got := ws.reconnectDelay(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("reconnectDelay(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestDisconnectHandling(t *testing.T) {
+	var transitions []string
+	ws := NewWebSocketClient(nil, &WebSocketClientOptions{AutoReconnect: true, MaxReconnectAttempts: 2})
+	ws.On(&WebSocketCallbacks{OnStateChange: func(from, to ConnectionState) {
+		transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+	}})
+	ws.setState(StateConnected)
+
+	// A read loop of an earlier session ending late leaves the client alone
+	ws.handleDisconnect(ws.session+1, 1006, "", io.EOF)
+	if err := ws.Err(); err != nil || ws.State() != StateConnected {
+		t.Errorf("after a stale disconnect: state %v, Err() = %v", ws.State(), err)
+	}
+
+	// With every attempt used up the client gives up instead of reconnecting
+	ws.reconnectAttempts = 2
+	ws.handleDisconnect(ws.session, 1006, "", io.EOF)
+	if err := ws.Wait(); !errors.Is(err, ErrMaxReconnectAttempts) {
+		t.Errorf("Wait() = %v, want ErrMaxReconnectAttempts", err)
+	}
+	if got := fmt.Sprint(transitions); got != "[disconnected->connected connected->disconnected disconnected->stopped]" {
+		t.Errorf("transitions = %s", got)
+	}
+}
+
+// wsServer is a WebSocket server that records every subscription it receives,
+// answers it with one book frame and then closes the first connection
+type wsServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	paths         []string
+	subscriptions []map[string]any
+}
+
+func newWSServer(t *testing.T) *wsServer {
+	s := &wsServer{}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var sub map[string]any
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.paths = append(s.paths, r.URL.Path)
+		s.subscriptions = append(s.subscriptions, sub)
+		n := len(s.subscriptions)
+		s.mu.Unlock()
+
+		if conn.WriteMessage(websocket.TextMessage, bookFrame(n-1)) != nil || n == 1 {
+			return
+		}
+		for {
+			if _, data, err := conn.ReadMessage(); err != nil {
+				return
+			} else if string(data) == "PING" {
+				conn.WriteMessage(websocket.TextMessage, []byte("PONG"))
+			}
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *wsServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func TestMarketChannelReconnects(t *testing.T) {
+	server := newWSServer(t)
+
+	reconnects := 0
+	ws := NewWebSocketClient(nil, &WebSocketClientOptions{
+		URL:            server.url(),
+		AssetIDs:       []string{"1"},
+		AutoReconnect:  true,
+		ReconnectDelay: 10 * time.Millisecond,
+	})
+	ws.On(&WebSocketCallbacks{OnReconnect: func(int) { reconnects++ }})
+	messages := ws.Messages()
+
+	// The market channel is public, so no CLOB client is needed
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	for i := range 2 {
+		select {
+		case msg := <-messages:
+			if hash := msg.(*types.BookMessage).Hash; hash != fmt.Sprint(i) {
+				t.Errorf("message %d has hash %s", i, hash)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+	ws.Disconnect()
+	if err := ws.Wait(); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+
+	// The client resubscribed to the same assets after the server dropped it
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if reconnects != 1 || len(server.subscriptions) != 2 {
+		t.Fatalf("reconnected %d times with %d subscriptions, want 1 and 2", reconnects, len(server.subscriptions))
+	}
+	for i, sub := range server.subscriptions {
+		if server.paths[i] != "/ws/market" || sub["type"] != "market" || fmt.Sprint(sub["assets_ids"]) != "[1]" || sub["auth"] != nil {
+			t.Errorf("subscription %d on %s = %v", i, server.paths[i], sub)
+		}
+	}
+}
+
+func TestUserChannelCredentials(t *testing.T) {
+	server := newWSServer(t)
+
+	// Without a CLOB client the user channel fails before dialing
+	ws := NewWebSocketClient(nil, &WebSocketClientOptions{URL: server.url(), Channel: ChannelUser})
+	if err := ws.Connect(); err == nil {
+		t.Error("Connect() to the user channel without a CLOB client should fail")
+	}
+
+	// Credentials the CLOB client holds are sent without deriving new ones
+	creds := &types.ApiKeyCreds{Key: "key", Secret: "secret", Passphrase: "pass"}
+	clob, err := NewClobClient(&ClientConfig{APIKey: creds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws = NewWebSocketClient(clob, &WebSocketClientOptions{URL: server.url(), Channel: ChannelUser, Markets: []string{"0xm"}})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	ws.Wait()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.subscriptions) != 1 || server.paths[0] != "/ws/user" {
+		t.Fatalf("subscriptions %v on %v, want one on /ws/user", server.subscriptions, server.paths)
+	}
+	sub := server.subscriptions[0]
+	if fmt.Sprint(sub["auth"]) != "map[apiKey:key passphrase:pass secret:secret]" || fmt.Sprint(sub["markets"]) != "[0xm]" {
+		t.Errorf("user subscription = %v", sub)
+	}
+}
+
+func TestConnectFailureStopsClient(t *testing.T) {
+	// A listener closed at once leaves a port that refuses connections
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "ws://" + listener.Addr().String()
+	listener.Close()
+
+	ws := NewWebSocketClient(nil, &WebSocketClientOptions{URL: url, AutoReconnect: true})
+	if err := ws.Wait(); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("Wait() before Connect = %v, want ErrNotStarted", err)
+	}
+	messages := ws.Messages()
+
+	dialErr := ws.Connect()
+	if dialErr == nil {
+		t.Fatal("Connect() to a closed port should fail")
+	}
+	if err := ws.Wait(); err != dialErr {
+		t.Errorf("Wait() = %v, want the dial error %v", err, dialErr)
+	}
+	if got := hashes(t, messages); len(got) != 0 {
+		t.Errorf("Messages() delivered %v", got)
+	}
+	if state := ws.State(); state != StateStopped {
+		t.Errorf("State() = %v, want %v", state, StateStopped)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ws := NewWebSocketClient(nil, &WebSocketClientOptions{AssetIDs: []string{"1", "2", "3"}})
+	ws.Unsubscribe([]string{"1"})
+	if err := ws.UnsubscribeErr([]string{"3"}); err != nil {
+		t.Errorf("UnsubscribeErr() while disconnected = %v", err)
+	}
+	if got := fmt.Sprint(ws.options.AssetIDs); got != "[2]" {
+		t.Errorf("asset IDs = %s, want [2]", got)
+	}
+}
@@ -0,0 +1,578 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/client"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/gamma"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// parseFlags parses the flags of a subcommand, reporting errors as errUsage
+func (c *cli) parseFlags(name string, args []string, define func(*flag.FlagSet)) (*flag.FlagSet, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = flags.PrintDefaults
+	if define != nil {
+		define(flags)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, errUsage
+	}
+	return flags, nil
+}
+
+// searchResult is one outcome of a market found by markets search
+type searchResult struct {
+	Event       string `json:"event"`
+	Question    string `json:"question"`
+	ConditionID string `json:"conditionId"`
+	Outcome     string `json:"outcome"`
+	Price       string `json:"price"`
+	TokenID     string `json:"tokenId"`
+	Closed      bool   `json:"closed"`
+}
+
+func runMarkets(c *cli, args []string) error {
+	if len(args) == 0 || args[0] != "search" {
+		return errUsage
+	}
+	var limit *int
+	flags, err := c.parseFlags("markets search", args[1:], func(flags *flag.FlagSet) {
+		limit = flags.Int("limit", 10, "events per page")
+	})
+	if err != nil {
+		return err
+	}
+	query := strings.Join(flags.Args(), " ")
+	if query == "" {
+		return errUsage
+	}
+
+	sdk, err := c.gamma()
+	if err != nil {
+		return err
+	}
+	resp, err := sdk.Search(gamma.SearchQuery{Q: &query, LimitPerType: limit})
+	if err != nil {
+		return err
+	}
+
+	results := []searchResult{}
+	for _, event := range resp.Events {
+		for _, market := range event.Markets {
+			for i, outcome := range market.Outcomes {
+				result := searchResult{
+					Event:       event.Title,
+					Question:    market.Question,
+					ConditionID: market.ConditionID,
+					Outcome:     outcome,
+					Closed:      market.Closed,
+				}
+				if i < len(market.OutcomePrices) {
+					result.Price = market.OutcomePrices[i]
+				}
+				if i < len(market.ClobTokenIDs) {
+					result.TokenID = market.ClobTokenIDs[i]
+				}
+				results = append(results, result)
+			}
+		}
+	}
+
+	return c.out.print(results, func() *table {
+		t := &table{header: []string{"QUESTION", "OUTCOME", "PRICE", "CLOSED", "TOKEN ID"}}
+		for _, r := range results {
+			t.add(truncate(r.Question, 60), r.Outcome, r.Price, strconv.FormatBool(r.Closed), r.TokenID)
+		}
+		return t
+	})
+}
+
+func runBook(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	clob, err := c.clob()
+	if err != nil {
+		return err
+	}
+	book, err := clob.GetOrderBook(args[0])
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(book, func() *table {
+		// Asks above bids, both from the highest price down, so that the
+		// spread is in the middle
+		t := &table{header: []string{"SIDE", "PRICE", "SIZE"}}
+		for _, level := range byPriceDescending(book.Asks) {
+			t.add("ask", level.Price, level.Size)
+		}
+		for _, level := range byPriceDescending(book.Bids) {
+			t.add("bid", level.Price, level.Size)
+		}
+		return t
+	})
+}
+
+func byPriceDescending(levels []types.OrderSummary) []types.OrderSummary {
+	sorted := append([]types.OrderSummary(nil), levels...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, _ := strconv.ParseFloat(sorted[i].Price, 64)
+		b, _ := strconv.ParseFloat(sorted[j].Price, 64)
+		return a > b
+	})
+	return sorted
+}
+
+// tokenPrice is the quote of one token
+type tokenPrice struct {
+	TokenID   string `json:"tokenId"`
+	Bid       string `json:"bid"`
+	Ask       string `json:"ask"`
+	Midpoint  string `json:"midpoint"`
+	LastTrade string `json:"lastTrade"`
+}
+
+func runPrice(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	clob, err := c.clob()
+	if err != nil {
+		return err
+	}
+
+	prices := make([]tokenPrice, 0, len(args))
+	for _, tokenID := range args {
+		bid, err := clob.GetPrice(tokenID, types.SideBuy)
+		if err != nil {
+			return err
+		}
+		ask, err := clob.GetPrice(tokenID, types.SideSell)
+		if err != nil {
+			return err
+		}
+		mid, err := clob.GetMidpoint(tokenID)
+		if err != nil {
+			return err
+		}
+		last, err := clob.GetLastTradePrice(tokenID)
+		if err != nil {
+			return err
+		}
+		prices = append(prices, tokenPrice{
+			TokenID:   tokenID,
+			Bid:       field(bid, "price"),
+			Ask:       field(ask, "price"),
+			Midpoint:  field(mid, "mid"),
+			LastTrade: field(last, "price"),
+		})
+	}
+
+	return c.out.print(prices, func() *table {
+		t := &table{header: []string{"TOKEN ID", "BID", "ASK", "MIDPOINT", "LAST TRADE"}}
+		for _, p := range prices {
+			t.add(p.TokenID, p.Bid, p.Ask, p.Midpoint, p.LastTrade)
+		}
+		return t
+	})
+}
+
+// field returns the key of a decoded JSON object as a string
+func field(v interface{}, key string) string {
+	object, ok := v.(map[string]interface{})
+	if !ok || object[key] == nil {
+		return ""
+	}
+	return fmt.Sprint(object[key])
+}
+
+// user returns the -user flag, or the address of the configured wallet
+func (c *cli) user(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if !c.config.hasWallet() {
+		return "", fmt.Errorf("-user, POLYMARKET_PRIVATE_KEY or POLYMARKET_KEYSTORE is required")
+	}
+	wallet, err := c.config.wallet()
+	if err != nil {
+		return "", err
+	}
+	return wallet.GetAddressHex(), nil
+}
+
+func runPositions(c *cli, args []string) error {
+	var user *string
+	var limit *int
+	if _, err := c.parseFlags("positions", args, func(flags *flag.FlagSet) {
+		user = flags.String("user", "", "wallet address (default the configured wallet)")
+		limit = flags.Int("limit", 100, "maximum number of positions")
+	}); err != nil {
+		return err
+	}
+	address, err := c.user(*user)
+	if err != nil {
+		return err
+	}
+
+	sdk, err := c.data()
+	if err != nil {
+		return err
+	}
+	positions, err := sdk.GetCurrentPositions(&data.PositionsQuery{User: &address, Limit: limit})
+	if err != nil {
+		return err
+	}
+	if positions == nil {
+		positions = []data.Position{}
+	}
+
+	return c.out.print(positions, func() *table {
+		t := &table{header: []string{"MARKET", "OUTCOME", "SIZE", "AVG PRICE", "PRICE", "VALUE", "PNL"}}
+		for _, p := range positions {
+			t.add(truncate(p.Title, 50), p.Outcome, formatFloat(p.Size), formatFloat(p.AvgPrice),
+				formatFloat(p.CurPrice), formatFloat(p.CurrentValue), formatFloat(p.CashPnl))
+		}
+		return t
+	})
+}
+
+func runActivity(c *cli, args []string) error {
+	var user *string
+	var limit *int
+	if _, err := c.parseFlags("activity", args, func(flags *flag.FlagSet) {
+		user = flags.String("user", "", "wallet address (default the configured wallet)")
+		limit = flags.Int("limit", 50, "maximum number of entries")
+	}); err != nil {
+		return err
+	}
+	address, err := c.user(*user)
+	if err != nil {
+		return err
+	}
+
+	sdk, err := c.data()
+	if err != nil {
+		return err
+	}
+	activity, err := sdk.GetUserActivity(&data.UserActivityQuery{User: &address, Limit: limit})
+	if err != nil {
+		return err
+	}
+	if activity == nil {
+		activity = []data.Activity{}
+	}
+
+	return c.out.print(activity, func() *table {
+		t := &table{header: []string{"TIME", "TYPE", "SIDE", "SIZE", "USDC", "PRICE", "MARKET"}}
+		for _, a := range activity {
+			price := ""
+			if a.Price != nil {
+				price = formatFloat(*a.Price)
+			}
+			t.add(time.Unix(a.Timestamp, 0).UTC().Format(time.DateTime), string(a.Type), string(a.Side),
+				formatFloat(a.Size), formatFloat(a.UsdcSize), price, truncate(a.Title, 50))
+		}
+		return t
+	})
+}
+
+func runKeys(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	printCreds := func(creds *types.ApiKeyCreds) error {
+		return c.out.print(creds, func() *table {
+			t := &table{header: []string{"KEY", "SECRET", "PASSPHRASE"}}
+			t.add(creds.Key, creds.Secret, creds.Passphrase)
+			return t
+		})
+	}
+
+	switch args[0] {
+	case "create", "derive":
+		if !c.config.hasWallet() {
+			return fmt.Errorf("POLYMARKET_PRIVATE_KEY or POLYMARKET_KEYSTORE is required")
+		}
+		clob, err := c.clob()
+		if err != nil {
+			return err
+		}
+		var creds *types.ApiKeyCreds
+		nonce := c.config.APIKeyNonce
+		if args[0] == "create" {
+			creds, err = clob.CreateApiKey(&nonce)
+		} else {
+			creds, err = clob.DeriveApiKey(&nonce)
+		}
+		if err != nil {
+			return err
+		}
+		return printCreds(creds)
+
+	case "list":
+		clob, err := c.authenticated()
+		if err != nil {
+			return err
+		}
+		keys, err := clob.GetApiKeys()
+		if err != nil {
+			return err
+		}
+		return c.out.print(keys, func() *table {
+			t := &table{header: []string{"KEY"}}
+			for _, key := range keys.APIKeys {
+				t.add(key)
+			}
+			return t
+		})
+
+	case "rotate":
+		if !c.config.hasWallet() {
+			return fmt.Errorf("POLYMARKET_PRIVATE_KEY or POLYMARKET_KEYSTORE is required")
+		}
+		clob, err := c.clob()
+		if err != nil {
+			return err
+		}
+		creds, err := clob.RotateApiKey(nil)
+		if err != nil {
+			return err
+		}
+		// The new key can only be derived again with its nonce
+		fmt.Fprintf(c.stderr, "set POLYMARKET_API_KEY_NONCE=%d to keep using the new key\n", clob.ApiKeyNonce())
+		return printCreds(creds)
+
+	case "delete":
+		clob, err := c.authenticated()
+		if err != nil {
+			return err
+		}
+		key := c.config.APIKey
+		if key == "" {
+			creds, err := clob.DeriveApiKey(nil)
+			if err != nil {
+				return err
+			}
+			key = creds.Key
+		}
+		result, err := clob.DeleteApiKey()
+		if err != nil {
+			return err
+		}
+		return c.out.print(result, func() *table {
+			t := &table{header: []string{"DELETED"}}
+			t.add(key)
+			return t
+		})
+	}
+	return errUsage
+}
+
+func runOrders(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "place":
+		return placeOrder(c, args[1:])
+	case "cancel":
+		return cancelOrders(c, args[1:])
+	case "list":
+		return listOrders(c, args[1:])
+	}
+	return errUsage
+}
+
+func placeOrder(c *cli, args []string) error {
+	var tokenID, side, orderType *string
+	var price, size *float64
+	var expiration *int
+	if _, err := c.parseFlags("orders place", args, func(flags *flag.FlagSet) {
+		tokenID = flags.String("token", "", "token ID (required)")
+		side = flags.String("side", "", "buy or sell (required)")
+		price = flags.Float64("price", 0, "limit price (required)")
+		size = flags.Float64("size", 0, "size in shares (required)")
+		orderType = flags.String("type", string(types.OrderTypeGTC), "GTC, GTD, FOK or FAK")
+		expiration = flags.Int("expiration", 0, "expiry of GTD orders, in unix seconds")
+	}); err != nil {
+		return err
+	}
+	if *tokenID == "" || *price <= 0 || *size <= 0 {
+		return errUsage
+	}
+	order := &types.UserOrder{
+		TokenID: *tokenID,
+		Price:   *price,
+		Size:    *size,
+		Side:    types.Side(strings.ToUpper(*side)),
+	}
+	if order.Side != types.SideBuy && order.Side != types.SideSell {
+		return fmt.Errorf("invalid side %q, want buy or sell", *side)
+	}
+	switch typ := types.OrderType(strings.ToUpper(*orderType)); {
+	case typ == types.OrderTypeGTD && *expiration == 0:
+		return fmt.Errorf("GTD orders require -expiration")
+	case typ != types.OrderTypeGTD && *expiration != 0:
+		return fmt.Errorf("-expiration only applies to GTD orders")
+	}
+	if *expiration != 0 {
+		order.Expiration = expiration
+	}
+
+	clob, err := c.authenticated()
+	if err != nil {
+		return err
+	}
+	resp, err := clob.CreateAndPostOrder(order, nil, types.OrderType(strings.ToUpper(*orderType)))
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("order rejected: %s", resp.ErrorMsg)
+	}
+
+	return c.out.print(resp, func() *table {
+		t := &table{header: []string{"ORDER ID", "STATUS", "MAKING", "TAKING"}}
+		t.add(resp.OrderID, resp.Status, resp.MakingAmount, resp.TakingAmount)
+		return t
+	})
+}
+
+func cancelOrders(c *cli, args []string) error {
+	var all *bool
+	flags, err := c.parseFlags("orders cancel", args, func(flags *flag.FlagSet) {
+		all = flags.Bool("all", false, "cancel every open order")
+	})
+	if err != nil {
+		return err
+	}
+	ids := flags.Args()
+	if *all == (len(ids) > 0) {
+		return errUsage
+	}
+
+	clob, err := c.authenticated()
+	if err != nil {
+		return err
+	}
+	var resp *types.CancelOrdersResponse
+	switch {
+	case *all:
+		resp, err = clob.CancelAll()
+	case len(ids) == 1:
+		resp, err = clob.CancelOrder(ids[0])
+	default:
+		resp, err = clob.CancelOrders(ids)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(resp, func() *table {
+		t := &table{header: []string{"ORDER ID", "RESULT"}}
+		for _, id := range resp.Canceled {
+			t.add(id, "canceled")
+		}
+		notCanceled := make([]string, 0, len(resp.NotCanceled))
+		for id := range resp.NotCanceled {
+			notCanceled = append(notCanceled, id)
+		}
+		sort.Strings(notCanceled)
+		for _, id := range notCanceled {
+			t.add(id, resp.NotCanceled[id])
+		}
+		return t
+	})
+}
+
+func listOrders(c *cli, args []string) error {
+	var market, tokenID *string
+	if _, err := c.parseFlags("orders list", args, func(flags *flag.FlagSet) {
+		market = flags.String("market", "", "condition ID to filter by")
+		tokenID = flags.String("token", "", "token ID to filter by")
+	}); err != nil {
+		return err
+	}
+	var params types.OpenOrderParams
+	if *market != "" {
+		params.Market = market
+	}
+	if *tokenID != "" {
+		params.AssetID = tokenID
+	}
+
+	clob, err := c.authenticated()
+	if err != nil {
+		return err
+	}
+	orders, err := clob.GetOpenOrders(&params)
+	if err != nil {
+		return err
+	}
+	if orders == nil {
+		orders = []types.OpenOrder{}
+	}
+
+	return c.out.print(orders, func() *table {
+		t := &table{header: []string{"ORDER ID", "SIDE", "PRICE", "SIZE", "MATCHED", "TYPE", "TOKEN ID"}}
+		for _, o := range orders {
+			t.add(o.ID, o.Side, o.Price, o.OriginalSize, o.SizeMatched, o.OrderType, o.AssetID)
+		}
+		return t
+	})
+}
+
+// runStream prints every market channel message of the tokens as one JSON
+// line, whatever the output format, until interrupted
+func runStream(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	clob, err := c.clob()
+	if err != nil {
+		return err
+	}
+
+	ws := client.NewWebSocketClient(clob, &client.WebSocketClientOptions{
+		URL:           c.config.WebSocketURL,
+		Channel:       client.ChannelMarket,
+		AssetIDs:      args,
+		AutoReconnect: true,
+		ProxyUrl:      c.config.ProxyURL,
+		UserAgent:     userAgent,
+	})
+	messages := ws.Messages()
+	if err := ws.Connect(); err != nil {
+		return err
+	}
+	go func() {
+		<-c.ctx.Done()
+		ws.Disconnect()
+	}()
+
+	enc := json.NewEncoder(c.stdout)
+	for msg := range messages {
+		if raw, ok := msg.(*types.RawMessage); ok {
+			err = enc.Encode(raw.Data)
+		} else {
+			err = enc.Encode(msg)
+		}
+		if err != nil {
+			ws.Disconnect()
+			return err
+		}
+	}
+	return ws.Err()
+}
@@ -0,0 +1,213 @@
+// Command polymarket is a command line client for the Polymarket APIs.
+//
+// Usage:
+//
+//	polymarket [-config file] [-o table|json] <command> [arguments]
+//
+// The commands are:
+//
+//	markets search <query>                  search markets on Gamma
+//	book <token-id>                         show the order book of a token
+//	price <token-id>...                     show the bid, ask, midpoint and last trade
+//	positions [-user address]               list current positions
+//	activity [-user address]                list recent activity
+//	keys create|derive|list|rotate|delete   manage CLOB API keys
+//	orders place|cancel|list                place, cancel and list limit orders
+//	stream <token-id>...                    print market channel messages as JSON lines
+//
+// Settings come from the environment and from the KEY=VALUE file named by
+// -config or POLYMARKET_CONFIG, the environment taking precedence:
+//
+//	POLYMARKET_PRIVATE_KEY     wallet key, required to sign
+//	POLYMARKET_KEYSTORE        JSON keystore file, instead of the private key
+//	POLYMARKET_KEYSTORE_PASSPHRASE
+//	POLYMARKET_CHAIN_ID        137 (default) or 80002
+//	POLYMARKET_API_KEY         CLOB API credentials; derived from the
+//	POLYMARKET_API_SECRET      private key when unset
+//	POLYMARKET_API_PASSPHRASE
+//	POLYMARKET_API_KEY_NONCE   nonce of the API key, printed by keys rotate
+//	POLYMARKET_SIGNATURE_TYPE  1 (proxy wallet) or 2 (Safe) when the funder
+//	POLYMARKET_FUNDER_ADDRESS  below holds the funds instead of the wallet
+//	POLYMARKET_CLOB_URL        API base URLs
+//	POLYMARKET_GAMMA_URL
+//	POLYMARKET_DATA_URL
+//	POLYMARKET_WS_URL
+//	POLYMARKET_PROXY_URL       HTTP, HTTPS or SOCKS5 proxy
+//	POLYMARKET_OUTPUT          table (default) or json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/ybina/polymarket-sdk-go/client"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/gamma"
+	"github.com/ybina/polymarket-sdk-go/proxy"
+)
+
+const userAgent = "polymarket-cli/1.0"
+
+// errUsage reports invalid arguments, after their usage was printed
+var errUsage = errors.New("usage")
+
+// command runs one subcommand with the arguments following its name
+type command struct {
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"markets":   {"markets search [-limit n] <query>", runMarkets},
+	"book":      {"book <token-id>", runBook},
+	"price":     {"price <token-id>...", runPrice},
+	"positions": {"positions [-user address] [-limit n]", runPositions},
+	"activity":  {"activity [-user address] [-limit n]", runActivity},
+	"keys":      {"keys create|derive|list|rotate|delete", runKeys},
+	"orders":    {"orders place|cancel|list", runOrders},
+	"stream":    {"stream <token-id>...", runStream},
+}
+
+// cli is the state shared by the commands of one invocation
+type cli struct {
+	ctx    context.Context
+	config *Config
+	out    *printer
+	stdout io.Writer
+	stderr io.Writer
+
+	clobClient *client.ClobClient
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	flags := flag.NewFlagSet("polymarket", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", getenv("POLYMARKET_CONFIG"), "KEY=VALUE config file")
+	output := flags.String("o", "", "output format: table or json (default $POLYMARKET_OUTPUT or table)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: polymarket [-config file] [-o table|json] <command> [arguments]\n\nCommands:")
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(stderr, "  %s\n", commands[name].usage)
+		}
+		fmt.Fprintln(stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "polymarket: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return 2
+	}
+
+	config, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "polymarket: %v\n", err)
+		return 1
+	}
+	if *output != "" {
+		config.Output = *output
+	}
+	out, err := newPrinter(stdout, config.Output)
+	if err != nil {
+		fmt.Fprintf(stderr, "polymarket: %v\n", err)
+		return 2
+	}
+
+	c := &cli{ctx: ctx, config: config, out: out, stdout: stdout, stderr: stderr}
+	if err := cmd.run(c, flags.Args()[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "Usage: polymarket %s\n", cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "polymarket: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// clob returns the CLOB client, created on first use
+func (c *cli) clob() (*client.ClobClient, error) {
+	if c.clobClient != nil {
+		return c.clobClient, nil
+	}
+	wallet, err := c.config.wallet()
+	if err != nil {
+		return nil, err
+	}
+	clob, err := client.NewClobClient(&client.ClientConfig{
+		Host:          c.config.ClobURL,
+		ChainID:       c.config.ChainID,
+		Wallet:        wallet,
+		APIKey:        c.config.creds(),
+		APIKeyNonce:   c.config.APIKeyNonce,
+		SignatureType: c.config.SignatureType,
+		FunderAddress: c.config.FunderAddress,
+		ProxyUrl:      c.config.ProxyURL,
+		UserAgent:     userAgent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.clobClient = clob
+	return clob, nil
+}
+
+// authenticated returns a CLOB client that can make L2 requests; without
+// configured API credentials, it derives them from the wallet on first use
+func (c *cli) authenticated() (*client.ClobClient, error) {
+	if c.config.creds() == nil && !c.config.hasWallet() {
+		return nil, fmt.Errorf("POLYMARKET_PRIVATE_KEY, POLYMARKET_KEYSTORE or POLYMARKET_API_KEY is required")
+	}
+	return c.clob()
+}
+
+func (c *cli) gamma() (*gamma.GammaSDK, error) {
+	config := &gamma.GammaSDKConfig{BaseURL: c.config.GammaURL, UserAgent: userAgent}
+	if c.config.ProxyURL != "" {
+		proxyConfig, err := proxy.FromURL(c.config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		config.Proxy = proxyConfig
+	}
+	return gamma.NewGammaSDK(config), nil
+}
+
+func (c *cli) data() (*data.DataSDK, error) {
+	config := &data.DataSDKConfig{BaseURL: c.config.DataURL, UserAgent: userAgent}
+	if c.config.ProxyURL != "" {
+		proxyConfig, err := proxy.FromURL(c.config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		config.Proxy = proxyConfig
+	}
+	return data.NewDataSDK(config), nil
+}
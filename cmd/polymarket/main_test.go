@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/gamma"
+	"github.com/ybina/polymarket-sdk-go/polytest"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+const (
+	testCondition = "0xc0nd"
+	yesToken      = "1111"
+	noToken       = "2222"
+)
+
+// testEnv starts the fake APIs and returns the environment pointing the CLI
+// at them, with a fresh wallet
+func testEnv(t *testing.T) (*polytest.Server, map[string]string) {
+	t.Helper()
+	srv := polytest.New(nil)
+	t.Cleanup(srv.Close)
+	srv.AddMarket(polytest.Market{
+		ConditionID: testCondition,
+		Question:    "Will it rain?",
+		Tokens:      []polytest.Token{{TokenID: yesToken, Outcome: "Yes"}, {TokenID: noToken, Outcome: "No"}},
+	})
+
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, map[string]string{
+		"POLYMARKET_PRIVATE_KEY": auth.PrivateKeyToHex(wallet.GetPrivateKey()),
+		"POLYMARKET_CHAIN_ID":    "80002",
+		"POLYMARKET_CLOB_URL":    srv.CLOBURL(),
+		"POLYMARKET_GAMMA_URL":   srv.GammaURL(),
+		"POLYMARKET_DATA_URL":    srv.DataURL(),
+		"POLYMARKET_WS_URL":      srv.WebSocketURL(),
+	}
+}
+
+// runCLI runs the CLI with env as its environment
+func runCLI(t *testing.T, env map[string]string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr, func(key string) string { return env[key] })
+	return stdout.String(), stderr.String(), code
+}
+
+func TestCommands(t *testing.T) {
+	srv, env := testEnv(t)
+	if _, err := srv.PlaceLimit("maker", yesToken, types.SideSell, 0.6, 20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.PlaceLimit("maker", yesToken, types.SideBuy, 0.4, 10); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetGamma("/public-search", gamma.SearchResponse{Events: []gamma.Event{{
+		Title: "Weather",
+		Markets: []gamma.EventMarket{{
+			Question:      "Will it rain?",
+			ConditionID:   testCondition,
+			Outcomes:      gamma.StringArray{"Yes", "No"},
+			OutcomePrices: gamma.StringArray{"0.5", "0.5"},
+			ClobTokenIDs:  gamma.StringArray{yesToken, noToken},
+		}},
+	}}})
+	srv.SetData("/positions", []data.Position{{Title: "Will it rain?", Outcome: "Yes", Size: 12, AvgPrice: 0.45, CurPrice: 0.5}})
+	srv.SetData("/activity", []data.Activity{{Timestamp: 1700000000, Type: "TRADE", Side: types.SideBuy, Size: 12, UsdcSize: 5.4, Title: "Will it rain?"}})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{name: "no command", args: nil, wantCode: 2},
+		{name: "unknown command", args: []string{"nope"}, wantCode: 2},
+		{name: "bad output", args: []string{"-o", "xml", "book", yesToken}, wantCode: 2},
+		{name: "book", args: []string{"book", yesToken}, want: []string{"SIDE", "ask   0.6", "bid   0.4"}},
+		{name: "book json", args: []string{"-o", "json", "book", yesToken}, want: []string{`"asset_id": "1111"`}},
+		{name: "book unknown token", args: []string{"book", "9999"}, wantCode: 1},
+		{name: "price", args: []string{"price", yesToken}, want: []string{"1111", "0.4", "0.6", "0.5"}},
+		{name: "markets search", args: []string{"markets", "search", "rain"}, want: []string{"Will it rain?", "Yes", noToken}},
+		{name: "markets without query", args: []string{"markets", "search"}, wantCode: 2},
+		{name: "positions", args: []string{"positions"}, want: []string{"Will it rain?", "0.45"}},
+		{name: "activity json", args: []string{"-o", "json", "activity", "-user", "0xabc"}, want: []string{`"usdcSize": 5.4`}},
+		{name: "keys derive before create", args: []string{"keys", "derive"}, wantCode: 1},
+		{name: "keys create", args: []string{"keys", "create"}, want: []string{"KEY", "SECRET", "PASSPHRASE"}},
+		{name: "keys list derives credentials", args: []string{"keys", "list"}, want: []string{"KEY"}},
+		{name: "keys rotate", args: []string{"keys", "rotate"}, want: []string{"KEY", "SECRET", "PASSPHRASE"}},
+		{name: "orders place", args: []string{"orders", "place", "-token", yesToken, "-side", "buy", "-price", "0.45", "-size", "10"}, want: []string{"live"}},
+		{name: "orders place bad side", args: []string{"orders", "place", "-token", yesToken, "-side", "hold", "-price", "0.45", "-size", "10"}, wantCode: 1},
+		{name: "orders place GTD without expiration", args: []string{"orders", "place", "-token", yesToken, "-side", "buy", "-price", "0.45", "-size", "10", "-type", "gtd"}, wantCode: 1},
+		{name: "orders place expiration without GTD", args: []string{"orders", "place", "-token", yesToken, "-side", "buy", "-price", "0.45", "-size", "10", "-expiration", "1900000000"}, wantCode: 1},
+		{name: "orders list", args: []string{"orders", "list", "-token", yesToken}, want: []string{"BUY", "0.45", "10"}},
+		{name: "orders cancel all", args: []string{"orders", "cancel", "-all"}, want: []string{"canceled"}},
+		{name: "orders cancel without ids", args: []string{"orders", "cancel"}, wantCode: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, code := runCLI(t, env, tt.args...)
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\nstdout: %s\nstderr: %s", code, tt.wantCode, stdout, stderr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("stdout misses %q:\n%s", want, stdout)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "polymarket.env")
+	file := "POLYMARKET_API_KEY=file-key\nPOLYMARKET_CHAIN_ID=80002\nPOLYMARKET_OUTPUT=json\nPOLYMARKET_SIGNATURE_TYPE=2\n"
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"POLYMARKET_API_KEY": "env-key"}
+	config, err := loadConfig(path, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if config.APIKey != "env-key" || config.ChainID != types.ChainAmoy || config.Output != outputJSON || config.SignatureType != types.SignatureTypePolyGnosisSafe {
+		t.Errorf("loadConfig() = %+v", config)
+	}
+
+	if config, _ := loadConfig("", func(string) string { return "" }); config.ChainID != types.ChainPolygon || config.Output != outputTable || config.creds() != nil {
+		t.Errorf("loadConfig() defaults = %+v", config)
+	}
+	if _, err := loadConfig("", func(key string) string { return map[string]string{"POLYMARKET_CHAIN_ID": "polygon"}[key] }); err == nil {
+		t.Error("loadConfig() with a non-numeric chain ID should fail")
+	}
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.env"), func(string) string { return "" }); err == nil {
+		t.Error("loadConfig() with a missing file should fail")
+	}
+}
+
+func TestKeystore(t *testing.T) {
+	_, env := testEnv(t)
+	wallet, err := auth.NewWalletFromHex(env["POLYMARKET_PRIVATE_KEY"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	keystoreJSON, err := keystore.EncryptKey(&keystore.Key{Address: wallet.GetAddress(), PrivateKey: wallet.GetPrivateKey()}, "hunter2", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	if err := os.WriteFile(path, keystoreJSON, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	delete(env, "POLYMARKET_PRIVATE_KEY")
+	env["POLYMARKET_KEYSTORE"] = path
+
+	if _, stderr, code := runCLI(t, env, "keys", "create"); code != 1 || !strings.Contains(stderr, "decrypt") {
+		t.Errorf("keys create with the wrong passphrase: exit code = %d, stderr: %s", code, stderr)
+	}
+	env["POLYMARKET_KEYSTORE_PASSPHRASE"] = "hunter2"
+	if stdout, stderr, code := runCLI(t, env, "keys", "create"); code != 0 || !strings.Contains(stdout, "KEY") {
+		t.Fatalf("keys create: exit code = %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+	if stdout, stderr, code := runCLI(t, env, "orders", "list"); code != 0 {
+		t.Errorf("orders list: exit code = %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for a concurrent writer and reader
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestStream(t *testing.T) {
+	srv, env := testEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var stdout, stderr syncBuffer
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, []string{"stream", yesToken}, &stdout, &stderr, func(key string) string { return env[key] })
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stdout.String(), "\n") {
+		if time.Now().After(deadline) {
+			t.Fatalf("no message streamed; stderr: %s", stderr.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := srv.PlaceLimit("maker", yesToken, types.SideBuy, 0.3, 5); err != nil {
+		t.Fatal(err)
+	}
+	for strings.Count(stdout.String(), "\n") < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("order not streamed:\n%s", stdout.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	if code := <-done; code != 0 {
+		t.Errorf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	var first struct {
+		EventType string `json:"event_type"`
+		AssetID   string `json:"asset_id"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first.EventType != "book" || first.AssetID != yesToken {
+		t.Errorf("first line = %s, want the book snapshot", lines[0])
+	}
+}
@@ -0,0 +1,65 @@
+package ctf
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// conditionalTokensABI is the part of the Gnosis Conditional Tokens ABI the
+// client uses
+const conditionalTokensABI = `[
+	{"type":"function","name":"splitPosition","stateMutability":"nonpayable","inputs":[
+		{"name":"collateralToken","type":"address"},{"name":"parentCollectionId","type":"bytes32"},
+		{"name":"conditionId","type":"bytes32"},{"name":"partition","type":"uint256[]"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"mergePositions","stateMutability":"nonpayable","inputs":[
+		{"name":"collateralToken","type":"address"},{"name":"parentCollectionId","type":"bytes32"},
+		{"name":"conditionId","type":"bytes32"},{"name":"partition","type":"uint256[]"},{"name":"amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"redeemPositions","stateMutability":"nonpayable","inputs":[
+		{"name":"collateralToken","type":"address"},{"name":"parentCollectionId","type":"bytes32"},
+		{"name":"conditionId","type":"bytes32"},{"name":"indexSets","type":"uint256[]"}],"outputs":[]},
+	{"type":"function","name":"setApprovalForAll","stateMutability":"nonpayable","inputs":[
+		{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"isApprovedForAll","stateMutability":"view","inputs":[
+		{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[
+		{"name":"owner","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"payoutDenominator","stateMutability":"view","inputs":[
+		{"name":"conditionId","type":"bytes32"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// negRiskAdapterABI is the part of the Neg Risk Adapter ABI the client uses
+const negRiskAdapterABI = `[
+	{"type":"function","name":"splitPosition","stateMutability":"nonpayable","inputs":[
+		{"name":"_conditionId","type":"bytes32"},{"name":"_amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"mergePositions","stateMutability":"nonpayable","inputs":[
+		{"name":"_conditionId","type":"bytes32"},{"name":"_amount","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"redeemPositions","stateMutability":"nonpayable","inputs":[
+		{"name":"_conditionId","type":"bytes32"},{"name":"_amounts","type":"uint256[]"}],"outputs":[]},
+	{"type":"function","name":"convertPositions","stateMutability":"nonpayable","inputs":[
+		{"name":"_marketId","type":"bytes32"},{"name":"_indexSet","type":"uint256"},{"name":"_amount","type":"uint256"}],"outputs":[]}
+]`
+
+// erc20ABI is the part of the ERC-20 ABI the client uses on the collateral
+const erc20ABI = `[
+	{"type":"function","name":"approve","stateMutability":"nonpayable","inputs":[
+		{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[
+		{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[
+		{"name":"account","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var (
+	conditionalTokens = mustParseABI(conditionalTokensABI)
+	negRiskAdapter    = mustParseABI(negRiskAdapterABI)
+	erc20             = mustParseABI(erc20ABI)
+)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
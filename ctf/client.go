@@ -0,0 +1,339 @@
+// Package ctf splits, merges, redeems and converts Polymarket positions on
+// chain, through the Gnosis Conditional Tokens Framework (CTF) and the Neg
+// Risk Adapter.
+//
+// Every binary market is a CTF condition with two outcomes, Yes (index set
+// 1) and No (index set 2). Splitting turns collateral into one token of each
+// outcome, merging turns a pair back into collateral and redeeming pays the
+// winning tokens out once the condition is resolved. Neg risk markets go
+// through the adapter, which also converts No tokens of several questions of
+// one market into collateral and Yes tokens of the others.
+//
+// The contracts pull tokens from the wallet, so approve them first:
+//
+//	c.ApproveCollateral(ctx, contracts.ConditionalTokens, amount) // split
+//	c.ApproveCollateral(ctx, contracts.NegRiskAdapter, amount)    // neg risk split
+//	c.SetApprovalForAll(ctx, contracts.NegRiskAdapter, true)      // other neg risk operations
+package ctf
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/internal/logging"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// DefaultGasMultiplier is the margin added to gas estimates
+const DefaultGasMultiplier = 1.2
+
+// Index sets of the two outcomes of a binary condition
+var (
+	yesIndexSet = big.NewInt(1)
+	noIndexSet  = big.NewInt(2)
+	binary      = []*big.Int{yesIndexSet, noIndexSet}
+)
+
+// Backend is the chain the client reads and transacts on, such as an
+// *ethclient.Client or a *simulated.Client
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Config configures a Client
+type Config struct {
+	Backend Backend
+	ChainID types.Chain
+	Wallet  *auth.Wallet // Signs and pays for the transactions
+
+	// Contracts overrides the addresses of ChainID, e.g. for a local deployment
+	Contracts *types.ContractConfig
+
+	// GasMultiplier scales gas estimates into gas limits (default DefaultGasMultiplier)
+	GasMultiplier float64
+
+	// Logger receives transactions at debug level (default: discard)
+	Logger *slog.Logger
+}
+
+// Client sends CTF and Neg Risk Adapter transactions from one wallet. It
+// assigns nonces itself, so that several transactions can be sent without
+// waiting for each to be mined.
+type Client struct {
+	backend           Backend
+	from              common.Address
+	signer            bind.SignerFn
+	collateral        common.Address
+	conditionalTokens *bind.BoundContract
+	negRiskAdapter    *bind.BoundContract
+	gasMultiplier     float64
+	logger            *slog.Logger
+
+	// nonceMu guards nonce, the nonce of the next transaction; nonceSynced
+	// is false until it is read from the pending state
+	nonceMu     sync.Mutex
+	nonce       uint64
+	nonceSynced bool
+}
+
+// NewClient creates a CTF client
+func NewClient(config *Config) (*Client, error) {
+	if config.Backend == nil {
+		return nil, fmt.Errorf("backend is required")
+	}
+	if config.Wallet == nil {
+		return nil, fmt.Errorf("wallet is required")
+	}
+	contracts := config.Contracts
+	if contracts == nil {
+		known, err := types.GetContractConfig(config.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		contracts = &known
+	}
+	for name, address := range map[string]string{
+		"collateral":         contracts.Collateral,
+		"conditional tokens": contracts.ConditionalTokens,
+		"neg risk adapter":   contracts.NegRiskAdapter,
+	} {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid %s address %q", name, address)
+		}
+	}
+
+	opts := bind.NewKeyedTransactor(config.Wallet.GetPrivateKey(), big.NewInt(int64(config.ChainID)))
+	gasMultiplier := config.GasMultiplier
+	if gasMultiplier <= 0 {
+		gasMultiplier = DefaultGasMultiplier
+	}
+	return &Client{
+		backend:           config.Backend,
+		from:              opts.From,
+		signer:            opts.Signer,
+		collateral:        common.HexToAddress(contracts.Collateral),
+		conditionalTokens: bind.NewBoundContract(common.HexToAddress(contracts.ConditionalTokens), conditionalTokens, config.Backend, config.Backend, config.Backend),
+		negRiskAdapter:    bind.NewBoundContract(common.HexToAddress(contracts.NegRiskAdapter), negRiskAdapter, config.Backend, config.Backend, config.Backend),
+		gasMultiplier:     gasMultiplier,
+		logger:            logging.OrDiscard(config.Logger),
+	}, nil
+}
+
+// Address returns the address transactions are sent from
+func (c *Client) Address() common.Address {
+	return c.from
+}
+
+// SplitPosition turns amount of collateral, in on-chain units with 6
+// decimals, into amount of Yes and of No tokens of conditionID
+func (c *Client) SplitPosition(ctx context.Context, conditionID common.Hash, amount *big.Int, negRisk bool) (*gethtypes.Transaction, error) {
+	if negRisk {
+		return c.transact(ctx, c.negRiskAdapter, negRiskAdapter, "splitPosition", conditionID, amount)
+	}
+	return c.transact(ctx, c.conditionalTokens, conditionalTokens, "splitPosition", c.collateral, common.Hash{}, conditionID, binary, amount)
+}
+
+// MergePositions turns amount of Yes and of No tokens of conditionID back
+// into amount of collateral
+func (c *Client) MergePositions(ctx context.Context, conditionID common.Hash, amount *big.Int, negRisk bool) (*gethtypes.Transaction, error) {
+	if negRisk {
+		return c.transact(ctx, c.negRiskAdapter, negRiskAdapter, "mergePositions", conditionID, amount)
+	}
+	return c.transact(ctx, c.conditionalTokens, conditionalTokens, "mergePositions", c.collateral, common.Hash{}, conditionID, binary, amount)
+}
+
+// RedeemPositions pays out every Yes and No token of the resolved
+// conditionID the wallet holds
+func (c *Client) RedeemPositions(ctx context.Context, conditionID common.Hash) (*gethtypes.Transaction, error) {
+	return c.transact(ctx, c.conditionalTokens, conditionalTokens, "redeemPositions", c.collateral, common.Hash{}, conditionID, binary)
+}
+
+// RedeemNegRiskPositions pays out yesAmount Yes and noAmount No tokens of
+// the resolved neg risk conditionID
+func (c *Client) RedeemNegRiskPositions(ctx context.Context, conditionID common.Hash, yesAmount, noAmount *big.Int) (*gethtypes.Transaction, error) {
+	return c.transact(ctx, c.negRiskAdapter, negRiskAdapter, "redeemPositions", conditionID, []*big.Int{yesAmount, noAmount})
+}
+
+// ConvertPositions converts amount of No tokens of each question of the neg
+// risk marketID in indexSet, a bit mask of question indexes, into collateral
+// and amount of Yes tokens of every other question
+func (c *Client) ConvertPositions(ctx context.Context, marketID common.Hash, indexSet, amount *big.Int) (*gethtypes.Transaction, error) {
+	return c.transact(ctx, c.negRiskAdapter, negRiskAdapter, "convertPositions", marketID, indexSet, amount)
+}
+
+// RedeemPosition redeems a redeemable position of the Data API, through the
+// adapter for neg risk markets. Neg risk positions are redeemed for the
+// on-chain balance of position.Asset, not the rounded Size the API reports.
+// The wallet must hold the tokens itself: positions of a proxy wallet or
+// Safe are redeemed by a transaction of that wallet, which this client does
+// not send.
+func (c *Client) RedeemPosition(ctx context.Context, position data.Position) (*gethtypes.Transaction, error) {
+	if !position.Redeemable {
+		return nil, fmt.Errorf("position %s is not redeemable", position.Asset)
+	}
+	if !isHash(position.ConditionID) {
+		return nil, fmt.Errorf("invalid condition ID %q", position.ConditionID)
+	}
+	conditionID := common.HexToHash(position.ConditionID)
+	if position.NegativeRisk == nil || !*position.NegativeRisk {
+		return c.RedeemPositions(ctx, conditionID)
+	}
+
+	if position.OutcomeIndex != 0 && position.OutcomeIndex != 1 {
+		return nil, fmt.Errorf("invalid outcome index %d", position.OutcomeIndex)
+	}
+	tokenID, ok := new(big.Int).SetString(position.Asset, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid asset %q", position.Asset)
+	}
+	balance, err := c.BalanceOf(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Sign() == 0 {
+		return nil, fmt.Errorf("%s holds no %s tokens", c.from.Hex(), position.Asset)
+	}
+	if position.OutcomeIndex == 0 {
+		return c.RedeemNegRiskPositions(ctx, conditionID, balance, new(big.Int))
+	}
+	return c.RedeemNegRiskPositions(ctx, conditionID, new(big.Int), balance)
+}
+
+// ApproveCollateral allows spender, the CTF or the adapter, to take amount
+// of collateral from the wallet
+func (c *Client) ApproveCollateral(ctx context.Context, spender common.Address, amount *big.Int) (*gethtypes.Transaction, error) {
+	token := bind.NewBoundContract(c.collateral, erc20, c.backend, c.backend, c.backend)
+	return c.transact(ctx, token, erc20, "approve", spender, amount)
+}
+
+// SetApprovalForAll allows or forbids operator, e.g. the adapter, to move
+// the wallet's conditional tokens
+func (c *Client) SetApprovalForAll(ctx context.Context, operator common.Address, approved bool) (*gethtypes.Transaction, error) {
+	return c.transact(ctx, c.conditionalTokens, conditionalTokens, "setApprovalForAll", operator, approved)
+}
+
+// CollateralAllowance returns how much collateral spender may take from the wallet
+func (c *Client) CollateralAllowance(ctx context.Context, spender common.Address) (*big.Int, error) {
+	token := bind.NewBoundContract(c.collateral, erc20, c.backend, c.backend, c.backend)
+	var allowance *big.Int
+	err := c.call(ctx, token, erc20, &allowance, "allowance", c.from, spender)
+	return allowance, err
+}
+
+// IsApprovedForAll reports whether operator may move the wallet's conditional tokens
+func (c *Client) IsApprovedForAll(ctx context.Context, operator common.Address) (bool, error) {
+	var approved bool
+	err := c.call(ctx, c.conditionalTokens, conditionalTokens, &approved, "isApprovedForAll", c.from, operator)
+	return approved, err
+}
+
+// BalanceOf returns the wallet's balance of the conditional token tokenID,
+// e.g. a CLOB token ID
+func (c *Client) BalanceOf(ctx context.Context, tokenID *big.Int) (*big.Int, error) {
+	var balance *big.Int
+	err := c.call(ctx, c.conditionalTokens, conditionalTokens, &balance, "balanceOf", c.from, tokenID)
+	return balance, err
+}
+
+// IsResolved reports whether conditionID has been resolved, after which its
+// positions can be redeemed
+func (c *Client) IsResolved(ctx context.Context, conditionID common.Hash) (bool, error) {
+	var denominator *big.Int
+	if err := c.call(ctx, c.conditionalTokens, conditionalTokens, &denominator, "payoutDenominator", conditionID); err != nil {
+		return false, err
+	}
+	return denominator.Sign() > 0, nil
+}
+
+// WaitMined waits for tx to be mined and returns its receipt, or an error
+// if it reverted
+func (c *Client) WaitMined(ctx context.Context, tx *gethtypes.Transaction) (*gethtypes.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, c.backend, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return receipt, fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return receipt, nil
+}
+
+// ResetNonce makes the next transaction read its nonce from the pending
+// state again, e.g. after replacing or dropping transactions elsewhere
+func (c *Client) ResetNonce() {
+	c.nonceMu.Lock()
+	defer c.nonceMu.Unlock()
+	c.nonceSynced = false
+}
+
+// transact estimates the gas of method on contract, assigns the next nonce,
+// signs and sends the transaction
+func (c *Client) transact(ctx context.Context, contract *bind.BoundContract, contractABI abi.ABI, method string, args ...interface{}) (*gethtypes.Transaction, error) {
+	input, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	to := contract.Address()
+	gas, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{From: c.from, To: &to, Data: input})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas of %s: %w", method, err)
+	}
+
+	c.nonceMu.Lock()
+	defer c.nonceMu.Unlock()
+	if !c.nonceSynced {
+		if c.nonce, err = c.backend.PendingNonceAt(ctx, c.from); err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		c.nonceSynced = true
+	}
+	tx, err := bind.Transact(contract, &bind.TransactOpts{
+		From:     c.from,
+		Nonce:    new(big.Int).SetUint64(c.nonce),
+		Signer:   c.signer,
+		GasLimit: uint64(float64(gas) * c.gasMultiplier),
+		Context:  ctx,
+	}, input)
+	if err != nil {
+		// The nonce may or may not have been used; read it again next time
+		c.nonceSynced = false
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	c.nonce++
+	c.logger.Debug("transaction sent", "method", method, "to", to.Hex(), "hash", tx.Hash().Hex(), "nonce", tx.Nonce(), "gas", tx.Gas())
+	return tx, nil
+}
+
+// call calls the view method on contract and unpacks its only result into out
+func (c *Client) call(ctx context.Context, contract *bind.BoundContract, contractABI abi.ABI, out interface{}, method string, args ...interface{}) error {
+	input, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	output, err := contract.CallRaw(&bind.CallOpts{From: c.from, Context: ctx}, input)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	if err := contractABI.UnpackIntoInterface(out, method, output); err != nil {
+		return fmt.Errorf("failed to unpack %s: %w", method, err)
+	}
+	return nil
+}
+
+// isHash reports whether s is a 0x-prefixed 32-byte hex string
+func isHash(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == common.HashLength
+}
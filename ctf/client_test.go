@@ -0,0 +1,382 @@
+package ctf
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+const testGas = 100000
+
+// fakeBackend is an in-memory chain that records sent transactions, mines
+// them instantly and answers calls from results keyed by method selector
+type fakeBackend struct {
+	mu       sync.Mutex
+	nonce    uint64 // pending nonce of every account
+	sent     []*gethtypes.Transaction
+	reverted map[common.Hash]bool
+	results  map[[4]byte][]byte
+	sendErr  error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{reverted: map[common.Hash]bool{}, results: map[[4]byte][]byte{}}
+}
+
+func (b *fakeBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (b *fakeBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (b *fakeBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result, ok := b.results[[4]byte(call.Data[:4])]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return result, nil
+}
+
+func (b *fakeBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return testGas, nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(30e9), nil
+}
+
+func (b *fakeBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(30e9), nil
+}
+
+func (b *fakeBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return &gethtypes.Header{Number: big.NewInt(1), BaseFee: big.NewInt(1e9)}, nil
+}
+
+func (b *fakeBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nonce, nil
+}
+
+func (b *fakeBackend) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sendErr != nil {
+		return b.sendErr
+	}
+	b.sent = append(b.sent, tx)
+	b.nonce = tx.Nonce() + 1
+	return nil
+}
+
+func (b *fakeBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, tx := range b.sent {
+		if tx.Hash() == txHash {
+			status := gethtypes.ReceiptStatusSuccessful
+			if b.reverted[txHash] {
+				status = gethtypes.ReceiptStatusFailed
+			}
+			return &gethtypes.Receipt{TxHash: txHash, Status: status, BlockNumber: big.NewInt(1)}, nil
+		}
+	}
+	return nil, ethereum.NotFound
+}
+
+func (b *fakeBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	return nil, nil
+}
+
+func (b *fakeBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- gethtypes.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+// setResult makes calls of method return values
+func (b *fakeBackend) setResult(t *testing.T, contractABI abi.ABI, method string, values ...interface{}) {
+	t.Helper()
+	output, err := contractABI.Methods[method].Outputs.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.results[[4]byte(contractABI.Methods[method].ID)] = output
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeBackend, types.ContractConfig) {
+	t.Helper()
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := newFakeBackend()
+	c, err := NewClient(&Config{Backend: backend, ChainID: types.ChainAmoy, Wallet: wallet})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	contracts, _ := types.GetContractConfig(types.ChainAmoy)
+	return c, backend, contracts
+}
+
+func TestTransactions(t *testing.T) {
+	c, backend, contracts := newTestClient(t)
+	ctx := context.Background()
+	conditionID := common.HexToHash("0xc0ffee")
+	collateral := common.HexToAddress(contracts.Collateral)
+	amount := big.NewInt(10e6)
+	negRisk := true
+	// The balance, not the rounded size of the position, is redeemed
+	backend.setResult(t, conditionalTokens, "balanceOf", big.NewInt(2499999))
+
+	tests := []struct {
+		name     string
+		send     func() (*gethtypes.Transaction, error)
+		to       string
+		abi      abi.ABI
+		method   string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "split",
+			send:     func() (*gethtypes.Transaction, error) { return c.SplitPosition(ctx, conditionID, amount, false) },
+			to:       contracts.ConditionalTokens,
+			abi:      conditionalTokens,
+			method:   "splitPosition",
+			wantArgs: []interface{}{collateral, [32]byte{}, [32]byte(conditionID), binary, amount},
+		},
+		{
+			name:     "merge",
+			send:     func() (*gethtypes.Transaction, error) { return c.MergePositions(ctx, conditionID, amount, false) },
+			to:       contracts.ConditionalTokens,
+			abi:      conditionalTokens,
+			method:   "mergePositions",
+			wantArgs: []interface{}{collateral, [32]byte{}, [32]byte(conditionID), binary, amount},
+		},
+		{
+			name:     "redeem",
+			send:     func() (*gethtypes.Transaction, error) { return c.RedeemPositions(ctx, conditionID) },
+			to:       contracts.ConditionalTokens,
+			abi:      conditionalTokens,
+			method:   "redeemPositions",
+			wantArgs: []interface{}{collateral, [32]byte{}, [32]byte(conditionID), binary},
+		},
+		{
+			name:     "neg risk split",
+			send:     func() (*gethtypes.Transaction, error) { return c.SplitPosition(ctx, conditionID, amount, true) },
+			to:       contracts.NegRiskAdapter,
+			abi:      negRiskAdapter,
+			method:   "splitPosition",
+			wantArgs: []interface{}{[32]byte(conditionID), amount},
+		},
+		{
+			name:     "neg risk merge",
+			send:     func() (*gethtypes.Transaction, error) { return c.MergePositions(ctx, conditionID, amount, true) },
+			to:       contracts.NegRiskAdapter,
+			abi:      negRiskAdapter,
+			method:   "mergePositions",
+			wantArgs: []interface{}{[32]byte(conditionID), amount},
+		},
+		{
+			name: "neg risk redeem",
+			send: func() (*gethtypes.Transaction, error) {
+				return c.RedeemNegRiskPositions(ctx, conditionID, amount, big.NewInt(0))
+			},
+			to:       contracts.NegRiskAdapter,
+			abi:      negRiskAdapter,
+			method:   "redeemPositions",
+			wantArgs: []interface{}{[32]byte(conditionID), []*big.Int{amount, big.NewInt(0)}},
+		},
+		{
+			name: "convert",
+			send: func() (*gethtypes.Transaction, error) {
+				return c.ConvertPositions(ctx, conditionID, big.NewInt(5), amount)
+			},
+			to:       contracts.NegRiskAdapter,
+			abi:      negRiskAdapter,
+			method:   "convertPositions",
+			wantArgs: []interface{}{[32]byte(conditionID), big.NewInt(5), amount},
+		},
+		{
+			name: "redeem neg risk position",
+			send: func() (*gethtypes.Transaction, error) {
+				return c.RedeemPosition(ctx, data.Position{Asset: "123", ConditionID: conditionID.Hex(), Size: 2.5, OutcomeIndex: 1, Redeemable: true, NegativeRisk: &negRisk})
+			},
+			to:       contracts.NegRiskAdapter,
+			abi:      negRiskAdapter,
+			method:   "redeemPositions",
+			wantArgs: []interface{}{[32]byte(conditionID), []*big.Int{big.NewInt(0), big.NewInt(2499999)}},
+		},
+		{
+			name: "approve collateral",
+			send: func() (*gethtypes.Transaction, error) {
+				return c.ApproveCollateral(ctx, common.HexToAddress(contracts.NegRiskAdapter), amount)
+			},
+			to:       contracts.Collateral,
+			abi:      erc20,
+			method:   "approve",
+			wantArgs: []interface{}{common.HexToAddress(contracts.NegRiskAdapter), amount},
+		},
+		{
+			name: "approve tokens",
+			send: func() (*gethtypes.Transaction, error) {
+				return c.SetApprovalForAll(ctx, common.HexToAddress(contracts.NegRiskAdapter), true)
+			},
+			to:       contracts.ConditionalTokens,
+			abi:      conditionalTokens,
+			method:   "setApprovalForAll",
+			wantArgs: []interface{}{common.HexToAddress(contracts.NegRiskAdapter), true},
+		},
+	}
+
+	signer := gethtypes.LatestSignerForChainID(big.NewInt(int64(types.ChainAmoy)))
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := tt.send()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if tx.To() == nil || *tx.To() != common.HexToAddress(tt.to) {
+				t.Errorf("to = %v, want %s", tx.To(), tt.to)
+			}
+			if tx.Nonce() != uint64(i) {
+				t.Errorf("nonce = %d, want %d", tx.Nonce(), i)
+			}
+			if tx.Gas() != testGas*DefaultGasMultiplier {
+				t.Errorf("gas = %d, want %d", tx.Gas(), uint64(testGas*DefaultGasMultiplier))
+			}
+			if from, err := gethtypes.Sender(signer, tx); err != nil || from != c.Address() {
+				t.Errorf("sender = %s, %v, want %s", from.Hex(), err, c.Address().Hex())
+			}
+
+			method, err := tt.abi.MethodById(tx.Data())
+			if err != nil || method.Name != tt.method {
+				t.Fatalf("method = %v, %v, want %s", method, err, tt.method)
+			}
+			args, err := method.Inputs.Unpack(tx.Data()[4:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := method.Inputs.Pack(tt.wantArgs...)
+			got, _ := method.Inputs.Pack(args...)
+			if string(got) != string(want) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+	if len(backend.sent) != len(tests) {
+		t.Errorf("sent %d transactions, want %d", len(backend.sent), len(tests))
+	}
+}
+
+func TestRedeemPositionRejects(t *testing.T) {
+	c, backend, _ := newTestClient(t)
+	backend.setResult(t, conditionalTokens, "balanceOf", big.NewInt(0))
+	negRisk := true
+	conditionID := common.HexToHash("0x1").Hex()
+	tests := []struct {
+		name     string
+		position data.Position
+	}{
+		{name: "not redeemable", position: data.Position{ConditionID: conditionID}},
+		{name: "invalid condition", position: data.Position{ConditionID: "0x1", Redeemable: true}},
+		{name: "invalid asset", position: data.Position{Asset: "0xabc", ConditionID: conditionID, Redeemable: true, NegativeRisk: &negRisk}},
+		{name: "invalid outcome", position: data.Position{Asset: "123", ConditionID: conditionID, OutcomeIndex: 2, Redeemable: true, NegativeRisk: &negRisk}},
+		{name: "not held by the wallet", position: data.Position{Asset: "123", ConditionID: conditionID, Redeemable: true, NegativeRisk: &negRisk}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := c.RedeemPosition(context.Background(), tt.position); err == nil {
+				t.Error("RedeemPosition() should fail")
+			}
+		})
+	}
+}
+
+func TestNonceRecovery(t *testing.T) {
+	c, backend, _ := newTestClient(t)
+	ctx := context.Background()
+	backend.nonce = 7
+
+	if tx, err := c.RedeemPositions(ctx, common.Hash{1}); err != nil || tx.Nonce() != 7 {
+		t.Fatalf("first transaction = %v, %v, want nonce 7", tx, err)
+	}
+
+	backend.sendErr = errors.New("replacement transaction underpriced")
+	if _, err := c.RedeemPositions(ctx, common.Hash{2}); err == nil {
+		t.Fatal("RedeemPositions() should fail when sending fails")
+	}
+
+	// A transaction sent elsewhere meanwhile moves the pending nonce
+	backend.sendErr = nil
+	backend.nonce = 9
+	if tx, err := c.RedeemPositions(ctx, common.Hash{3}); err != nil || tx.Nonce() != 9 {
+		t.Errorf("transaction after a failure = %v, %v, want nonce 9", tx, err)
+	}
+
+	backend.nonce = 20
+	c.ResetNonce()
+	if tx, err := c.RedeemPositions(ctx, common.Hash{4}); err != nil || tx.Nonce() != 20 {
+		t.Errorf("transaction after ResetNonce() = %v, %v, want nonce 20", tx, err)
+	}
+}
+
+func TestWaitMined(t *testing.T) {
+	c, backend, _ := newTestClient(t)
+	ctx := context.Background()
+
+	tx, err := c.SplitPosition(ctx, common.Hash{1}, big.NewInt(1e6), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt, err := c.WaitMined(ctx, tx); err != nil || receipt.TxHash != tx.Hash() {
+		t.Errorf("WaitMined() = %v, %v", receipt, err)
+	}
+
+	tx, err = c.MergePositions(ctx, common.Hash{1}, big.NewInt(1e6), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.reverted[tx.Hash()] = true
+	if _, err := c.WaitMined(ctx, tx); err == nil {
+		t.Error("WaitMined() of a reverted transaction should fail")
+	}
+}
+
+func TestCalls(t *testing.T) {
+	c, backend, contracts := newTestClient(t)
+	ctx := context.Background()
+	adapter := common.HexToAddress(contracts.NegRiskAdapter)
+
+	backend.setResult(t, conditionalTokens, "payoutDenominator", big.NewInt(1))
+	backend.setResult(t, conditionalTokens, "isApprovedForAll", true)
+	backend.setResult(t, erc20, "allowance", big.NewInt(5e6))
+
+	if resolved, err := c.IsResolved(ctx, common.Hash{1}); err != nil || !resolved {
+		t.Errorf("IsResolved() = %v, %v, want true", resolved, err)
+	}
+	if approved, err := c.IsApprovedForAll(ctx, adapter); err != nil || !approved {
+		t.Errorf("IsApprovedForAll() = %v, %v, want true", approved, err)
+	}
+	if allowance, err := c.CollateralAllowance(ctx, adapter); err != nil || allowance.Int64() != 5e6 {
+		t.Errorf("CollateralAllowance() = %v, %v, want 5000000", allowance, err)
+	}
+	if _, err := c.BalanceOf(ctx, big.NewInt(1)); err == nil {
+		t.Error("BalanceOf() should fail when the call reverts")
+	}
+}
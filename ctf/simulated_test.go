@@ -0,0 +1,215 @@
+package ctf
+
+import (
+	"context"
+	"maps"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// stubConditionalTokens returns the code of a minimal stand-in for the
+// Conditional Tokens contract, enough to run the client against a real EVM.
+// splitPosition credits the caller amount of the outcome pair of
+// conditionId, mergePositions debits it and reverts beyond the balance,
+// redeemPositions clears a nonzero balance and balanceOf(owner, id) returns
+// the pair balance of the condition id. No collateral moves.
+func stubConditionalTokens() []byte {
+	// Jump targets depend on the size of the pushes jumping to them;
+	// assemble until they settle
+	labels := map[string]uint64{}
+	for {
+		p := program.New()
+		got := map[string]uint64{}
+		mark := func(name string) { _, got[name] = p.Jumpdest() }
+
+		// Dispatch on the selector in calldata[0:4]
+		for _, method := range []string{"splitPosition", "mergePositions", "redeemPositions", "balanceOf"} {
+			p.Push(0).Op(vm.CALLDATALOAD).Push(0xe0).Op(vm.SHR)
+			p.Push(conditionalTokens.Methods[method].ID).Op(vm.EQ)
+			p.Push(labels[method]).Op(vm.JUMPI)
+		}
+		mark("revert")
+		p.Push(0).Push(0).Op(vm.REVERT)
+
+		// pairSlot pushes keccak256(owner, calldata[id:id+32])
+		pairSlot := func(owner func(), id int) {
+			owner()
+			p.Push(0).Op(vm.MSTORE)
+			p.Push(id).Op(vm.CALLDATALOAD).Push(32).Op(vm.MSTORE)
+			p.Push(64).Push(0).Op(vm.KECCAK256)
+		}
+		caller := func() { p.Op(vm.CALLER) }
+		const conditionID, amount = 68, 132
+
+		// slot += amount
+		mark("splitPosition")
+		pairSlot(caller, conditionID)
+		p.Op(vm.DUP1, vm.SLOAD).Push(amount).Op(vm.CALLDATALOAD, vm.ADD, vm.SWAP1, vm.SSTORE, vm.STOP)
+
+		// require(amount <= slot); slot -= amount
+		mark("mergePositions")
+		pairSlot(caller, conditionID)
+		p.Op(vm.DUP1, vm.SLOAD).Push(amount).Op(vm.CALLDATALOAD, vm.DUP2, vm.DUP2, vm.GT)
+		p.Push(labels["revert"]).Op(vm.JUMPI)
+		p.Op(vm.SWAP1, vm.SUB, vm.SWAP1, vm.SSTORE, vm.STOP)
+
+		// require(slot != 0); slot = 0
+		mark("redeemPositions")
+		pairSlot(caller, conditionID)
+		p.Op(vm.DUP1, vm.SLOAD, vm.ISZERO).Push(labels["revert"]).Op(vm.JUMPI)
+		p.Push(0).Op(vm.SWAP1, vm.SSTORE, vm.STOP)
+
+		// return slot of (owner, id)
+		mark("balanceOf")
+		pairSlot(func() { p.Push(4).Op(vm.CALLDATALOAD) }, 36)
+		p.Op(vm.SLOAD).Push(0).Op(vm.MSTORE)
+		p.Return(0, 32)
+
+		if maps.Equal(labels, got) {
+			return p.Bytes()
+		}
+		labels = got
+	}
+}
+
+// newSimulatedClient returns a client on a simulated chain where the
+// conditional tokens of Amoy are the stub and the wallet has ether
+func newSimulatedClient(t *testing.T) (*Client, *simulated.Backend, *auth.Wallet) {
+	t.Helper()
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contracts, _ := types.GetContractConfig(types.ChainAmoy)
+	sim := simulated.NewBackend(gethtypes.GenesisAlloc{
+		wallet.GetAddress(): {Balance: big.NewInt(params.Ether)},
+		common.HexToAddress(contracts.ConditionalTokens): {Code: stubConditionalTokens()},
+	})
+	t.Cleanup(func() { sim.Close() })
+
+	c, err := NewClient(&Config{
+		Backend:   sim.Client(),
+		ChainID:   types.Chain(params.AllDevChainProtocolChanges.ChainID.Int64()),
+		Wallet:    wallet,
+		Contracts: &contracts,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return c, sim, wallet
+}
+
+// mine commits the pending transactions and checks that tx succeeded
+func mine(t *testing.T, c *Client, sim *simulated.Backend, tx *gethtypes.Transaction, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("send error = %v", err)
+	}
+	sim.Commit()
+	if _, err := c.WaitMined(context.Background(), tx); err != nil {
+		t.Fatalf("WaitMined() error = %v", err)
+	}
+}
+
+func TestSimulatedSplitMergeRedeem(t *testing.T) {
+	c, sim, _ := newSimulatedClient(t)
+	ctx := context.Background()
+	conditionID := common.HexToHash("0xc0ffee")
+	wantBalance := func(want int64) {
+		t.Helper()
+		balance, err := c.BalanceOf(ctx, conditionID.Big())
+		if err != nil || balance.Int64() != want {
+			t.Errorf("BalanceOf() = %v, %v, want %d", balance, err, want)
+		}
+	}
+
+	tx, err := c.SplitPosition(ctx, conditionID, big.NewInt(10e6), false)
+	mine(t, c, sim, tx, err)
+	wantBalance(10e6)
+
+	tx, err = c.MergePositions(ctx, conditionID, big.NewInt(4e6), false)
+	mine(t, c, sim, tx, err)
+	wantBalance(6e6)
+
+	if _, err := c.MergePositions(ctx, conditionID, big.NewInt(7e6), false); err == nil {
+		t.Error("MergePositions() beyond the balance should fail")
+	}
+
+	tx, err = c.RedeemPosition(ctx, data.Position{ConditionID: conditionID.Hex(), Redeemable: true})
+	mine(t, c, sim, tx, err)
+	wantBalance(0)
+
+	if _, err := c.RedeemPositions(ctx, conditionID); err == nil {
+		t.Error("RedeemPositions() without a balance should fail")
+	}
+}
+
+func TestSimulatedNonceRecovery(t *testing.T) {
+	c, sim, wallet := newSimulatedClient(t)
+	ctx := context.Background()
+	chainID := params.AllDevChainProtocolChanges.ChainID
+
+	// sendElsewhere sends a transaction of the wallet the client does not know about
+	sendElsewhere := func(nonce uint64) {
+		t.Helper()
+		to := common.Address{1}
+		tx, err := gethtypes.SignNewTx(wallet.GetPrivateKey(), gethtypes.LatestSignerForChainID(chainID), &gethtypes.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(100 * params.GWei),
+			Gas:       params.TxGas,
+			To:        &to,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sim.Client().SendTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+		sim.Commit()
+	}
+
+	// Transactions in flight take consecutive nonces
+	first, err := c.SplitPosition(ctx, common.Hash{1}, big.NewInt(1e6), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.SplitPosition(ctx, common.Hash{2}, big.NewInt(1e6), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Nonce() != 0 || second.Nonce() != 1 {
+		t.Errorf("nonces = %d, %d, want 0, 1", first.Nonce(), second.Nonce())
+	}
+	mine(t, c, sim, second, nil)
+
+	// The client reuses the nonce taken elsewhere, fails and reads it again
+	sendElsewhere(2)
+	if _, err := c.SplitPosition(ctx, common.Hash{3}, big.NewInt(1e6), false); err == nil {
+		t.Fatal("SplitPosition() with a used nonce should fail")
+	}
+	tx, err := c.SplitPosition(ctx, common.Hash{3}, big.NewInt(1e6), false)
+	mine(t, c, sim, tx, err)
+	if tx.Nonce() != 3 {
+		t.Errorf("nonce after a failure = %d, want 3", tx.Nonce())
+	}
+
+	sendElsewhere(4)
+	c.ResetNonce()
+	tx, err = c.SplitPosition(ctx, common.Hash{4}, big.NewInt(1e6), false)
+	mine(t, c, sim, tx, err)
+	if tx.Nonce() != 5 {
+		t.Errorf("nonce after ResetNonce() = %d, want 5", tx.Nonce())
+	}
+}
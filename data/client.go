@@ -1,14 +1,24 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/ybina/polymarket-sdk-go/internal/instrument"
+	"github.com/ybina/polymarket-sdk-go/internal/logging"
+	"github.com/ybina/polymarket-sdk-go/internal/query"
+	"github.com/ybina/polymarket-sdk-go/observe"
+	"github.com/ybina/polymarket-sdk-go/proxy"
 )
 
 const (
@@ -21,51 +31,57 @@ type DataSDK struct {
 	baseURL     string
 	proxyConfig *ProxyConfig
 	httpClient  *http.Client
+	userAgent   string
+	headers     http.Header
+	logger      *slog.Logger
+	observer    observe.Observer
 }
 
 // NewDataSDK creates a new Data SDK instance
 func NewDataSDK(config *DataSDKConfig) *DataSDK {
-	var proxyConfig *ProxyConfig
-	if config != nil {
-		proxyConfig = config.Proxy
-	}
-
-	// Create HTTP client with proxy if configured
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+	if config == nil {
+		config = &DataSDKConfig{}
 	}
+	proxyConfig := config.Proxy
 
-	// Configure proxy if provided
-	if proxyConfig != nil {
-		protocol := "http"
-		if proxyConfig.Protocol != nil {
-			protocol = *proxyConfig.Protocol
-		}
-
-		// Build proxy URL with authentication if provided
-		var proxyURL string
-		if proxyConfig.Username != nil && proxyConfig.Password != nil {
-			proxyURL = fmt.Sprintf("%s://%s:%s@%s:%d",
-				protocol, *proxyConfig.Username, *proxyConfig.Password, proxyConfig.Host, proxyConfig.Port)
-		} else {
-			proxyURL = fmt.Sprintf("%s://%s:%d", protocol, proxyConfig.Host, proxyConfig.Port)
+	// Create HTTP client
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: config.Transport,
 		}
+	}
 
-		parsedProxyURL, err := url.Parse(proxyURL)
-		if err != nil {
-			fmt.Printf("Warning: Failed to parse proxy URL %s: %v\n", proxyURL, err)
-		} else {
-			httpClient.Transport = &http.Transport{
-				Proxy: http.ProxyURL(parsedProxyURL),
-			}
-			fmt.Printf("✅ Proxy configured: %s\n", parsedProxyURL.String())
-		}
+	// Route through the proxy pool or proxy if provided; an invalid proxy
+	// fails each request with its validation error
+	var src proxy.Source
+	if config.ProxyPool != nil {
+		src = config.ProxyPool
+	} else if proxyConfig != nil {
+		src = proxyConfig
+	}
+	if src != nil && config.HTTPClient == nil && config.Transport == nil {
+		httpClient.Transport = proxy.NewTransport(src)
 	}
 
 	client := &DataSDK{
 		baseURL:     DataAPIBase,
 		proxyConfig: proxyConfig,
 		httpClient:  httpClient,
+		userAgent:   "data-go-sdk/1.0",
+		headers:     config.Headers.Clone(),
+		logger:      logging.OrDiscard(config.Logger),
+		observer:    observe.OrNop(config.Observer),
+	}
+	if config.BaseURL != "" {
+		client.baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	if ua := config.Headers.Get("User-Agent"); ua != "" {
+		client.userAgent = ua
+	}
+	if config.UserAgent != "" {
+		client.userAgent = config.UserAgent
 	}
 
 	return client
@@ -77,94 +93,42 @@ func (d *DataSDK) GetHttpClient() *http.Client {
 }
 
 // buildURL constructs a URL with query parameters
-func (d *DataSDK) buildURL(endpoint string, query interface{}) (string, error) {
+func (d *DataSDK) buildURL(endpoint string, q interface{}) (string, error) {
 	u, err := url.Parse(d.baseURL + endpoint)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse URL: %w", err)
 	}
 
-	if query != nil {
-		values := url.Values{}
-		v := reflect.ValueOf(query)
-
-		// Dereference pointer if necessary
-		if v.Kind() == reflect.Ptr {
-			if v.IsNil() {
-				return u.String(), nil
-			}
-			v = v.Elem()
-		}
-
-		t := v.Type()
-
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			fieldValue := v.Field(i)
-
-			// Skip nil pointer fields
-			if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
-				continue
-			}
-
-			// Get the JSON tag for the field name
-			jsonTag := field.Tag.Get("json")
-			if jsonTag == "" {
-				continue
-			}
-
-			// Handle omitempty
-			if strings.Contains(jsonTag, "omitempty") && fieldValue.IsZero() {
-				continue
-			}
-
-			// Extract field name from JSON tag
-			fieldName := strings.Split(jsonTag, ",")[0]
-			if fieldName == "" {
-				continue
-			}
-
-			// Convert value to string and add to query params
-			var strValue string
-			if fieldValue.Kind() == reflect.Ptr {
-				strValue = fmt.Sprintf("%v", fieldValue.Elem().Interface())
-			} else {
-				strValue = fmt.Sprintf("%v", fieldValue.Interface())
-			}
-
-			// Handle slice fields for array parameters
-			if fieldValue.Kind() == reflect.Slice {
-				slice := fieldValue.Interface()
-				if sliceValue, ok := slice.([]string); ok {
-					for _, item := range sliceValue {
-						values.Add(fieldName, item)
-					}
-				}
-			} else {
-				values.Add(fieldName, strValue)
-			}
-		}
-
-		u.RawQuery = values.Encode()
+	if err := query.Apply(u, q); err != nil {
+		return "", fmt.Errorf("failed to encode query: %w", err)
 	}
 
 	return u.String(), nil
 }
 
 // createRequest creates an HTTP request with proper headers and proxy support
-func (d *DataSDK) createRequest(method, url string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, nil)
+func (d *DataSDK) createRequest(ctx context.Context, method, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "data-go-sdk/1.0")
+	for key, values := range d.headers {
+		req.Header[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+	}
+	req.Header.Set("User-Agent", d.userAgent)
 
 	return req, nil
 }
 
 // makeRequest makes an HTTP request and returns the response
 func (d *DataSDK) makeRequest(method, endpoint string, query interface{}) (*APIResponse, error) {
+	return d.makeRequestContext(context.Background(), method, endpoint, query)
+}
+
+// makeRequestContext makes an HTTP request bound to ctx and returns the response
+func (d *DataSDK) makeRequestContext(ctx context.Context, method, endpoint string, query interface{}) (*APIResponse, error) {
 	// Build URL with query parameters
 	fullURL, err := d.buildURL(endpoint, query)
 	if err != nil {
@@ -172,13 +136,13 @@ func (d *DataSDK) makeRequest(method, endpoint string, query interface{}) (*APIR
 	}
 
 	// Create request
-	req, err := d.createRequest(method, fullURL)
+	req, err := d.createRequest(ctx, method, fullURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Make the request
-	resp, err := d.httpClient.Do(req)
+	resp, err := instrument.Do("data", d.httpClient, d.logger, d.observer, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -254,11 +218,15 @@ func (d *DataSDK) GetHealth() (*DataHealthResponse, error) {
 // Positions API
 // GetCurrentPositions gets current positions for a user
 func (d *DataSDK) GetCurrentPositions(query *PositionsQuery) ([]Position, error) {
+	return d.getCurrentPositions(context.Background(), query)
+}
+
+func (d *DataSDK) getCurrentPositions(ctx context.Context, query *PositionsQuery) ([]Position, error) {
 	if query == nil {
 		query = &PositionsQuery{}
 	}
 
-	resp, err := d.makeRequest("GET", "/positions", query)
+	resp, err := d.makeRequestContext(ctx, "GET", "/positions", query)
 	if err != nil {
 		return nil, err
 	}
@@ -268,11 +236,15 @@ func (d *DataSDK) GetCurrentPositions(query *PositionsQuery) ([]Position, error)
 
 // GetClosedPositions gets closed positions for a user
 func (d *DataSDK) GetClosedPositions(query *ClosedPositionsQuery) ([]ClosedPosition, error) {
+	return d.getClosedPositions(context.Background(), query)
+}
+
+func (d *DataSDK) getClosedPositions(ctx context.Context, query *ClosedPositionsQuery) ([]ClosedPosition, error) {
 	if query == nil {
 		query = &ClosedPositionsQuery{}
 	}
 
-	resp, err := d.makeRequest("GET", "/closed-positions", query)
+	resp, err := d.makeRequestContext(ctx, "GET", "/closed-positions", query)
 	if err != nil {
 		return nil, err
 	}
@@ -283,11 +255,15 @@ func (d *DataSDK) GetClosedPositions(query *ClosedPositionsQuery) ([]ClosedPosit
 // Trades API
 // GetTrades gets trades for users or markets
 func (d *DataSDK) GetTrades(query *TradesQuery) ([]DataTrade, error) {
+	return d.getTrades(context.Background(), query)
+}
+
+func (d *DataSDK) getTrades(ctx context.Context, query *TradesQuery) ([]DataTrade, error) {
 	if query == nil {
 		query = &TradesQuery{}
 	}
 
-	resp, err := d.makeRequest("GET", "/trades", query)
+	resp, err := d.makeRequestContext(ctx, "GET", "/trades", query)
 	if err != nil {
 		return nil, err
 	}
@@ -298,11 +274,15 @@ func (d *DataSDK) GetTrades(query *TradesQuery) ([]DataTrade, error) {
 // User Activity API
 // GetUserActivity gets user activity
 func (d *DataSDK) GetUserActivity(query *UserActivityQuery) ([]Activity, error) {
+	return d.getUserActivity(context.Background(), query)
+}
+
+func (d *DataSDK) getUserActivity(ctx context.Context, query *UserActivityQuery) ([]Activity, error) {
 	if query == nil {
 		query = &UserActivityQuery{}
 	}
 
-	resp, err := d.makeRequest("GET", "/activity", query)
+	resp, err := d.makeRequestContext(ctx, "GET", "/activity", query)
 	if err != nil {
 		return nil, err
 	}
@@ -385,16 +365,14 @@ func (d *DataSDK) GetLiveVolume(query *LiveVolumeQuery) (*LiveVolumeResponse, er
 
 // Convenience methods
 
-// GetAllPositions gets all positions (current and closed) for a user
-func (d *DataSDK) GetAllPositions(user string, options *struct {
-	Limit          *int
-	Offset         *int
-	SortBy         *string
-	SortDirection  *string
-}) (*struct {
-	Current []Position
-	Closed  []ClosedPosition
-}, error) {
+// GetAllPositions gets all positions (current and closed) for a user, paging
+// through every result. options may be nil. When one of the two fetches
+// fails, the positions from the other are still returned along with the error.
+func (d *DataSDK) GetAllPositions(user string, options *PortfolioOptions) (*AllPositions, error) {
+	if options == nil {
+		options = &PortfolioOptions{}
+	}
+
 	// Build queries for both endpoints
 	currentQuery := &PositionsQuery{
 		User:          &user,
@@ -408,107 +386,89 @@ func (d *DataSDK) GetAllPositions(user string, options *struct {
 		User:          &user,
 		Limit:         options.Limit,
 		Offset:        options.Offset,
-		SortBy:        options.SortBy,
+		SortBy:        options.ClosedSortBy,
 		SortDirection: options.SortDirection,
 	}
 
 	// Fetch both in parallel
-	currentChan := make(chan []Position, 1)
-	closedChan := make(chan []ClosedPosition, 1)
-	currentErrChan := make(chan error, 1)
-	closedErrChan := make(chan error, 1)
+	ctx := context.Background()
+	var result AllPositions
+	var currentErr, closedErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
 
 	go func() {
-		positions, err := d.GetCurrentPositions(currentQuery)
-		currentChan <- positions
-		currentErrChan <- err
+		defer wg.Done()
+		result.Current, currentErr = collect(d.IterPositions(ctx, currentQuery, nil))
 	}()
 
 	go func() {
-		positions, err := d.GetClosedPositions(closedQuery)
-		closedChan <- positions
-		closedErrChan <- err
+		defer wg.Done()
+		result.Closed, closedErr = collect(d.IterClosedPositions(ctx, closedQuery, nil))
 	}()
 
-	currentPositions := <-currentChan
-	closedPositions := <-closedChan
-	currentErr := <-currentErrChan
-	closedErr := <-closedErrChan
+	wg.Wait()
 
+	var errs []error
 	if currentErr != nil {
-		return nil, fmt.Errorf("failed to get current positions: %w", currentErr)
+		errs = append(errs, fmt.Errorf("failed to get current positions: %w", currentErr))
 	}
 	if closedErr != nil {
-		return nil, fmt.Errorf("failed to get closed positions: %w", closedErr)
+		errs = append(errs, fmt.Errorf("failed to get closed positions: %w", closedErr))
 	}
 
-	return &struct {
-		Current []Position
-		Closed  []ClosedPosition
-	}{
-		Current: currentPositions,
-		Closed:  closedPositions,
-	}, nil
+	return &result, errors.Join(errs...)
 }
 
-// GetPortfolioSummary gets comprehensive portfolio summary for a user
-func (d *DataSDK) GetPortfolioSummary(user string) (*struct {
-	TotalValue       []TotalValue
-	MarketsTraded    *TotalMarketsTraded
-	CurrentPositions []Position
-}, error) {
+// GetPortfolioSummary gets comprehensive portfolio summary for a user: total
+// value, markets traded and every current position, broken down by event and
+// category with redeemable and mergeable totals and the top winners and
+// losers. options may be nil; Limit, Offset and the sort fields are ignored.
+//
+// The three fetches run concurrently. When some fail, the summary holds what
+// the others returned and the error joins the failures.
+func (d *DataSDK) GetPortfolioSummary(user string, options *PortfolioOptions) (*Portfolio, error) {
+	if options == nil {
+		options = &PortfolioOptions{}
+	}
+
 	// Fetch all data in parallel
-	totalValueChan := make(chan []TotalValue, 1)
-	marketsTradedChan := make(chan *TotalMarketsTraded, 1)
-	positionsChan := make(chan []Position, 1)
-	totalValueErrChan := make(chan error, 1)
-	marketsTradedErrChan := make(chan error, 1)
-	positionsErrChan := make(chan error, 1)
+	ctx := context.Background()
+	var portfolio Portfolio
+	var totalValueErr, marketsTradedErr, positionsErr error
+	var wg sync.WaitGroup
+	wg.Add(3)
 
 	go func() {
-		value, err := d.GetTotalValue(&TotalValueQuery{User: &user})
-		totalValueChan <- value
-		totalValueErrChan <- err
+		defer wg.Done()
+		portfolio.TotalValue, totalValueErr = d.GetTotalValue(&TotalValueQuery{User: &user})
 	}()
 
 	go func() {
-		traded, err := d.GetTotalMarketsTraded(&TotalMarketsTradedQuery{User: &user})
-		marketsTradedChan <- traded
-		marketsTradedErrChan <- err
+		defer wg.Done()
+		portfolio.MarketsTraded, marketsTradedErr = d.GetTotalMarketsTraded(&TotalMarketsTradedQuery{User: &user})
 	}()
 
 	go func() {
-		positions, err := d.GetCurrentPositions(&PositionsQuery{User: &user})
-		positionsChan <- positions
-		positionsErrChan <- err
+		defer wg.Done()
+		portfolio.CurrentPositions, positionsErr = collect(d.IterPositions(ctx, &PositionsQuery{User: &user}, nil))
 	}()
 
-	totalValue := <-totalValueChan
-	marketsTraded := <-marketsTradedChan
-	positions := <-positionsChan
-	totalValueErr := <-totalValueErrChan
-	marketsTradedErr := <-marketsTradedErrChan
-	positionsErr := <-positionsErrChan
+	wg.Wait()
 
+	var errs []error
 	if totalValueErr != nil {
-		return nil, fmt.Errorf("failed to get total value: %w", totalValueErr)
+		errs = append(errs, fmt.Errorf("failed to get total value: %w", totalValueErr))
 	}
 	if marketsTradedErr != nil {
-		return nil, fmt.Errorf("failed to get markets traded: %w", marketsTradedErr)
+		errs = append(errs, fmt.Errorf("failed to get markets traded: %w", marketsTradedErr))
 	}
 	if positionsErr != nil {
-		return nil, fmt.Errorf("failed to get current positions: %w", positionsErr)
-	}
-
-	return &struct {
-		TotalValue       []TotalValue
-		MarketsTraded    *TotalMarketsTraded
-		CurrentPositions []Position
-	}{
-		TotalValue:       totalValue,
-		MarketsTraded:    marketsTraded,
-		CurrentPositions: positions,
-	}, nil
+		errs = append(errs, fmt.Errorf("failed to get current positions: %w", positionsErr))
+	}
+
+	portfolio.summarize(options)
+	return &portfolio, errors.Join(errs...)
 }
 
 // Unmarshal helper methods
@@ -0,0 +1,63 @@
+package data
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestNewDataSDKConfig(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"data": "OK"}`))
+	}))
+	defer srv.Close()
+
+	transported := 0
+	tests := []struct {
+		name      string
+		config    *DataSDKConfig
+		wantAgent string
+	}{
+		{name: "defaults", config: &DataSDKConfig{BaseURL: srv.URL + "/"}, wantAgent: "data-go-sdk/1.0"},
+		{name: "http client", config: &DataSDKConfig{BaseURL: srv.URL, HTTPClient: &http.Client{}, UserAgent: "bot/2"}, wantAgent: "bot/2"},
+		{
+			name: "transport",
+			config: &DataSDKConfig{BaseURL: srv.URL, Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				transported++
+				return http.DefaultTransport.RoundTrip(r)
+			})},
+			wantAgent: "data-go-sdk/1.0",
+		},
+		{name: "header user agent", config: &DataSDKConfig{BaseURL: srv.URL, Headers: http.Header{"User-Agent": {"bot/1"}}}, wantAgent: "bot/1"},
+		{name: "user agent over header", config: &DataSDKConfig{BaseURL: srv.URL, UserAgent: "bot/2", Headers: http.Header{"User-Agent": {"bot/1"}}}, wantAgent: "bot/2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.config.Headers == nil {
+				tt.config.Headers = http.Header{}
+			}
+			tt.config.Headers.Set("X-Desk", "alpha")
+			sdk := NewDataSDK(tt.config)
+			if _, err := sdk.GetHealth(); err != nil {
+				t.Fatalf("GetHealth() error = %v", err)
+			}
+			if got.URL.Path != "/" || got.UserAgent() != tt.wantAgent || got.Header.Get("X-Desk") != "alpha" {
+				t.Errorf("request %s with User-Agent %q and X-Desk %q", got.URL.Path, got.UserAgent(), got.Header.Get("X-Desk"))
+			}
+			if tt.config.HTTPClient != nil && sdk.GetHttpClient() != tt.config.HTTPClient {
+				t.Error("GetHttpClient() is not the configured client")
+			}
+		})
+	}
+	if transported != 1 {
+		t.Errorf("transport used %d times, want 1", transported)
+	}
+}
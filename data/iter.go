@@ -0,0 +1,239 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/internal/paging"
+)
+
+const (
+	// DefaultPageSize is the page size used by the Iter methods when none is set
+	DefaultPageSize = 100
+	// MaxOffset is the largest offset the Data API accepts on its paginated endpoints
+	MaxOffset = 10000
+)
+
+// ErrOffsetCeiling is yielded when the remaining results lie beyond MaxOffset
+// and cannot be reached by narrowing the time window instead
+var ErrOffsetCeiling = errors.New("data api offset ceiling reached")
+
+// IterOptions controls how the Iter methods page through results
+type IterOptions struct {
+	// PageSize is the number of items requested per page. Defaults to the
+	// query's Limit, or DefaultPageSize when that is unset too.
+	PageSize int
+	// Prefetch is the number of pages requested ahead, concurrently, while
+	// the current page is consumed. 0 fetches one page at a time.
+	Prefetch int
+}
+
+// pageParams resolves the page size and prefetch depth for an iterator
+func (o *IterOptions) pageParams(limit *int) (int, int) {
+	pageSize, prefetch := DefaultPageSize, 0
+	if limit != nil && *limit > 0 {
+		pageSize = *limit
+	}
+	if o != nil {
+		if o.PageSize > 0 {
+			pageSize = o.PageSize
+		}
+		if o.Prefetch > 0 {
+			prefetch = o.Prefetch
+		}
+	}
+	return pageSize, prefetch
+}
+
+// startOffset returns the offset iteration starts from
+func startOffset(offset *int) int {
+	if offset != nil && *offset > 0 {
+		return *offset
+	}
+	return 0
+}
+
+// capped wraps fetch so that pages past MaxOffset fail with ErrOffsetCeiling
+// instead of being requested
+func capped[T any](fetch paging.FetchFunc[T]) paging.FetchFunc[T] {
+	return func(ctx context.Context, limit, offset int) ([]T, error) {
+		if offset > MaxOffset {
+			return nil, ErrOffsetCeiling
+		}
+		return fetch(ctx, limit, offset)
+	}
+}
+
+// IterPositions iterates over every current position matching query, paging
+// transparently. The query's Offset sets where iteration starts; Limit is only
+// used as the default page size. Items already yielded stay valid when a later
+// page fails: the error is yielded once as the final element, and is
+// ErrOffsetCeiling when more positions exist past MaxOffset.
+func (d *DataSDK) IterPositions(ctx context.Context, query *PositionsQuery, options *IterOptions) iter.Seq2[Position, error] {
+	base := PositionsQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	return paging.Iterate(ctx, startOffset(base.Offset), pageSize, prefetch, capped(func(ctx context.Context, limit, offset int) ([]Position, error) {
+		q := base
+		q.Limit, q.Offset = &limit, &offset
+		return d.getCurrentPositions(ctx, &q)
+	}))
+}
+
+// IterClosedPositions iterates over every closed position matching query,
+// paging transparently. See IterPositions for how the query and errors are
+// handled.
+func (d *DataSDK) IterClosedPositions(ctx context.Context, query *ClosedPositionsQuery, options *IterOptions) iter.Seq2[ClosedPosition, error] {
+	base := ClosedPositionsQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	return paging.Iterate(ctx, startOffset(base.Offset), pageSize, prefetch, capped(func(ctx context.Context, limit, offset int) ([]ClosedPosition, error) {
+		q := base
+		q.Limit, q.Offset = &limit, &offset
+		return d.getClosedPositions(ctx, &q)
+	}))
+}
+
+// IterTrades iterates over every trade matching query, paging transparently.
+// See IterPositions for how the query and errors are handled; the trades
+// endpoint has no time filter, so history past MaxOffset is out of reach.
+func (d *DataSDK) IterTrades(ctx context.Context, query *TradesQuery, options *IterOptions) iter.Seq2[DataTrade, error] {
+	base := TradesQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	return paging.Iterate(ctx, startOffset(base.Offset), pageSize, prefetch, capped(func(ctx context.Context, limit, offset int) ([]DataTrade, error) {
+		q := base
+		q.Limit, q.Offset = &limit, &offset
+		return d.getTrades(ctx, &q)
+	}))
+}
+
+// activityKey identifies an activity entry when windows overlap on a timestamp
+type activityKey struct {
+	tx       string
+	asset    string
+	kind     ActivityType
+	side     string
+	size     float64
+	usdcSize float64
+}
+
+func keyOf(a Activity) activityKey {
+	return activityKey{
+		tx:       a.TransactionHash,
+		asset:    a.AssetID,
+		kind:     a.Type,
+		side:     string(a.Side),
+		size:     a.Size,
+		usdcSize: a.UsdcSize,
+	}
+}
+
+// IterActivity iterates over a user's complete activity matching query. It
+// pages by offset and, on reaching MaxOffset, continues in a narrower time
+// window that starts at the last timestamp seen, so histories of any length
+// are covered. Entries on the window boundary are yielded once.
+//
+// Results are sorted by timestamp, descending unless query asks for ASC;
+// other sort keys cannot be windowed and stop with ErrOffsetCeiling instead.
+// Start and End bound the whole iteration. Errors are handled as in
+// IterPositions.
+func (d *DataSDK) IterActivity(ctx context.Context, query *UserActivityQuery, options *IterOptions) iter.Seq2[Activity, error] {
+	base := UserActivityQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	windowed := base.SortBy == nil || *base.SortBy == ActivitySortByTimestamp
+	if windowed {
+		sortBy := ActivitySortByTimestamp
+		base.SortBy = &sortBy
+	}
+	ascending := base.SortDirection != nil && *base.SortDirection == SortAsc
+
+	return func(yield func(Activity, error) bool) {
+		start := startOffset(base.Offset)
+		window := base
+		seen := make(map[activityKey]bool) // entries at boundary, already yielded
+		for {
+			var boundary int64
+			var atBoundary map[activityKey]bool
+			ceiling := false
+			for activity, err := range paging.Iterate(ctx, start, pageSize, prefetch, capped(func(ctx context.Context, limit, offset int) ([]Activity, error) {
+				q := window
+				q.Limit, q.Offset = &limit, &offset
+				return d.getUserActivity(ctx, &q)
+			})) {
+				if errors.Is(err, ErrOffsetCeiling) && windowed {
+					ceiling = true
+					break
+				}
+				if err != nil {
+					yield(Activity{}, err)
+					return
+				}
+
+				if activity.Timestamp != boundary || atBoundary == nil {
+					boundary = activity.Timestamp
+					atBoundary = make(map[activityKey]bool)
+				}
+				key := keyOf(activity)
+				atBoundary[key] = true
+				if seen[key] {
+					continue
+				}
+				if !yield(activity, nil) {
+					return
+				}
+			}
+
+			if !ceiling {
+				return
+			}
+			if atBoundary == nil {
+				yield(Activity{}, ErrOffsetCeiling)
+				return
+			}
+
+			// Narrow the window to the last timestamp seen. Its bound is
+			// inclusive, so entries there are fetched again and skipped.
+			next := time.Unix(boundary, 0)
+			bound := &window.End
+			if ascending {
+				bound = &window.Start
+			}
+			if *bound != nil && (*bound).Equal(next) {
+				yield(Activity{}, fmt.Errorf("%w: more than %d activities at %s", ErrOffsetCeiling, MaxOffset, next.UTC().Format(time.RFC3339)))
+				return
+			}
+			*bound = &next
+			seen = atBoundary
+			start = 0
+		}
+	}
+}
+
+// collect drains seq into a slice, stopping at the first error
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,204 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// activityServer serves /activity from history, honouring limit, offset,
+// start, end and sortDirection and rejecting offsets past MaxOffset
+func activityServer(t *testing.T, history []Activity) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		if offset > MaxOffset {
+			http.Error(w, `{"error": "offset too large"}`, http.StatusBadRequest)
+			return
+		}
+		if q.Get("sortBy") != "TIMESTAMP" {
+			t.Errorf("sortBy = %q, want TIMESTAMP", q.Get("sortBy"))
+		}
+
+		var items []Activity
+		for _, a := range history {
+			if s := q.Get("start"); s != "" {
+				if start, _ := strconv.ParseInt(s, 10, 64); a.Timestamp < start {
+					continue
+				}
+			}
+			if e := q.Get("end"); e != "" {
+				if end, _ := strconv.ParseInt(e, 10, 64); a.Timestamp > end {
+					continue
+				}
+			}
+			items = append(items, a)
+		}
+		asc := q.Get("sortDirection") == "ASC"
+		sort.SliceStable(items, func(i, j int) bool {
+			if asc {
+				return items[i].Timestamp < items[j].Timestamp
+			}
+			return items[i].Timestamp > items[j].Timestamp
+		})
+
+		items = items[min(offset, len(items)):]
+		items = items[:min(limit, len(items))]
+		json.NewEncoder(w).Encode(items)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// paged returns the part of items selected by the limit and offset of r, as
+// the Data API does
+func paged[T any](r *http.Request, items []T) []T {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	items = items[min(max(offset, 0), len(items)):]
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil {
+		items = items[:min(limit, len(items))]
+	}
+	return items
+}
+
+// makeHistory builds n activities, perTimestamp of them sharing each second
+func makeHistory(n, perTimestamp int) []Activity {
+	history := make([]Activity, n)
+	for i := range history {
+		history[i] = Activity{
+			Timestamp:       1700000000 + int64(i/perTimestamp),
+			Type:            ActivityTrade,
+			TransactionHash: fmt.Sprintf("0x%x", i),
+		}
+	}
+	return history
+}
+
+func TestIterActivityWindowsPastOffsetCeiling(t *testing.T) {
+	history := makeHistory(23456, 7)
+
+	for _, direction := range []SortDirection{SortDesc, SortAsc} {
+		t.Run(string(direction), func(t *testing.T) {
+			srv, _ := activityServer(t, history)
+			sdk := NewDataSDK(nil)
+			sdk.baseURL = srv.URL
+
+			query := &UserActivityQuery{SortDirection: &direction}
+			seen := make(map[string]bool)
+			last := int64(-1)
+			for activity, err := range sdk.IterActivity(t.Context(), query, &IterOptions{PageSize: 500, Prefetch: 2}) {
+				if err != nil {
+					t.Fatalf("IterActivity() error = %v", err)
+				}
+				if seen[activity.TransactionHash] {
+					t.Fatalf("activity %s yielded twice", activity.TransactionHash)
+				}
+				seen[activity.TransactionHash] = true
+				if last >= 0 && ((direction == SortDesc && activity.Timestamp > last) || (direction == SortAsc && activity.Timestamp < last)) {
+					t.Fatalf("activity %s out of order", activity.TransactionHash)
+				}
+				last = activity.Timestamp
+			}
+			if len(seen) != len(history) {
+				t.Errorf("got %d activities, want %d", len(seen), len(history))
+			}
+		})
+	}
+}
+
+func TestIterActivityStopsWhenWindowCannotNarrow(t *testing.T) {
+	srv, _ := activityServer(t, makeHistory(MaxOffset+1000, MaxOffset+1000))
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	count := 0
+	var gotErr error
+	for _, err := range sdk.IterActivity(t.Context(), nil, &IterOptions{PageSize: 500}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		count++
+	}
+	if !errors.Is(gotErr, ErrOffsetCeiling) {
+		t.Fatalf("IterActivity() error = %v, want ErrOffsetCeiling", gotErr)
+	}
+	if count != MaxOffset+500 {
+		t.Errorf("got %d activities before the error, want %d", count, MaxOffset+500)
+	}
+}
+
+func TestIterActivityKeepsBounds(t *testing.T) {
+	srv, requests := activityServer(t, makeHistory(100, 1))
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	start, end := time.Unix(1700000010, 0), time.Unix(1700000019, 0)
+	activities, err := collect(sdk.IterActivity(t.Context(), &UserActivityQuery{Start: &start, End: &end}, nil))
+	if err != nil {
+		t.Fatalf("IterActivity() error = %v", err)
+	}
+	if len(activities) != 10 || activities[0].Timestamp != end.Unix() || activities[9].Timestamp != start.Unix() {
+		t.Errorf("got %d activities from %d to %d", len(activities), activities[0].Timestamp, activities[len(activities)-1].Timestamp)
+	}
+	// The short page may mean a capped page size, so one more page is
+	// requested to find the end
+	if got := requests.Load(); got != 2 {
+		t.Errorf("made %d requests, want 2", got)
+	}
+}
+
+func TestIterTradesStopsAtOffsetCeiling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		json.NewEncoder(w).Encode(make([]DataTrade, limit))
+	}))
+	defer srv.Close()
+
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	trades, err := collect(sdk.IterTrades(t.Context(), nil, &IterOptions{PageSize: 1000}))
+	if !errors.Is(err, ErrOffsetCeiling) {
+		t.Fatalf("IterTrades() error = %v, want ErrOffsetCeiling", err)
+	}
+	if len(trades) != MaxOffset+1000 {
+		t.Errorf("got %d trades, want %d", len(trades), MaxOffset+1000)
+	}
+}
+
+func TestGetAllPositionsPagesFully(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		total := 250
+		if r.URL.Path == "/closed-positions" {
+			total = 120
+		}
+		json.NewEncoder(w).Encode(make([]Position, max(0, min(limit, total-offset))))
+	}))
+	defer srv.Close()
+
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	limit := 100
+	all, err := sdk.GetAllPositions("0xabc", &PortfolioOptions{Limit: &limit})
+	if err != nil {
+		t.Fatalf("GetAllPositions() error = %v", err)
+	}
+	if len(all.Current) != 250 || len(all.Closed) != 120 {
+		t.Errorf("got %d current and %d closed positions, want 250 and 120", len(all.Current), len(all.Closed))
+	}
+}
@@ -0,0 +1,561 @@
+package data
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// CostMethod selects how disposed tokens are matched against their purchases
+type CostMethod int
+
+const (
+	// FIFO disposes of the oldest lots first
+	FIFO CostMethod = iota
+	// AverageCost disposes at the running average price, as the Data API does
+	AverageCost
+)
+
+// String returns the method name
+func (m CostMethod) String() string {
+	switch m {
+	case FIFO:
+		return "FIFO"
+	case AverageCost:
+		return "AVERAGE_COST"
+	default:
+		return fmt.Sprintf("CostMethod(%d)", int(m))
+	}
+}
+
+// OutcomeKey identifies one outcome token of a market
+type OutcomeKey struct {
+	ConditionID  string
+	OutcomeIndex int
+}
+
+// lot is a block of tokens bought at one price per token, fees included
+type lot struct {
+	size  float64
+	price float64
+}
+
+// AssetPnL is the replayed accounting state of one outcome token
+type AssetPnL struct {
+	ConditionID  string
+	OutcomeIndex int
+	Asset        string // Token ID, empty until an entry names it
+	Title        string
+	Outcome      string
+
+	Size        float64 // Tokens held
+	CostBasis   float64 // Cost of the tokens held, fees included
+	RealizedPnl float64 // Proceeds minus cost of the tokens disposed of
+	Fees        float64 // Trading fees paid
+	Bought      float64 // Tokens acquired by trades and splits
+	Sold        float64 // Tokens disposed of by trades, merges, redemptions and conversions
+	// Unmatched counts tokens disposed of without a recorded acquisition,
+	// e.g. because the history starts after they were bought. They are
+	// booked at zero cost.
+	Unmatched float64
+	Converted float64 // Tokens disposed of by conversions
+
+	lots []lot
+}
+
+// AvgPrice returns the average cost per token held
+func (a *AssetPnL) AvgPrice() float64 {
+	if a.Size <= 0 {
+		return 0
+	}
+	return a.CostBasis / a.Size
+}
+
+// UnrealizedPnl returns the gain on the tokens held when marked at price
+func (a *AssetPnL) UnrealizedPnl(price float64) float64 {
+	return a.Size*price - a.CostBasis
+}
+
+// acquire adds size tokens costing cost in total
+func (a *AssetPnL) acquire(method CostMethod, size, cost float64) {
+	if size <= 0 {
+		return
+	}
+	a.Size += size
+	a.CostBasis += cost
+	a.Bought += size
+
+	if method == AverageCost {
+		a.lots = []lot{{size: a.Size, price: a.CostBasis / a.Size}}
+		return
+	}
+	a.lots = append(a.lots, lot{size: size, price: cost / size})
+}
+
+// dispose removes size tokens for proceeds in total and books the gain
+func (a *AssetPnL) dispose(size, proceeds float64) {
+	if size <= 0 {
+		a.RealizedPnl += proceeds
+		return
+	}
+
+	remaining, cost := size, 0.0
+	for remaining > 0 && len(a.lots) > 0 {
+		take := min(remaining, a.lots[0].size)
+		cost += take * a.lots[0].price
+		remaining -= take
+		a.lots[0].size -= take
+		if a.lots[0].size <= dust {
+			a.lots = a.lots[1:]
+		}
+	}
+
+	matched := size - remaining
+	if remaining > dust {
+		a.Unmatched += remaining
+	}
+	a.Size -= matched
+	a.CostBasis -= cost
+	if a.Size <= dust {
+		a.Size, a.CostBasis, a.lots = 0, 0, nil
+	}
+	a.Sold += size
+	a.RealizedPnl += proceeds - cost
+}
+
+// dust is the token amount below which a holding counts as empty
+const dust = 1e-9
+
+// NegRiskResolver returns the condition IDs of every market of the neg risk
+// event that conditionID belongs to, conditionID included, e.g. through
+// gamma.GammaSDK.NegRiskConditionIDs
+type NegRiskResolver func(conditionID string) ([]string, error)
+
+// Ledger replays Data API activity into per-outcome cost basis, realized PnL
+// and fees. Polymarket markets are binary, so splits, merges and redemptions
+// apply to outcome indexes 0 and 1 of their condition.
+type Ledger struct {
+	method CostMethod
+	assets map[OutcomeKey]*AssetPnL
+	events map[string][]string // Resolved neg risk events by condition ID
+
+	Funded  float64 // USDC moved in by FUND entries
+	Rewards float64 // USDC received as REWARD entries
+
+	// Markets resolves the neg risk event of converted tokens, whose other
+	// markets receive the Yes tokens a conversion mints. Replaying a
+	// conversion without it fails.
+	Markets NegRiskResolver
+}
+
+// NewLedger creates an empty ledger using method
+func NewLedger(method CostMethod) *Ledger {
+	return &Ledger{
+		method: method,
+		assets: make(map[OutcomeKey]*AssetPnL),
+		events: make(map[string][]string),
+	}
+}
+
+// asset returns the state for key, creating it on first use
+func (l *Ledger) asset(key OutcomeKey) *AssetPnL {
+	a, ok := l.assets[key]
+	if !ok {
+		a = &AssetPnL{ConditionID: key.ConditionID, OutcomeIndex: key.OutcomeIndex}
+		l.assets[key] = a
+	}
+	return a
+}
+
+// entryAsset returns the state for the outcome an entry names, filling in
+// its descriptive fields
+func (l *Ledger) entryAsset(activity Activity) *AssetPnL {
+	a := l.asset(OutcomeKey{ConditionID: activity.ConditionID, OutcomeIndex: activity.OutcomeIndex})
+	if activity.AssetID != "" {
+		a.Asset = activity.AssetID
+	}
+	if activity.Title != "" {
+		a.Title = activity.Title
+	}
+	if activity.Outcome != "" {
+		a.Outcome = activity.Outcome
+	}
+	return a
+}
+
+// binary returns both outcomes of an entry's condition
+func (l *Ledger) binary(activity Activity) [2]*AssetPnL {
+	var outcomes [2]*AssetPnL
+	for i := range outcomes {
+		outcomes[i] = l.asset(OutcomeKey{ConditionID: activity.ConditionID, OutcomeIndex: i})
+		if activity.Title != "" {
+			outcomes[i].Title = activity.Title
+		}
+	}
+	return outcomes
+}
+
+// Apply books one activity entry.
+//
+//   - TRADE buys or sells Size tokens for UsdcSize; fees add to the cost of
+//     a buy and reduce the proceeds of a sell.
+//   - SPLIT turns UsdcSize into Size tokens of each outcome, each costing half.
+//   - MERGE turns Size tokens of each outcome back into UsdcSize.
+//   - REDEEM burns every token of the condition for UsdcSize, credited to
+//     the outcome whose holding matches the payout, or pro rata by size.
+//   - CONVERSION disposes of Size tokens of the entry's outcome for UsdcSize
+//     and mints Size Yes tokens in every other market of its neg risk event,
+//     resolved with Markets, which share UsdcSize as their cost. Apply takes
+//     each entry as a conversion of its own; Replay groups the entries of
+//     one transaction, which converted several markets.
+//   - FUND and REWARD are tallied in Funded and Rewards.
+func (l *Ledger) Apply(activity Activity) error {
+	if activity.Type == ActivityConversion {
+		return l.convert([]Activity{activity})
+	}
+
+	fee := 0.0
+	if activity.Fee != nil {
+		fee = *activity.Fee
+	}
+	usdc := usdcSize(activity)
+
+	switch activity.Type {
+	case ActivityTrade:
+		a := l.entryAsset(activity)
+		a.Fees += fee
+		switch activity.Side {
+		case types.SideBuy:
+			a.acquire(l.method, activity.Size, usdc+fee)
+		case types.SideSell:
+			a.dispose(activity.Size, usdc-fee)
+		default:
+			return fmt.Errorf("trade %s has invalid side %q", activity.TransactionHash, activity.Side)
+		}
+
+	case ActivitySplit:
+		for _, a := range l.binary(activity) {
+			a.acquire(l.method, activity.Size, usdc/2)
+		}
+
+	case ActivityMerge:
+		for _, a := range l.binary(activity) {
+			a.dispose(activity.Size, usdc/2)
+		}
+
+	case ActivityRedeem:
+		outcomes := l.binary(activity)
+		winner := redeemWinner(outcomes, usdc)
+		held := outcomes[0].Size + outcomes[1].Size
+		for i, a := range outcomes {
+			share := usdc / 2
+			switch {
+			case winner >= 0 && i == winner:
+				share = usdc
+			case winner >= 0:
+				share = 0
+			case held > 0:
+				share = usdc * a.Size / held
+			}
+			a.dispose(a.Size, share)
+		}
+
+	case ActivityFund:
+		l.Funded += usdc
+
+	case ActivityReward:
+		l.Rewards += usdc
+
+	default:
+		return fmt.Errorf("unsupported activity type %q in %s", activity.Type, activity.TransactionHash)
+	}
+
+	return nil
+}
+
+// usdcSize returns the USDC an entry moved, computed from its price when the
+// API leaves UsdcSize empty
+func usdcSize(activity Activity) float64 {
+	if activity.UsdcSize == 0 && activity.Price != nil {
+		return activity.Size * *activity.Price
+	}
+	return activity.UsdcSize
+}
+
+// convert books the entries of one conversion: their No tokens are disposed
+// of and the other markets of the event receive Yes tokens costing the
+// entries' USDC
+func (l *Ledger) convert(entries []Activity) error {
+	conditionID := entries[0].ConditionID
+	markets, ok := l.events[conditionID]
+	if !ok {
+		if l.Markets == nil {
+			return fmt.Errorf("conversion %s needs Ledger.Markets to resolve its event", entries[0].TransactionHash)
+		}
+		var err error
+		if markets, err = l.Markets(conditionID); err != nil {
+			return fmt.Errorf("failed to resolve the event of conversion %s: %w", entries[0].TransactionHash, err)
+		}
+		for _, market := range markets {
+			l.events[market] = markets
+		}
+	}
+
+	converted := make(map[string]bool, len(entries))
+	size, usdc := entries[0].Size, 0.0
+	for _, entry := range entries {
+		value := usdcSize(entry)
+		a := l.entryAsset(entry)
+		a.dispose(entry.Size, value)
+		a.Converted += entry.Size
+		converted[entry.ConditionID] = true
+		usdc += value
+	}
+
+	var minted []string
+	for _, market := range markets {
+		if !converted[market] {
+			minted = append(minted, market)
+		}
+	}
+	for _, market := range minted {
+		l.asset(OutcomeKey{ConditionID: market}).acquire(l.method, size, usdc/float64(len(minted)))
+	}
+	return nil
+}
+
+// redeemWinner returns the index of the only outcome whose holding equals
+// payout, or -1 when the payout cannot be attributed that way
+func redeemWinner(outcomes [2]*AssetPnL, payout float64) int {
+	const epsilon = 1e-6
+	match0 := math.Abs(outcomes[0].Size-payout) <= epsilon
+	match1 := math.Abs(outcomes[1].Size-payout) <= epsilon
+	switch {
+	case match0 && !match1:
+		return 0
+	case match1 && !match0:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// replayRank orders entries sharing a timestamp so acquisitions come first
+func replayRank(activity Activity) int {
+	switch {
+	case activity.Type == ActivityFund:
+		return 0
+	case activity.Type == ActivitySplit:
+		return 1
+	case activity.Type == ActivityTrade && activity.Side == types.SideBuy:
+		return 2
+	case activity.Type == ActivityMerge:
+		return 4
+	case activity.Type == ActivityRedeem:
+		return 5
+	default:
+		return 3
+	}
+}
+
+// Replay applies activities oldest first, whatever order they come in.
+// Entries with the same timestamp are applied acquisitions first, and the
+// conversion entries of one transaction together.
+func (l *Ledger) Replay(activities []Activity) error {
+	sorted := slices.Clone(activities)
+	slices.SortStableFunc(sorted, func(a, b Activity) int {
+		return cmp.Or(cmp.Compare(a.Timestamp, b.Timestamp), cmp.Compare(replayRank(a), replayRank(b)))
+	})
+
+	conversions := make(map[string][]Activity)
+	for _, activity := range sorted {
+		if activity.Type == ActivityConversion && activity.TransactionHash != "" {
+			conversions[activity.TransactionHash] = append(conversions[activity.TransactionHash], activity)
+		}
+	}
+
+	for _, activity := range sorted {
+		if activity.Type != ActivityConversion || activity.TransactionHash == "" {
+			if err := l.Apply(activity); err != nil {
+				return err
+			}
+			continue
+		}
+		entries := conversions[activity.TransactionHash]
+		if entries == nil {
+			continue // Booked with the first entry of its transaction
+		}
+		conversions[activity.TransactionHash] = nil
+		if err := l.convert(entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Assets returns a copy of every outcome's state, ordered by condition and outcome
+func (l *Ledger) Assets() []AssetPnL {
+	assets := make([]AssetPnL, 0, len(l.assets))
+	for _, a := range l.assets {
+		c := *a
+		c.lots = nil
+		assets = append(assets, c)
+	}
+	slices.SortFunc(assets, func(a, b AssetPnL) int {
+		return cmp.Or(cmp.Compare(a.ConditionID, b.ConditionID), cmp.Compare(a.OutcomeIndex, b.OutcomeIndex))
+	})
+	return assets
+}
+
+// Asset returns the state of one outcome, or nil when it never appeared
+func (l *Ledger) Asset(key OutcomeKey) *AssetPnL {
+	a, ok := l.assets[key]
+	if !ok {
+		return nil
+	}
+	c := *a
+	c.lots = nil
+	return &c
+}
+
+// Totals returns the realized PnL, the cost basis of open holdings and the
+// fees over every outcome
+func (l *Ledger) Totals() (realized, costBasis, fees float64) {
+	for _, a := range l.assets {
+		realized += a.RealizedPnl
+		costBasis += a.CostBasis
+		fees += a.Fees
+	}
+	return realized, costBasis, fees
+}
+
+// Discrepancy is a figure where the ledger and the Data API disagree
+type Discrepancy struct {
+	OutcomeKey
+	Title    string
+	Field    string // "size", "avgPrice" or "realizedPnl"
+	Computed float64
+	Reported float64
+}
+
+// Reconcile compares the ledger with the positions the Data API reports and
+// returns every size, average price and realized PnL that differs by more
+// than tolerance. The API uses average cost, so a FIFO ledger will disagree
+// on average price and realized PnL whenever lots were bought at different
+// prices. Outcomes the API lists in neither slice must hold no tokens.
+func (l *Ledger) Reconcile(current []Position, closed []ClosedPosition, tolerance float64) []Discrepancy {
+	var found []Discrepancy
+	check := func(key OutcomeKey, title, field string, computed, reported float64) {
+		if math.Abs(computed-reported) > tolerance {
+			found = append(found, Discrepancy{OutcomeKey: key, Title: title, Field: field, Computed: computed, Reported: reported})
+		}
+	}
+
+	listed := make(map[OutcomeKey]bool)
+	empty := &AssetPnL{}
+
+	for _, p := range current {
+		key := OutcomeKey{ConditionID: p.ConditionID, OutcomeIndex: p.OutcomeIndex}
+		listed[key] = true
+		a := l.assets[key]
+		if a == nil {
+			a = empty
+		}
+		check(key, p.Title, "size", a.Size, p.Size)
+		check(key, p.Title, "avgPrice", a.AvgPrice(), p.AvgPrice)
+		check(key, p.Title, "realizedPnl", a.RealizedPnl, p.RealizedPnl)
+	}
+
+	for _, p := range closed {
+		key := OutcomeKey{ConditionID: p.ConditionID, OutcomeIndex: p.OutcomeIndex}
+		if listed[key] {
+			continue
+		}
+		listed[key] = true
+		a := l.assets[key]
+		if a == nil {
+			a = empty
+		}
+		check(key, p.Title, "size", a.Size, 0)
+		check(key, p.Title, "realizedPnl", a.RealizedPnl, p.RealizedPnl)
+	}
+
+	for key, a := range l.assets {
+		if !listed[key] {
+			check(key, a.Title, "size", a.Size, 0)
+		}
+	}
+
+	slices.SortFunc(found, func(a, b Discrepancy) int {
+		return cmp.Or(
+			cmp.Compare(a.ConditionID, b.ConditionID),
+			cmp.Compare(a.OutcomeIndex, b.OutcomeIndex),
+			cmp.Compare(a.Field, b.Field),
+		)
+	})
+	return found
+}
+
+// PnLReport is a user's replayed PnL, marked to the Data API's current prices
+type PnLReport struct {
+	Method        CostMethod
+	Assets        []AssetPnL
+	RealizedPnl   float64
+	UnrealizedPnl float64 // Open holdings marked at each position's CurPrice
+	Fees          float64
+	Funded        float64
+	Rewards       float64
+	Discrepancies []Discrepancy
+}
+
+// ComputePnL replays a user's complete activity with method, marks open
+// holdings at the current position prices and reconciles the result against
+// the user's current and closed positions within tolerance USDC or tokens.
+// markets resolves the events of conversions; it may be nil for users who
+// never converted.
+func (d *DataSDK) ComputePnL(ctx context.Context, user string, method CostMethod, tolerance float64, markets NegRiskResolver) (*PnLReport, error) {
+	asc := SortAsc
+	activities, err := collect(d.IterActivity(ctx, &UserActivityQuery{User: &user, SortDirection: &asc}, &IterOptions{PageSize: 500}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user activity: %w", err)
+	}
+	current, err := collect(d.IterPositions(ctx, &PositionsQuery{User: &user}, nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current positions: %w", err)
+	}
+	closed, err := collect(d.IterClosedPositions(ctx, &ClosedPositionsQuery{User: &user}, nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed positions: %w", err)
+	}
+
+	ledger := NewLedger(method)
+	ledger.Markets = markets
+	if err := ledger.Replay(activities); err != nil {
+		return nil, err
+	}
+
+	report := &PnLReport{
+		Method:        method,
+		Assets:        ledger.Assets(),
+		Funded:        ledger.Funded,
+		Rewards:       ledger.Rewards,
+		Discrepancies: ledger.Reconcile(current, closed, tolerance),
+	}
+	report.RealizedPnl, _, report.Fees = ledger.Totals()
+
+	prices := make(map[OutcomeKey]float64, len(current))
+	for _, p := range current {
+		prices[OutcomeKey{ConditionID: p.ConditionID, OutcomeIndex: p.OutcomeIndex}] = p.CurPrice
+	}
+	for _, a := range report.Assets {
+		if a.Size > 0 {
+			report.UnrealizedPnl += a.UnrealizedPnl(prices[OutcomeKey{ConditionID: a.ConditionID, OutcomeIndex: a.OutcomeIndex}])
+		}
+	}
+
+	return report, nil
+}
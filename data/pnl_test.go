@@ -0,0 +1,246 @@
+package data
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+func trade(ts int64, side types.Side, size, price float64) Activity {
+	return Activity{Timestamp: ts, Type: ActivityTrade, Side: side, ConditionID: "0xc", OutcomeIndex: 0, Size: size, UsdcSize: size * price}
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestLedgerCostMethods(t *testing.T) {
+	history := []Activity{
+		trade(1, types.SideBuy, 100, 0.40),
+		trade(2, types.SideBuy, 100, 0.60),
+		trade(3, types.SideSell, 100, 0.70),
+	}
+
+	tests := []struct {
+		method       CostMethod
+		wantRealized float64
+		wantBasis    float64
+	}{
+		{method: FIFO, wantRealized: 30, wantBasis: 60},
+		{method: AverageCost, wantRealized: 20, wantBasis: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method.String(), func(t *testing.T) {
+			ledger := NewLedger(tt.method)
+			if err := ledger.Replay(history); err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+			a := ledger.Asset(OutcomeKey{ConditionID: "0xc"})
+			if !near(a.Size, 100) || !near(a.RealizedPnl, tt.wantRealized) || !near(a.CostBasis, tt.wantBasis) {
+				t.Errorf("got size %v realized %v basis %v, want 100 %v %v", a.Size, a.RealizedPnl, a.CostBasis, tt.wantRealized, tt.wantBasis)
+			}
+			if got := a.UnrealizedPnl(0.8); !near(got, 80-tt.wantBasis) {
+				t.Errorf("UnrealizedPnl(0.8) = %v, want %v", got, 80-tt.wantBasis)
+			}
+		})
+	}
+}
+
+func TestLedgerSplitMergeRedeem(t *testing.T) {
+	fee := 0.5
+	sell := trade(2, types.SideSell, 40, 0.30)
+	sell.OutcomeIndex = 1
+	sell.Fee = &fee
+
+	history := []Activity{
+		{Timestamp: 5, Type: ActivityRedeem, ConditionID: "0xc", UsdcSize: 90},
+		{Timestamp: 3, Type: ActivityMerge, ConditionID: "0xc", Size: 10, UsdcSize: 10},
+		sell,
+		{Timestamp: 2, Type: ActivitySplit, ConditionID: "0xc", Size: 100, UsdcSize: 100},
+		{Timestamp: 0, Type: ActivityFund, UsdcSize: 100},
+	}
+
+	ledger := NewLedger(FIFO)
+	if err := ledger.Replay(history); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	yes, no := ledger.Asset(OutcomeKey{ConditionID: "0xc", OutcomeIndex: 0}), ledger.Asset(OutcomeKey{ConditionID: "0xc", OutcomeIndex: 1})
+	// Split at 0.5 each; sold 40 NO for 12 - 0.5 fee; merged 10 pairs for 10;
+	// YES won, so redeeming paid 90 for the 90 YES left and nothing for 50 NO.
+	if a := yes; a.Size != 0 || !near(a.RealizedPnl, (5-5)+(90-45)) {
+		t.Errorf("yes: size %v realized %v", a.Size, a.RealizedPnl)
+	}
+	if a := no; a.Size != 0 || !near(a.RealizedPnl, (11.5-20)+(5-5)+(0-25)) || !near(a.Fees, 0.5) {
+		t.Errorf("no: size %v realized %v fees %v", a.Size, a.RealizedPnl, a.Fees)
+	}
+	if ledger.Funded != 100 {
+		t.Errorf("Funded = %v, want 100", ledger.Funded)
+	}
+
+	realized, basis, fees := ledger.Totals()
+	if !near(realized, 45+11.5-20-25) || basis != 0 || fees != 0.5 {
+		t.Errorf("Totals() = %v, %v, %v", realized, basis, fees)
+	}
+}
+
+func TestLedgerRedeemCreditsWinner(t *testing.T) {
+	ledger := NewLedger(AverageCost)
+	history := []Activity{
+		{Timestamp: 1, Type: ActivitySplit, ConditionID: "0xc", Size: 100, UsdcSize: 100},
+		trade(2, types.SideSell, 100, 0.2),
+		{Timestamp: 3, Type: ActivityRedeem, ConditionID: "0xc", UsdcSize: 100},
+	}
+	history[1].OutcomeIndex = 0
+	if err := ledger.Replay(history); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	no := ledger.Asset(OutcomeKey{ConditionID: "0xc", OutcomeIndex: 1})
+	if !near(no.RealizedPnl, 50) || no.Size != 0 {
+		t.Errorf("no: realized %v size %v, want 50 0", no.RealizedPnl, no.Size)
+	}
+}
+
+func TestLedgerUnmatchedAndErrors(t *testing.T) {
+	ledger := NewLedger(FIFO)
+	if err := ledger.Apply(trade(1, types.SideSell, 10, 0.5)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	a := ledger.Asset(OutcomeKey{ConditionID: "0xc"})
+	if a.Unmatched != 10 || a.RealizedPnl != 5 {
+		t.Errorf("unmatched %v realized %v, want 10 5", a.Unmatched, a.RealizedPnl)
+	}
+
+	if err := ledger.Apply(Activity{Type: "CANCEL"}); err == nil {
+		t.Error("Apply(CANCEL) error = nil")
+	}
+	if err := ledger.Apply(Activity{Type: ActivityTrade}); err == nil {
+		t.Error("Apply(trade without side) error = nil")
+	}
+}
+
+func TestLedgerReconcile(t *testing.T) {
+	ledger := NewLedger(AverageCost)
+	ledger.Replay([]Activity{
+		trade(1, types.SideBuy, 100, 0.40),
+		{Timestamp: 1, Type: ActivityTrade, Side: types.SideBuy, ConditionID: "0xd", Size: 10, UsdcSize: 5},
+		{Timestamp: 1, Type: ActivityTrade, Side: types.SideBuy, ConditionID: "0xe", Size: 10, UsdcSize: 5},
+		{Timestamp: 2, Type: ActivityTrade, Side: types.SideSell, ConditionID: "0xe", Size: 10, UsdcSize: 8},
+	})
+
+	got := ledger.Reconcile(
+		[]Position{{ConditionID: "0xc", Size: 100, AvgPrice: 0.45}},
+		[]ClosedPosition{{ConditionID: "0xe", RealizedPnl: 3}},
+		0.001,
+	)
+	want := []Discrepancy{
+		{OutcomeKey: OutcomeKey{ConditionID: "0xc"}, Field: "avgPrice", Computed: 0.4, Reported: 0.45},
+		{OutcomeKey: OutcomeKey{ConditionID: "0xd"}, Field: "size", Computed: 10, Reported: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Reconcile() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].OutcomeKey != want[i].OutcomeKey || got[i].Field != want[i].Field || !near(got[i].Computed, want[i].Computed) || got[i].Reported != want[i].Reported {
+			t.Errorf("Reconcile()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLedgerConversion(t *testing.T) {
+	// 0xa, 0xb and 0xc are the markets of one neg risk event
+	resolves := 0
+	markets := func(conditionID string) ([]string, error) {
+		resolves++
+		return []string{"0xa", "0xb", "0xc"}, nil
+	}
+
+	ledger := NewLedger(AverageCost)
+	ledger.Markets = markets
+	err := ledger.Replay([]Activity{
+		{Timestamp: 1, Type: ActivitySplit, ConditionID: "0xa", Size: 10, UsdcSize: 10},
+		{Timestamp: 2, Type: ActivityConversion, ConditionID: "0xa", OutcomeIndex: 1, Size: 10, UsdcSize: 4, TransactionHash: "0x1"},
+		{Timestamp: 3, Type: ActivityRedeem, ConditionID: "0xb", UsdcSize: 10},
+		{Timestamp: 3, Type: ActivityRedeem, ConditionID: "0xc", UsdcSize: 0},
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	got := ledger.Reconcile(
+		[]Position{{ConditionID: "0xa", Size: 10, AvgPrice: 0.5}},
+		[]ClosedPosition{
+			{ConditionID: "0xa", OutcomeIndex: 1, RealizedPnl: -1},
+			{ConditionID: "0xb", RealizedPnl: 8},
+			{ConditionID: "0xc", RealizedPnl: -2},
+		},
+		0.001,
+	)
+	if len(got) != 0 {
+		t.Errorf("Reconcile() = %+v, want no discrepancies", got)
+	}
+	if a := ledger.Asset(OutcomeKey{ConditionID: "0xa", OutcomeIndex: 1}); !near(a.Converted, 10) {
+		t.Errorf("Converted = %v, want 10", a.Converted)
+	}
+
+	// Entries of one transaction convert several markets at once
+	ledger = NewLedger(FIFO)
+	ledger.Markets = markets
+	err = ledger.Replay([]Activity{
+		{Timestamp: 1, Type: ActivitySplit, ConditionID: "0xa", Size: 10, UsdcSize: 10},
+		{Timestamp: 1, Type: ActivitySplit, ConditionID: "0xb", Size: 10, UsdcSize: 10},
+		{Timestamp: 2, Type: ActivityConversion, ConditionID: "0xa", OutcomeIndex: 1, Size: 10, UsdcSize: 6, TransactionHash: "0x2"},
+		{Timestamp: 2, Type: ActivityConversion, ConditionID: "0xb", OutcomeIndex: 1, Size: 10, UsdcSize: 6, TransactionHash: "0x2"},
+	})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if a := ledger.Asset(OutcomeKey{ConditionID: "0xc"}); a == nil || !near(a.Size, 10) || !near(a.CostBasis, 12) {
+		t.Errorf("minted Yes = %+v, want size 10 costing 12", a)
+	}
+	if a := ledger.Asset(OutcomeKey{ConditionID: "0xa"}); !near(a.Size, 10) {
+		t.Errorf("Yes of a converted market = %v, want the split 10 only", a.Size)
+	}
+	if resolves != 2 {
+		t.Errorf("resolved %d events, want one per ledger", resolves)
+	}
+
+	if err := NewLedger(FIFO).Apply(Activity{Type: ActivityConversion, ConditionID: "0xa", Size: 1}); err == nil {
+		t.Error("Apply(conversion) without Markets error = nil")
+	}
+}
+
+func TestComputePnL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/activity":
+			if r.URL.Query().Get("sortDirection") != "ASC" {
+				t.Errorf("activity sortDirection = %q, want ASC", r.URL.Query().Get("sortDirection"))
+			}
+			json.NewEncoder(w).Encode(paged(r, []Activity{
+				trade(1, types.SideBuy, 100, 0.40),
+				trade(2, types.SideSell, 50, 0.60),
+			}))
+		case "/positions":
+			json.NewEncoder(w).Encode(paged(r, []Position{{ConditionID: "0xc", Size: 50, AvgPrice: 0.40, RealizedPnl: 10, CurPrice: 0.7}}))
+		case "/closed-positions":
+			json.NewEncoder(w).Encode([]ClosedPosition{})
+		}
+	}))
+	defer srv.Close()
+
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	report, err := sdk.ComputePnL(t.Context(), "0xabc", AverageCost, 0.001, nil)
+	if err != nil {
+		t.Fatalf("ComputePnL() error = %v", err)
+	}
+	if !near(report.RealizedPnl, 10) || !near(report.UnrealizedPnl, 15) || len(report.Discrepancies) != 0 {
+		t.Errorf("ComputePnL() = realized %v unrealized %v discrepancies %+v", report.RealizedPnl, report.UnrealizedPnl, report.Discrepancies)
+	}
+}
@@ -0,0 +1,87 @@
+package data
+
+import (
+	"cmp"
+	"slices"
+)
+
+// DefaultTopN is the number of winners and losers a portfolio summary keeps
+const DefaultTopN = 5
+
+// summarize fills the derived fields from CurrentPositions
+func (p *Portfolio) summarize(options *PortfolioOptions) {
+	topN := DefaultTopN
+	if options.TopN > 0 {
+		topN = options.TopN
+	}
+
+	p.ExposureByEvent = exposureBy(p.CurrentPositions, func(pos Position) string {
+		return cmp.Or(pos.EventSlug, pos.EventID, pos.Slug)
+	})
+	if options.Category != nil {
+		p.ExposureByCategory = exposureBy(p.CurrentPositions, options.Category)
+	}
+
+	p.Redeemable, p.Mergeable = PositionTotals{}, PositionTotals{}
+	for _, pos := range p.CurrentPositions {
+		if pos.Redeemable {
+			p.Redeemable.add(pos)
+		}
+		if pos.Mergeable {
+			p.Mergeable.add(pos)
+		}
+	}
+
+	byPnl := slices.Clone(p.CurrentPositions)
+	slices.SortStableFunc(byPnl, func(a, b Position) int {
+		return cmp.Compare(b.CashPnl, a.CashPnl)
+	})
+	p.TopWinners, p.TopLosers = nil, nil
+	for _, pos := range byPnl {
+		if len(p.TopWinners) == topN || pos.CashPnl <= 0 {
+			break
+		}
+		p.TopWinners = append(p.TopWinners, pos)
+	}
+	for _, pos := range slices.Backward(byPnl) {
+		if len(p.TopLosers) == topN || pos.CashPnl >= 0 {
+			break
+		}
+		p.TopLosers = append(p.TopLosers, pos)
+	}
+}
+
+// add counts pos into the totals
+func (t *PositionTotals) add(pos Position) {
+	t.Positions++
+	t.Size += pos.Size
+	t.CurrentValue += pos.CurrentValue
+}
+
+// exposureBy groups positions by key, largest current value first. Positions
+// with an empty key are left out.
+func exposureBy(positions []Position, key func(Position) string) []Exposure {
+	index := make(map[string]int)
+	var groups []Exposure
+	for _, pos := range positions {
+		k := key(pos)
+		if k == "" {
+			continue
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Exposure{Key: k, Title: pos.Title})
+		}
+		groups[i].Positions++
+		groups[i].InitialValue += pos.InitialValue
+		groups[i].CurrentValue += pos.CurrentValue
+		groups[i].CashPnl += pos.CashPnl
+	}
+
+	slices.SortStableFunc(groups, func(a, b Exposure) int {
+		return cmp.Compare(b.CurrentValue, a.CurrentValue)
+	})
+	return groups
+}
@@ -0,0 +1,99 @@
+package data
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPortfolioSummarize(t *testing.T) {
+	portfolio := Portfolio{CurrentPositions: []Position{
+		{Title: "A1", EventSlug: "a", CurrentValue: 10, InitialValue: 8, CashPnl: 2, Redeemable: true, Size: 10},
+		{Title: "A2", EventSlug: "a", CurrentValue: 5, InitialValue: 9, CashPnl: -4},
+		{Title: "B1", EventSlug: "b", CurrentValue: 30, InitialValue: 20, CashPnl: 10, Mergeable: true, Size: 40},
+		{Title: "C1", EventSlug: "c", CurrentValue: 1, InitialValue: 3, CashPnl: -2},
+		{Title: "D1", EventSlug: "d", CurrentValue: 0, InitialValue: 0, CashPnl: 0},
+	}}
+
+	portfolio.summarize(&PortfolioOptions{
+		TopN: 1,
+		Category: func(p Position) string {
+			if p.EventSlug == "b" {
+				return "Sports"
+			}
+			return "Politics"
+		},
+	})
+
+	wantEvents := []Exposure{
+		{Key: "b", Title: "B1", Positions: 1, InitialValue: 20, CurrentValue: 30, CashPnl: 10},
+		{Key: "a", Title: "A1", Positions: 2, InitialValue: 17, CurrentValue: 15, CashPnl: -2},
+		{Key: "c", Title: "C1", Positions: 1, InitialValue: 3, CurrentValue: 1, CashPnl: -2},
+		{Key: "d", Title: "D1", Positions: 1},
+	}
+	if !reflect.DeepEqual(portfolio.ExposureByEvent, wantEvents) {
+		t.Errorf("ExposureByEvent = %+v, want %+v", portfolio.ExposureByEvent, wantEvents)
+	}
+	if len(portfolio.ExposureByCategory) != 2 || portfolio.ExposureByCategory[0].Key != "Sports" || portfolio.ExposureByCategory[1].Positions != 4 {
+		t.Errorf("ExposureByCategory = %+v", portfolio.ExposureByCategory)
+	}
+	if portfolio.Redeemable != (PositionTotals{Positions: 1, Size: 10, CurrentValue: 10}) || portfolio.Mergeable != (PositionTotals{Positions: 1, Size: 40, CurrentValue: 30}) {
+		t.Errorf("Redeemable = %+v, Mergeable = %+v", portfolio.Redeemable, portfolio.Mergeable)
+	}
+	if len(portfolio.TopWinners) != 1 || portfolio.TopWinners[0].Title != "B1" || len(portfolio.TopLosers) != 1 || portfolio.TopLosers[0].Title != "A2" {
+		t.Errorf("TopWinners = %+v, TopLosers = %+v", portfolio.TopWinners, portfolio.TopLosers)
+	}
+}
+
+func TestGetPortfolioSummaryReturnsPartialResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/value":
+			json.NewEncoder(w).Encode([]TotalValue{{User: "0xabc", Value: 42}})
+		case "/traded":
+			http.Error(w, `{"error": "boom"}`, http.StatusInternalServerError)
+		case "/positions":
+			json.NewEncoder(w).Encode(paged(r, []Position{{Title: "A", EventSlug: "a", CashPnl: 1, CurrentValue: 3}}))
+		}
+	}))
+	defer srv.Close()
+
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	portfolio, err := sdk.GetPortfolioSummary("0xabc", nil)
+	if err == nil || !strings.Contains(err.Error(), "markets traded") {
+		t.Fatalf("GetPortfolioSummary() error = %v, want a markets traded failure", err)
+	}
+	if portfolio == nil || len(portfolio.TotalValue) != 1 || len(portfolio.CurrentPositions) != 1 || len(portfolio.TopWinners) != 1 {
+		t.Fatalf("GetPortfolioSummary() = %+v, want the successful parts", portfolio)
+	}
+	if portfolio.MarketsTraded != nil {
+		t.Errorf("MarketsTraded = %+v, want nil", portfolio.MarketsTraded)
+	}
+}
+
+func TestGetAllPositionsNilOptionsAndPartialFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/closed-positions" {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(paged(r, []Position{{Title: "A"}}))
+	}))
+	defer srv.Close()
+
+	sdk := NewDataSDK(nil)
+	sdk.baseURL = srv.URL
+
+	all, err := sdk.GetAllPositions("0xabc", nil)
+	if err == nil || !strings.Contains(err.Error(), "closed positions") {
+		t.Fatalf("GetAllPositions() error = %v, want a closed positions failure", err)
+	}
+	if len(all.Current) != 1 {
+		t.Errorf("got %d current positions, want 1", len(all.Current))
+	}
+}
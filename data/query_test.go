@@ -0,0 +1,149 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestBuildURLQueries(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+	flag := func(b bool) *bool { return &b }
+	float := func(f float64) *float64 { return &f }
+	list := func(s ...string) *[]string { return &s }
+
+	tests := []struct {
+		name     string
+		endpoint string
+		query    interface{}
+		want     string
+	}{
+		{
+			name:     "nil query",
+			endpoint: "/positions",
+			query:    (*PositionsQuery)(nil),
+			want:     "/positions",
+		},
+		{
+			name:     "PositionsQuery",
+			endpoint: "/positions",
+			query: &PositionsQuery{
+				User:          str("0xabc"),
+				Market:        list("0x1", "0x2"),
+				EventID:       list("10", "11"),
+				SizeThreshold: float(1.5),
+				Redeemable:    flag(true),
+				Mergeable:     flag(false),
+				Limit:         num(50),
+				Offset:        num(100),
+				SortBy:        ptr(PositionSortByCashPnl),
+				SortDirection: ptr(SortDesc),
+				Title:         str("Election"),
+			},
+			want: "/positions?eventId=10%2C11&limit=50&market=0x1%2C0x2&mergeable=false&offset=100&redeemable=true&sizeThreshold=1.5&sortBy=CASHPNL&sortDirection=DESC&title=Election&user=0xabc",
+		},
+		{
+			name:     "ClosedPositionsQuery",
+			endpoint: "/closed-positions",
+			query: &ClosedPositionsQuery{
+				User:          str("0xabc"),
+				Market:        list("0x1"),
+				EventID:       list("10"),
+				Title:         str("Cup"),
+				Limit:         num(10),
+				Offset:        num(0),
+				SortBy:        ptr(ClosedPositionSortByRealizedPnl),
+				SortDirection: ptr(SortAsc),
+			},
+			want: "/closed-positions?eventId=10&limit=10&market=0x1&offset=0&sortBy=REALIZEDPNL&sortDirection=ASC&title=Cup&user=0xabc",
+		},
+		{
+			name:     "TradesQuery",
+			endpoint: "/trades",
+			query: &TradesQuery{
+				Limit:        num(5),
+				Offset:       num(5),
+				TakerOnly:    flag(true),
+				FilterType:   str("CASH"),
+				FilterAmount: float(10),
+				Market:       list("0x1", "0x2", "0x3"),
+				EventID:      list("7"),
+				User:         str("0xabc"),
+				Side:         ptr(types.SideBuy),
+			},
+			want: "/trades?eventId=7&filterAmount=10&filterType=CASH&limit=5&market=0x1%2C0x2%2C0x3&offset=5&side=BUY&takerOnly=true&user=0xabc",
+		},
+		{
+			name:     "UserActivityQuery",
+			endpoint: "/activity",
+			query: &UserActivityQuery{
+				User:          str("0xabc"),
+				Limit:         num(100),
+				Offset:        num(200),
+				Market:        list("0x1", "0x2"),
+				EventID:       list("1", "2"),
+				Type:          []ActivityType{ActivityTrade, ActivityRedeem},
+				Start:         ptr(time.Unix(1700000000, 0)),
+				End:           ptr(time.Unix(1700086400, 0)),
+				SortBy:        ptr(ActivitySortByTimestamp),
+				SortDirection: ptr(SortDesc),
+				Side:          ptr(types.SideSell),
+			},
+			want: "/activity?end=1700086400&eventId=1%2C2&limit=100&market=0x1%2C0x2&offset=200&side=SELL&sortBy=TIMESTAMP&sortDirection=DESC&start=1700000000&type=TRADE%2CREDEEM&user=0xabc",
+		},
+		{
+			name:     "TopHoldersQuery",
+			endpoint: "/holders",
+			query: &TopHoldersQuery{
+				Limit:      num(20),
+				Market:     []string{"0x1", "0x2"},
+				MinBalance: num(1),
+			},
+			want: "/holders?limit=20&market=0x1%2C0x2&minBalance=1",
+		},
+		{
+			name:     "TotalValueQuery",
+			endpoint: "/value",
+			query: &TotalValueQuery{
+				User:   str("0xabc"),
+				Market: list("0x1", "0x2"),
+			},
+			want: "/value?market=0x1%2C0x2&user=0xabc",
+		},
+		{
+			name:     "TotalMarketsTradedQuery",
+			endpoint: "/traded",
+			query:    &TotalMarketsTradedQuery{User: str("0xabc")},
+			want:     "/traded?user=0xabc",
+		},
+		{
+			name:     "OpenInterestQuery",
+			endpoint: "/oi",
+			query:    &OpenInterestQuery{Market: []string{"0x1", "0x2"}},
+			want:     "/oi?market=0x1%2C0x2",
+		},
+		{
+			name:     "LiveVolumeQuery",
+			endpoint: "/live-volume",
+			query:    &LiveVolumeQuery{ID: 42},
+			want:     "/live-volume?id=42",
+		},
+	}
+
+	sdk := NewDataSDK(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sdk.buildURL(tt.endpoint, tt.query)
+			if err != nil {
+				t.Fatalf("buildURL() error = %v", err)
+			}
+			if want := DataAPIBase + tt.want; got != want {
+				t.Errorf("buildURL() = %q, want %q", got, want)
+			}
+		})
+	}
+}
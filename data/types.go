@@ -1,17 +1,105 @@
 package data
 
-// ProxyConfig represents HTTP/HTTPS proxy configuration
-type ProxyConfig struct {
-	Host     string  `json:"host"`
-	Port     int     `json:"port"`
-	Username *string `json:"username,omitempty"`
-	Password *string `json:"password,omitempty"`
-	Protocol *string `json:"protocol,omitempty"` // "http" or "https"
-}
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/observe"
+	"github.com/ybina/polymarket-sdk-go/proxy"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// ActivityType represents the kind of a user activity entry
+type ActivityType string
+
+const (
+	ActivityTrade      ActivityType = "TRADE"
+	ActivitySplit      ActivityType = "SPLIT"
+	ActivityMerge      ActivityType = "MERGE"
+	ActivityRedeem     ActivityType = "REDEEM"
+	ActivityReward     ActivityType = "REWARD"
+	ActivityConversion ActivityType = "CONVERSION"
+	ActivityFund       ActivityType = "FUND"
+)
+
+// ActivitySortBy represents the sort keys of the activity endpoint
+type ActivitySortBy string
+
+const (
+	ActivitySortByTimestamp ActivitySortBy = "TIMESTAMP"
+	ActivitySortByTokens    ActivitySortBy = "TOKENS"
+	ActivitySortByCash      ActivitySortBy = "CASH"
+)
+
+// PositionSortBy represents the sort keys of the positions endpoint
+type PositionSortBy string
+
+const (
+	PositionSortByCurrent    PositionSortBy = "CURRENT"
+	PositionSortByInitial    PositionSortBy = "INITIAL"
+	PositionSortByTokens     PositionSortBy = "TOKENS"
+	PositionSortByCashPnl    PositionSortBy = "CASHPNL"
+	PositionSortByPercentPnl PositionSortBy = "PERCENTPNL"
+	PositionSortByTitle      PositionSortBy = "TITLE"
+	PositionSortByResolving  PositionSortBy = "RESOLVING"
+	PositionSortByPrice      PositionSortBy = "PRICE"
+	PositionSortByAvgPrice   PositionSortBy = "AVGPRICE"
+)
+
+// ClosedPositionSortBy represents the sort keys of the closed positions endpoint
+type ClosedPositionSortBy string
+
+const (
+	ClosedPositionSortByRealizedPnl ClosedPositionSortBy = "REALIZEDPNL"
+	ClosedPositionSortByTitle       ClosedPositionSortBy = "TITLE"
+	ClosedPositionSortByPrice       ClosedPositionSortBy = "PRICE"
+	ClosedPositionSortByAvgPrice    ClosedPositionSortBy = "AVGPRICE"
+	ClosedPositionSortByTimestamp   ClosedPositionSortBy = "TIMESTAMP"
+)
+
+// SortDirection represents a sort order
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "ASC"
+	SortDesc SortDirection = "DESC"
+)
+
+// ProxyConfig represents HTTP, HTTPS or SOCKS5 proxy configuration
+type ProxyConfig = proxy.Config
 
 // DataSDKConfig represents configuration for the Data SDK
 type DataSDKConfig struct {
-	Proxy *ProxyConfig `json:"proxy,omitempty"` // HTTP/HTTPS proxy configuration
+	Proxy *ProxyConfig `json:"proxy,omitempty"` // HTTP, HTTPS or SOCKS5 proxy configuration
+
+	// ProxyPool rotates requests over several proxies; takes precedence over Proxy
+	ProxyPool *proxy.Pool `json:"-"`
+
+	// BaseURL overrides DataAPIBase, e.g. for staging or a local fake
+	BaseURL string `json:"baseUrl,omitempty"`
+
+	// HTTPClient sends every request when set; the proxy settings and Transport
+	// are then ignored
+	HTTPClient *http.Client `json:"-"`
+
+	// Transport replaces the default transport, e.g. for mTLS, connection
+	// pooling or recording; the proxy settings are then ignored
+	Transport http.RoundTripper `json:"-"`
+
+	// UserAgent sent with every request (default "data-go-sdk/1.0"); it
+	// takes precedence over a User-Agent in Headers
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// Headers added to every request
+	Headers http.Header `json:"headers,omitempty"`
+
+	// Logger receives request and response logs at debug level
+	// (default: discard)
+	Logger *slog.Logger `json:"-"`
+
+	// Observer receives request, rate limit and latency events
+	Observer observe.Observer `json:"-"`
 }
 
 // Position represents a user's position from the Data API
@@ -68,59 +156,60 @@ type ClosedPosition struct {
 
 // DataTrade represents a trade from the Data API
 type DataTrade struct {
-	ProxyWallet     string  `json:"proxyWallet"`
-	Side            string  `json:"side"` // "BUY" or "SELL"
-	ConditionID     string  `json:"conditionId"`
-	Outcome         string  `json:"outcome"`
-	Market          string  `json:"market"`
-	Size            float64 `json:"size"`
-	Price           float64 `json:"price"`
-	Fee             *float64 `json:"fee,omitempty"`
-	Timestamp       int64   `json:"timestamp"`
-	TransactionHash string  `json:"transactionHash"`
-	Maker           string  `json:"maker"`
-	Taker           string  `json:"taker"`
-	AssetID         string  `json:"assetId"`
+	ProxyWallet     string     `json:"proxyWallet"`
+	Side            types.Side `json:"side"`
+	ConditionID     string     `json:"conditionId"`
+	Outcome         string     `json:"outcome"`
+	Market          string     `json:"market"`
+	Size            float64    `json:"size"`
+	Price           float64    `json:"price"`
+	Fee             *float64   `json:"fee,omitempty"`
+	Timestamp       int64      `json:"timestamp"`
+	TransactionHash string     `json:"transactionHash"`
+	Maker           string     `json:"maker"`
+	Taker           string     `json:"taker"`
+	AssetID         string     `json:"assetId"`
 	// Additional fields from actual API response
-	Title             string `json:"title"`
-	Slug              string `json:"slug"`
-	Icon              string `json:"icon"`
-	EventSlug         string `json:"eventSlug"`
-	OutcomeIndex      int    `json:"outcomeIndex"`
-	Name              string `json:"name"`
-	Pseudonym         string `json:"pseudonym"`
-	Bio               string `json:"bio"`
-	ProfileImage      string `json:"profileImage"`
+	Title                 string `json:"title"`
+	Slug                  string `json:"slug"`
+	Icon                  string `json:"icon"`
+	EventSlug             string `json:"eventSlug"`
+	OutcomeIndex          int    `json:"outcomeIndex"`
+	Name                  string `json:"name"`
+	Pseudonym             string `json:"pseudonym"`
+	Bio                   string `json:"bio"`
+	ProfileImage          string `json:"profileImage"`
 	ProfileImageOptimized string `json:"profileImageOptimized"`
 }
 
 // Activity represents user activity from the Data API
 type Activity struct {
-	ProxyWallet     string  `json:"proxyWallet"`
-	Timestamp       int64   `json:"timestamp"`
-	Type            string  `json:"type"` // "TRADE", "CANCEL", "FUND", "REDEEM"
-	Size            float64 `json:"size"`
-	UsdcSize        float64 `json:"usdcSize"`
-	Price           *float64 `json:"price,omitempty"`
-	Fee             *float64 `json:"fee,omitempty"`
-	ConditionID     string  `json:"conditionId"`
-	Outcome         string  `json:"outcome"`
-	Market          string  `json:"market"`
-	TransactionHash string  `json:"transactionHash"`
-	From            string  `json:"from"`
-	To              string  `json:"to"`
-	AssetID         string  `json:"assetId"`
-	Value           *float64 `json:"value,omitempty"`
+	ProxyWallet     string       `json:"proxyWallet"`
+	Timestamp       int64        `json:"timestamp"`
+	Type            ActivityType `json:"type"`
+	Side            types.Side   `json:"side,omitempty"` // Set for trades
+	Size            float64      `json:"size"`
+	UsdcSize        float64      `json:"usdcSize"`
+	Price           *float64     `json:"price,omitempty"`
+	Fee             *float64     `json:"fee,omitempty"`
+	ConditionID     string       `json:"conditionId"`
+	Outcome         string       `json:"outcome"`
+	Market          string       `json:"market"`
+	TransactionHash string       `json:"transactionHash"`
+	From            string       `json:"from"`
+	To              string       `json:"to"`
+	AssetID         string       `json:"assetId"`
+	Value           *float64     `json:"value,omitempty"`
 	// Additional fields from actual API response
-	Title             string `json:"title"`
-	Slug              string `json:"slug"`
-	Icon              string `json:"icon"`
-	EventSlug         string `json:"eventSlug"`
-	OutcomeIndex      int    `json:"outcomeIndex"`
-	Name              string `json:"name"`
-	Pseudonym         string `json:"pseudonym"`
-	Bio               string `json:"bio"`
-	ProfileImage      string `json:"profileImage"`
+	Title                 string `json:"title"`
+	Slug                  string `json:"slug"`
+	Icon                  string `json:"icon"`
+	EventSlug             string `json:"eventSlug"`
+	OutcomeIndex          int    `json:"outcomeIndex"`
+	Name                  string `json:"name"`
+	Pseudonym             string `json:"pseudonym"`
+	Bio                   string `json:"bio"`
+	ProfileImage          string `json:"profileImage"`
 	ProfileImageOptimized string `json:"profileImageOptimized"`
 }
 
@@ -176,70 +265,70 @@ type DataHealthResponse struct {
 
 // PositionsQuery represents query parameters for positions
 type PositionsQuery struct {
-	User           *string  `json:"user,omitempty"`
-	Market         *[]string `json:"market,omitempty"`
-	EventID        *[]string `json:"eventId,omitempty"`
-	SizeThreshold  *float64  `json:"sizeThreshold,omitempty"`
-	Redeemable     *bool     `json:"redeemable,omitempty"`
-	Mergeable      *bool     `json:"mergeable,omitempty"`
-	Limit          *int     `json:"limit,omitempty"`
-	Offset         *int     `json:"offset,omitempty"`
-	SortBy         *string  `json:"sortBy,omitempty"`
-	SortDirection  *string  `json:"sortDirection,omitempty"` // "ASC" or "DESC"
-	Title          *string  `json:"title,omitempty"`
+	User          *string         `json:"user,omitempty"`
+	Market        *[]string       `json:"market,omitempty" url:"market,omitempty,comma"`
+	EventID       *[]string       `json:"eventId,omitempty" url:"eventId,omitempty,comma"`
+	SizeThreshold *float64        `json:"sizeThreshold,omitempty"`
+	Redeemable    *bool           `json:"redeemable,omitempty"`
+	Mergeable     *bool           `json:"mergeable,omitempty"`
+	Limit         *int            `json:"limit,omitempty"`
+	Offset        *int            `json:"offset,omitempty"`
+	SortBy        *PositionSortBy `json:"sortBy,omitempty"`
+	SortDirection *SortDirection  `json:"sortDirection,omitempty"`
+	Title         *string         `json:"title,omitempty"`
 }
 
 // ClosedPositionsQuery represents query parameters for closed positions
 type ClosedPositionsQuery struct {
-	User           *string  `json:"user,omitempty"`
-	Market         *[]string `json:"market,omitempty"`
-	EventID        *[]string `json:"eventId,omitempty"`
-	Title          *string  `json:"title,omitempty"`
-	Limit          *int     `json:"limit,omitempty"`
-	Offset         *int     `json:"offset,omitempty"`
-	SortBy         *string  `json:"sortBy,omitempty"`
-	SortDirection  *string  `json:"sortDirection,omitempty"` // "ASC" or "DESC"
+	User          *string               `json:"user,omitempty"`
+	Market        *[]string             `json:"market,omitempty" url:"market,omitempty,comma"`
+	EventID       *[]string             `json:"eventId,omitempty" url:"eventId,omitempty,comma"`
+	Title         *string               `json:"title,omitempty"`
+	Limit         *int                  `json:"limit,omitempty"`
+	Offset        *int                  `json:"offset,omitempty"`
+	SortBy        *ClosedPositionSortBy `json:"sortBy,omitempty"`
+	SortDirection *SortDirection        `json:"sortDirection,omitempty"`
 }
 
 // TradesQuery represents query parameters for trades
 type TradesQuery struct {
-	Limit        *int     `json:"limit,omitempty"`
-	Offset       *int     `json:"offset,omitempty"`
-	TakerOnly    *bool    `json:"takerOnly,omitempty"`
-	FilterType   *string  `json:"filterType,omitempty"`
-	FilterAmount *float64 `json:"filterAmount,omitempty"`
-	Market       *[]string `json:"market,omitempty"`
-	EventID      *[]string `json:"eventId,omitempty"`
-	User         *string  `json:"user,omitempty"`
-	Side         *string  `json:"side,omitempty"` // "BUY" or "SELL"
+	Limit        *int        `json:"limit,omitempty"`
+	Offset       *int        `json:"offset,omitempty"`
+	TakerOnly    *bool       `json:"takerOnly,omitempty"`
+	FilterType   *string     `json:"filterType,omitempty"`
+	FilterAmount *float64    `json:"filterAmount,omitempty"`
+	Market       *[]string   `json:"market,omitempty" url:"market,omitempty,comma"`
+	EventID      *[]string   `json:"eventId,omitempty" url:"eventId,omitempty,comma"`
+	User         *string     `json:"user,omitempty"`
+	Side         *types.Side `json:"side,omitempty"`
 }
 
 // UserActivityQuery represents query parameters for user activity
 type UserActivityQuery struct {
-	User           *string  `json:"user,omitempty"`
-	Limit          *int     `json:"limit,omitempty"`
-	Offset         *int     `json:"offset,omitempty"`
-	Market         *[]string `json:"market,omitempty"`
-	EventID        *[]string `json:"eventId,omitempty"`
-	Type           *string  `json:"type,omitempty"` // "BUY", "SELL", "CANCEL", "FUND", "REDEEM"
-	Start          *string  `json:"start,omitempty"`
-	End            *string  `json:"end,omitempty"`
-	SortBy         *string  `json:"sortBy,omitempty"`
-	SortDirection  *string  `json:"sortDirection,omitempty"` // "ASC" or "DESC"
-	Side           *string  `json:"side,omitempty"` // "BUY" or "SELL"
+	User          *string         `json:"user,omitempty"`
+	Limit         *int            `json:"limit,omitempty"`
+	Offset        *int            `json:"offset,omitempty"`
+	Market        *[]string       `json:"market,omitempty" url:"market,omitempty,comma"`
+	EventID       *[]string       `json:"eventId,omitempty" url:"eventId,omitempty,comma"`
+	Type          []ActivityType  `json:"type,omitempty" url:"type,omitempty,comma"`
+	Start         *time.Time      `json:"start,omitempty" url:"start,omitempty,unix"` // Inclusive
+	End           *time.Time      `json:"end,omitempty" url:"end,omitempty,unix"`     // Inclusive
+	SortBy        *ActivitySortBy `json:"sortBy,omitempty"`
+	SortDirection *SortDirection  `json:"sortDirection,omitempty"`
+	Side          *types.Side     `json:"side,omitempty"`
 }
 
 // TopHoldersQuery represents query parameters for top holders
 type TopHoldersQuery struct {
-	Limit      *int     `json:"limit,omitempty"`      // 0-500, default 100
-	Market     []string `json:"market"`               // Required, comma-separated condition IDs
-	MinBalance *int     `json:"minBalance,omitempty"` // 0-999999, default 1
+	Limit      *int     `json:"limit,omitempty"`           // 0-500, default 100
+	Market     []string `json:"market" url:"market,comma"` // Required, comma-separated condition IDs
+	MinBalance *int     `json:"minBalance,omitempty"`      // 0-999999, default 1
 }
 
 // TotalValueQuery represents query parameters for total value
 type TotalValueQuery struct {
-	User   *string  `json:"user,omitempty"`   // Required
-	Market *[]string `json:"market,omitempty"` // Optional
+	User   *string   `json:"user,omitempty"`                                // Required
+	Market *[]string `json:"market,omitempty" url:"market,omitempty,comma"` // Optional
 }
 
 // TotalMarketsTradedQuery represents query parameters for total markets traded
@@ -249,10 +338,63 @@ type TotalMarketsTradedQuery struct {
 
 // OpenInterestQuery represents query parameters for open interest
 type OpenInterestQuery struct {
-	Market []string `json:"market"` // Required, array of Hash64 strings
+	Market []string `json:"market" url:"market,comma"` // Required, array of Hash64 strings
 }
 
 // LiveVolumeQuery represents query parameters for live volume
 type LiveVolumeQuery struct {
 	ID int `json:"id"` // Required, event ID, minimum 1
-}
\ No newline at end of file
+}
+
+// Portfolio types
+
+// PortfolioOptions controls GetAllPositions and GetPortfolioSummary
+type PortfolioOptions struct {
+	Limit         *int                  `json:"limit,omitempty"`  // Page size
+	Offset        *int                  `json:"offset,omitempty"` // Where paging starts
+	SortBy        *PositionSortBy       `json:"sortBy,omitempty"`
+	ClosedSortBy  *ClosedPositionSortBy `json:"closedSortBy,omitempty"`
+	SortDirection *SortDirection        `json:"sortDirection,omitempty"`
+	TopN          int                   `json:"topN,omitempty"` // Winners and losers kept in the summary, default 5
+	// Category maps a position to its category, e.g. from the Gamma event's
+	// tags. The Data API does not report categories, so ExposureByCategory
+	// stays empty without it.
+	Category func(Position) string `json:"-"`
+}
+
+// AllPositions holds a user's current and closed positions
+type AllPositions struct {
+	Current []Position       `json:"current"`
+	Closed  []ClosedPosition `json:"closed"`
+}
+
+// Exposure aggregates the positions sharing an event or category
+type Exposure struct {
+	Key          string  `json:"key"`   // Event slug or category
+	Title        string  `json:"title"` // Title of the first position in the group
+	Positions    int     `json:"positions"`
+	InitialValue float64 `json:"initialValue"`
+	CurrentValue float64 `json:"currentValue"`
+	CashPnl      float64 `json:"cashPnl"`
+}
+
+// PositionTotals sums a subset of positions
+type PositionTotals struct {
+	Positions    int     `json:"positions"`
+	Size         float64 `json:"size"`
+	CurrentValue float64 `json:"currentValue"`
+}
+
+// Portfolio is a user's portfolio summary
+type Portfolio struct {
+	TotalValue       []TotalValue        `json:"totalValue"`
+	MarketsTraded    *TotalMarketsTraded `json:"marketsTraded,omitempty"`
+	CurrentPositions []Position          `json:"currentPositions"`
+
+	ExposureByEvent    []Exposure     `json:"exposureByEvent"`    // Largest current value first
+	ExposureByCategory []Exposure     `json:"exposureByCategory"` // Largest current value first
+	Redeemable         PositionTotals `json:"redeemable"`
+	Mergeable          PositionTotals `json:"mergeable"`
+	TopWinners         []Position     `json:"topWinners"` // Highest CashPnl first, gains only
+	TopLosers          []Position     `json:"topLosers"`  // Lowest CashPnl first, losses only
+}
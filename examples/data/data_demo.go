@@ -89,7 +89,7 @@ func main() {
 
 	// 5. Get Portfolio Summary
 	fmt.Println("\n5. Getting portfolio summary...")
-	portfolio, err := dataSDK.GetPortfolioSummary(userAddress)
+	portfolio, err := dataSDK.GetPortfolioSummary(userAddress, nil)
 	if err != nil {
 		log.Printf("❌ Failed to get portfolio summary: %v", err)
 	} else {
@@ -129,12 +129,7 @@ func main() {
 
 	// 8. Get All Positions (both current and closed)
 	fmt.Println("\n8. Getting all positions...")
-	allPositions, err := dataSDK.GetAllPositions(userAddress, &struct {
-		Limit         *int
-		Offset        *int
-		SortBy        *string
-		SortDirection *string
-	}{
+	allPositions, err := dataSDK.GetAllPositions(userAddress, &data.PortfolioOptions{
 		Limit: &limit,
 	})
 	if err != nil {
@@ -8,17 +8,12 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/ybina/polymarket-sdk-go/client"
 	"github.com/ybina/polymarket-sdk-go/gamma"
 	"github.com/ybina/polymarket-sdk-go/types"
 )
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
-	}
 	gammaSdk := gamma.NewGammaSDK(nil)
 	query := &gamma.UpdatedEventQuery{
 		Limit:  gamma.IntPtr(10),
@@ -41,24 +36,16 @@ func main() {
 	}
 	println("len(clobTokens):", len(clobTokens))
 	// clobTokens = clobTokens[:3]
-	privateKey := os.Getenv("POLYMARKET_KEY")
-	if privateKey == "" {
-		log.Fatal("POLYMARKET_KEY environment variable is required")
-	}
 	// set local proxy if needed
 	//proxyUrl := "http://127.0.0.1:7890"
 
-	// Create CLOB client
-	config := &client.ClientConfig{
-		Host:          "https://clob.polymarket.com",
-		ChainID:       types.ChainPolygon,
-		PrivateKey:    privateKey,
-		UseServerTime: true,
-		Timeout:       30 * time.Second,
+	// The public market channel needs no credentials, so a keyless CLOB client is enough
+	clobClient, err := client.NewClobClient(&client.ClientConfig{
+		Host:    "https://clob.polymarket.com",
+		ChainID: types.ChainPolygon,
+		Timeout: 30 * time.Second,
 		//ProxyUrl: proxyUrl,
-	}
-
-	clobClient, err := client.NewClobClient(config)
+	})
 	if err != nil {
 		log.Fatalf("Failed to create CLOB client: %v", err)
 	}
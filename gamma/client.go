@@ -1,14 +1,22 @@
 package gamma
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"reflect"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/ybina/polymarket-sdk-go/internal/instrument"
+	"github.com/ybina/polymarket-sdk-go/internal/logging"
+	"github.com/ybina/polymarket-sdk-go/internal/query"
+	"github.com/ybina/polymarket-sdk-go/observe"
+	"github.com/ybina/polymarket-sdk-go/proxy"
 )
 
 const (
@@ -18,7 +26,35 @@ const (
 
 // GammaSDKConfig represents configuration for the Gamma SDK
 type GammaSDKConfig struct {
-	Proxy *ProxyConfig `json:"proxy,omitempty"` // HTTP/HTTPS proxy configuration
+	Proxy *ProxyConfig `json:"proxy,omitempty"` // HTTP, HTTPS or SOCKS5 proxy configuration
+
+	// ProxyPool rotates requests over several proxies; takes precedence over Proxy
+	ProxyPool *proxy.Pool `json:"-"`
+
+	// BaseURL overrides GammaAPIBase, e.g. for staging or a local fake
+	BaseURL string `json:"baseUrl,omitempty"`
+
+	// HTTPClient sends every request when set; the proxy settings and Transport
+	// are then ignored
+	HTTPClient *http.Client `json:"-"`
+
+	// Transport replaces the default transport, e.g. for mTLS, connection
+	// pooling or recording; the proxy settings are then ignored
+	Transport http.RoundTripper `json:"-"`
+
+	// UserAgent sent with every request (default "gamma-go-sdk/1.0"); it
+	// takes precedence over a User-Agent in Headers
+	UserAgent string `json:"userAgent,omitempty"`
+
+	// Headers added to every request
+	Headers http.Header `json:"headers,omitempty"`
+
+	// Logger receives request and response logs at debug level
+	// (default: discard)
+	Logger *slog.Logger `json:"-"`
+
+	// Observer receives request, rate limit and latency events
+	Observer observe.Observer `json:"-"`
 }
 
 // GammaSDK represents the Polymarket Gamma API SDK
@@ -26,51 +62,57 @@ type GammaSDK struct {
 	baseURL     string
 	proxyConfig *ProxyConfig
 	httpClient  *http.Client
+	userAgent   string
+	headers     http.Header
+	logger      *slog.Logger
+	observer    observe.Observer
 }
 
 // NewGammaSDK creates a new Gamma SDK instance
 func NewGammaSDK(config *GammaSDKConfig) *GammaSDK {
-	var proxyConfig *ProxyConfig
-	if config != nil {
-		proxyConfig = config.Proxy
+	if config == nil {
+		config = &GammaSDKConfig{}
 	}
+	proxyConfig := config.Proxy
 
-	// Create HTTP client with proxy if configured
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	// Configure proxy if provided
-	if proxyConfig != nil {
-		protocol := "http"
-		if proxyConfig.Protocol != nil {
-			protocol = *proxyConfig.Protocol
-		}
-
-		// Build proxy URL with authentication if provided
-		var proxyURL string
-		if proxyConfig.Username != nil && proxyConfig.Password != nil {
-			proxyURL = fmt.Sprintf("%s://%s:%s@%s:%d",
-				protocol, *proxyConfig.Username, *proxyConfig.Password, proxyConfig.Host, proxyConfig.Port)
-		} else {
-			proxyURL = fmt.Sprintf("%s://%s:%d", protocol, proxyConfig.Host, proxyConfig.Port)
+	// Create HTTP client
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: config.Transport,
 		}
+	}
 
-		parsedProxyURL, err := url.Parse(proxyURL)
-		if err != nil {
-			fmt.Printf("Warning: Failed to parse proxy URL %s: %v\n", proxyURL, err)
-		} else {
-			httpClient.Transport = &http.Transport{
-				Proxy: http.ProxyURL(parsedProxyURL),
-			}
-			fmt.Printf("✅ Proxy configured: %s\n", parsedProxyURL.String())
-		}
+	// Route through the proxy pool or proxy if provided; an invalid proxy
+	// fails each request with its validation error
+	var src proxy.Source
+	if config.ProxyPool != nil {
+		src = config.ProxyPool
+	} else if proxyConfig != nil {
+		src = proxyConfig
+	}
+	if src != nil && config.HTTPClient == nil && config.Transport == nil {
+		httpClient.Transport = proxy.NewTransport(src)
 	}
 
 	client := &GammaSDK{
 		baseURL:     GammaAPIBase,
 		proxyConfig: proxyConfig,
 		httpClient:  httpClient,
+		userAgent:   "gamma-go-sdk/1.0",
+		headers:     config.Headers.Clone(),
+		logger:      logging.OrDiscard(config.Logger),
+		observer:    observe.OrNop(config.Observer),
+	}
+	if config.BaseURL != "" {
+		client.baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	if ua := config.Headers.Get("User-Agent"); ua != "" {
+		client.userAgent = ua
+	}
+	if config.UserAgent != "" {
+		client.userAgent = config.UserAgent
 	}
 
 	return client
@@ -82,84 +124,42 @@ func (g *GammaSDK) GetHttpClient() *http.Client {
 }
 
 // buildURL constructs a URL with query parameters
-func (g *GammaSDK) buildURL(endpoint string, query interface{}) (string, error) {
+func (g *GammaSDK) buildURL(endpoint string, q interface{}) (string, error) {
 	u, err := url.Parse(g.baseURL + endpoint)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse URL: %w", err)
 	}
 
-	if query != nil {
-		values := url.Values{}
-		v := reflect.ValueOf(query)
-
-		// Dereference pointer if necessary
-		if v.Kind() == reflect.Ptr {
-			if v.IsNil() {
-				return u.String(), nil
-			}
-			v = v.Elem()
-		}
-
-		t := v.Type()
-
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			fieldValue := v.Field(i)
-
-			// Skip nil pointer fields
-			if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
-				continue
-			}
-
-			// Get the JSON tag for the field name
-			jsonTag := field.Tag.Get("json")
-			if jsonTag == "" {
-				continue
-			}
-
-			// Handle omitempty
-			if strings.Contains(jsonTag, "omitempty") && fieldValue.IsZero() {
-				continue
-			}
-
-			// Extract field name from JSON tag
-			fieldName := strings.Split(jsonTag, ",")[0]
-			if fieldName == "" {
-				continue
-			}
-
-			// Convert value to string and add to query params
-			var strValue string
-			if fieldValue.Kind() == reflect.Ptr {
-				strValue = fmt.Sprintf("%v", fieldValue.Elem().Interface())
-			} else {
-				strValue = fmt.Sprintf("%v", fieldValue.Interface())
-			}
-
-			values.Add(fieldName, strValue)
-		}
-
-		u.RawQuery = values.Encode()
+	if err := query.Apply(u, q); err != nil {
+		return "", fmt.Errorf("failed to encode query: %w", err)
 	}
 
 	return u.String(), nil
 }
 
 // createRequest creates an HTTP request with proper headers and proxy support
-func (g *GammaSDK) createRequest(method, url string) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, nil)
+func (g *GammaSDK) createRequest(ctx context.Context, method, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "gamma-go-sdk/1.0")
+	for key, values := range g.headers {
+		req.Header[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+	}
+	req.Header.Set("User-Agent", g.userAgent)
 
 	return req, nil
 }
 
 // makeRequest makes an HTTP request and returns the response
 func (g *GammaSDK) makeRequest(method, endpoint string, query interface{}) (*APIResponse, error) {
+	return g.makeRequestContext(context.Background(), method, endpoint, query)
+}
+
+// makeRequestContext makes an HTTP request bound to ctx and returns the response
+func (g *GammaSDK) makeRequestContext(ctx context.Context, method, endpoint string, query interface{}) (*APIResponse, error) {
 	// Build URL with query parameters
 	fullURL, err := g.buildURL(endpoint, query)
 	if err != nil {
@@ -167,13 +167,13 @@ func (g *GammaSDK) makeRequest(method, endpoint string, query interface{}) (*API
 	}
 
 	// Create request
-	req, err := g.createRequest(method, fullURL)
+	req, err := g.createRequest(ctx, method, fullURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Make the request
-	resp, err := g.httpClient.Do(req)
+	resp, err := instrument.Do("gamma", g.httpClient, g.logger, g.observer, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -348,18 +348,11 @@ func (g *GammaSDK) unmarshalEventsResponse(resp *APIResponse, operation string)
 		return nil, err
 	}
 
-	// Parse as array of maps first to transform each item
-	var rawItems []map[string]interface{}
-	if err := json.Unmarshal(data, &rawItems); err != nil {
+	var events []Event
+	if err := json.Unmarshal(data, &events); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal %s data: %w", operation, err)
 	}
 
-	// Transform each event
-	events := make([]Event, len(rawItems))
-	for i, item := range rawItems {
-		events[i] = g.transformEventData(item)
-	}
-
 	return events, nil
 }
 
@@ -370,18 +363,11 @@ func (g *GammaSDK) unmarshalMarketsResponse(resp *APIResponse, operation string)
 		return nil, err
 	}
 
-	// Parse as array of maps first to transform each item
-	var rawItems []map[string]interface{}
-	if err := json.Unmarshal(data, &rawItems); err != nil {
+	var markets []Market
+	if err := json.Unmarshal(data, &markets); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal %s data: %w", operation, err)
 	}
 
-	// Transform each market
-	markets := make([]Market, len(rawItems))
-	for i, item := range rawItems {
-		markets[i] = g.transformMarketData(item)
-	}
-
 	return markets, nil
 }
 
@@ -400,101 +386,6 @@ func (g *GammaSDK) unmarshalSearchResponse(resp *APIResponse, operation string)
 	return &result, nil
 }
 
-// parseJSONArray parses a JSON array from either string or already parsed array
-func (g *GammaSDK) parseJSONArray(value interface{}) []string {
-	if value == nil {
-		return []string{}
-	}
-
-	switch v := value.(type) {
-	case []interface{}:
-		// Already an array of interfaces
-		result := make([]string, len(v))
-		for i, item := range v {
-			result[i] = fmt.Sprintf("%v", item)
-		}
-		return result
-	case []string:
-		// Already a string array
-		return v
-	case string:
-		// JSON string that needs to be parsed
-		var result []string
-		if err := json.Unmarshal([]byte(v), &result); err != nil {
-			// If parsing fails, return empty array
-			return []string{}
-		}
-		return result
-	default:
-		// Any other type, convert to string
-		return []string{fmt.Sprintf("%v", v)}
-	}
-}
-
-// transformMarketData transforms market data to parse JSON string fields
-func (g *GammaSDK) transformMarketData(item map[string]interface{}) Market {
-	market := Market{}
-
-	// Use json.Unmarshal for proper type conversion
-	itemBytes, _ := json.Marshal(item)
-	json.Unmarshal(itemBytes, &market)
-
-	// Parse JSON array fields that might be strings
-	if outcomes, ok := item["outcomes"]; ok {
-		market.Outcomes = g.parseJSONArray(outcomes)
-	}
-
-	if outcomePrices, ok := item["outcomePrices"]; ok {
-		market.OutcomePrices = g.parseJSONArray(outcomePrices)
-	}
-
-	if clobTokenIds, ok := item["clobTokenIds"]; ok {
-		market.ClobTokenIDs = g.parseJSONArray(clobTokenIds)
-	}
-
-	return market
-}
-
-// transformEventData transforms event data to parse JSON string fields in nested markets
-func (g *GammaSDK) transformEventData(item map[string]interface{}) Event {
-	event := Event{}
-
-	// Use json.Unmarshal for proper type conversion
-	itemBytes, _ := json.Marshal(item)
-	json.Unmarshal(itemBytes, &event)
-
-	// Transform nested markets
-	if marketsData, ok := item["markets"]; ok {
-		if markets, ok := marketsData.([]interface{}); ok {
-			event.Markets = make([]EventMarket, len(markets))
-			for i, marketItem := range markets {
-				if marketMap, ok := marketItem.(map[string]interface{}); ok {
-					market := EventMarket{}
-					marketBytes, _ := json.Marshal(marketMap)
-					json.Unmarshal(marketBytes, &market)
-
-					// Parse JSON array fields
-					if outcomes, ok := marketMap["outcomes"]; ok {
-						market.Outcomes = g.parseJSONArray(outcomes)
-					}
-
-					if outcomePrices, ok := marketMap["outcomePrices"]; ok {
-						market.OutcomePrices = g.parseJSONArray(outcomePrices)
-					}
-
-					if clobTokenIds, ok := marketMap["clobTokenIds"]; ok {
-						market.ClobTokenIDs = g.parseJSONArray(clobTokenIds)
-					}
-
-					event.Markets[i] = market
-				}
-			}
-		}
-	}
-
-	return event
-}
-
 // Health check
 // GetHealth performs a health check on the Gamma API
 func (g *GammaSDK) GetHealth() (map[string]interface{}, error) {
@@ -528,10 +419,60 @@ func (g *GammaSDK) GetTeams(query *TeamQuery) ([]Team, error) {
 	return g.unmarshalTeamsResponse(resp, "Get teams")
 }
 
+// Sports API
+// GetSports gets sports metadata for every league, including resolution
+// sources and the tag IDs used to filter each league's events
+func (g *GammaSDK) GetSports() ([]Sport, error) {
+	return g.getSports(context.Background())
+}
+
+func (g *GammaSDK) getSports(ctx context.Context) ([]Sport, error) {
+	resp, err := g.makeRequestContext(ctx, "GET", "/sports", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.extractResponseData(resp, "Get sports")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Sport
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sports response: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetSportsMarketTypes gets the valid sports market types, e.g. "moneyline"
+func (g *GammaSDK) GetSportsMarketTypes() ([]string, error) {
+	resp, err := g.makeRequest("GET", "/sports/market-types", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.extractResponseData(resp, "Get sports market types")
+	if err != nil {
+		return nil, err
+	}
+
+	var result SportsMarketTypesResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sports market types response: %w", err)
+	}
+
+	return result.MarketTypes, nil
+}
+
 // Tags API
 // GetTags gets list of tags with optional filtering
 func (g *GammaSDK) GetTags(query TagQuery) ([]UpdatedTag, error) {
-	resp, err := g.makeRequest("GET", "/tags", query)
+	return g.getTags(context.Background(), query)
+}
+
+func (g *GammaSDK) getTags(ctx context.Context, query TagQuery) ([]UpdatedTag, error) {
+	resp, err := g.makeRequestContext(ctx, "GET", "/tags", query)
 	if err != nil {
 		return nil, err
 	}
@@ -626,11 +567,15 @@ func (g *GammaSDK) GetTagsRelatedToTagSlug(slug string, query *RelatedTagsQuery)
 // Events API
 // GetEvents gets list of events with optional filtering
 func (g *GammaSDK) GetEvents(query *UpdatedEventQuery) ([]Event, error) {
+	return g.getEvents(context.Background(), query)
+}
+
+func (g *GammaSDK) getEvents(ctx context.Context, query *UpdatedEventQuery) ([]Event, error) {
 	if query == nil {
 		query = &UpdatedEventQuery{}
 	}
 
-	resp, err := g.makeRequest("GET", "/events", query)
+	resp, err := g.makeRequestContext(ctx, "GET", "/events", query)
 	if err != nil {
 		return nil, err
 	}
@@ -650,26 +595,12 @@ func (g *GammaSDK) GetEventsPaginated(query PaginatedEventQuery) (*PaginatedEven
 		return nil, err
 	}
 
-	// Parse the paginated response
-	var rawResponse struct {
-		Data       []map[string]interface{} `json:"data"`
-		Pagination Pagination               `json:"pagination"`
-	}
-
-	if err := json.Unmarshal(data, &rawResponse); err != nil {
+	var result PaginatedEventsResponse
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal paginated events response: %w", err)
 	}
 
-	// Transform each event
-	events := make([]Event, len(rawResponse.Data))
-	for i, item := range rawResponse.Data {
-		events[i] = g.transformEventData(item)
-	}
-
-	return &PaginatedEventsResponse{
-		Data:       events,
-		Pagination: rawResponse.Pagination,
-	}, nil
+	return &result, nil
 }
 
 // GetEventById gets a specific event by ID
@@ -692,13 +623,11 @@ func (g *GammaSDK) GetEventById(id int, query *EventByIdQuery) (*Event, error) {
 		return nil, err
 	}
 
-	// Parse and transform the event
-	var rawEvent map[string]interface{}
-	if err := json.Unmarshal(data, &rawEvent); err != nil {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
 	}
 
-	event := g.transformEventData(rawEvent)
 	return &event, nil
 }
 
@@ -732,24 +661,26 @@ func (g *GammaSDK) GetEventBySlug(slug string, query *EventByIdQuery) (*Event, e
 		return nil, err
 	}
 
-	// Parse and transform the event
-	var rawEvent map[string]interface{}
-	if err := json.Unmarshal(data, &rawEvent); err != nil {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
 	}
 
-	event := g.transformEventData(rawEvent)
 	return &event, nil
 }
 
 // Markets API
 // GetMarkets gets list of markets with optional filtering
 func (g *GammaSDK) GetMarkets(query *UpdatedMarketQuery) ([]Market, error) {
+	return g.getMarkets(context.Background(), query)
+}
+
+func (g *GammaSDK) getMarkets(ctx context.Context, query *UpdatedMarketQuery) ([]Market, error) {
 	if query == nil {
 		query = &UpdatedMarketQuery{}
 	}
 
-	resp, err := g.makeRequest("GET", "/markets", query)
+	resp, err := g.makeRequestContext(ctx, "GET", "/markets", query)
 	if err != nil {
 		return nil, err
 	}
@@ -777,13 +708,11 @@ func (g *GammaSDK) GetMarketById(id int, query *MarketByIdQuery) (*Market, error
 		return nil, err
 	}
 
-	// Parse and transform the market
-	var rawMarket map[string]interface{}
-	if err := json.Unmarshal(data, &rawMarket); err != nil {
+	var market Market
+	if err := json.Unmarshal(data, &market); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal market data: %w", err)
 	}
 
-	market := g.transformMarketData(rawMarket)
 	return &market, nil
 }
 
@@ -817,20 +746,62 @@ func (g *GammaSDK) GetMarketBySlug(slug string, query *MarketByIdQuery) (*Market
 		return nil, err
 	}
 
-	// Parse and transform the market
-	var rawMarket map[string]interface{}
-	if err := json.Unmarshal(data, &rawMarket); err != nil {
+	var market Market
+	if err := json.Unmarshal(data, &market); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal market data: %w", err)
 	}
 
-	market := g.transformMarketData(rawMarket)
 	return &market, nil
 }
 
+// NegRiskConditionIDs returns the condition IDs of every market of the neg
+// risk event the market conditionID belongs to, conditionID included. It
+// resolves conversions for data.Ledger:
+//
+//	ledger.Markets = func(id string) ([]string, error) { return gammaSDK.NegRiskConditionIDs(ctx, id) }
+func (g *GammaSDK) NegRiskConditionIDs(ctx context.Context, conditionID string) ([]string, error) {
+	markets, err := g.getMarkets(ctx, &UpdatedMarketQuery{ConditionIDs: []string{conditionID}})
+	if err != nil {
+		return nil, err
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("market %s not found", conditionID)
+	}
+	market := markets[0]
+	if market.NegRisk == nil || !*market.NegRisk || len(market.Events) == 0 {
+		return nil, fmt.Errorf("market %s is not part of a neg risk event", conditionID)
+	}
+
+	resp, err := g.makeRequestContext(ctx, "GET", "/events/"+market.Events[0].ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := g.extractResponseData(resp, "Get neg risk event")
+	if err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+	}
+
+	conditionIDs := make([]string, 0, len(event.Markets))
+	for _, m := range event.Markets {
+		if m.ConditionID != "" {
+			conditionIDs = append(conditionIDs, m.ConditionID)
+		}
+	}
+	return conditionIDs, nil
+}
+
 // Series API
 // GetSeries gets list of series with filtering and pagination
 func (g *GammaSDK) GetSeries(query SeriesQuery) ([]Series, error) {
-	resp, err := g.makeRequest("GET", "/series", query)
+	return g.getSeries(context.Background(), query)
+}
+
+func (g *GammaSDK) getSeries(ctx context.Context, query SeriesQuery) ([]Series, error) {
+	resp, err := g.makeRequestContext(ctx, "GET", "/series", query)
 	if err != nil {
 		return nil, err
 	}
@@ -855,11 +826,15 @@ func (g *GammaSDK) GetSeriesById(id int, query *SeriesByIdQuery) (*Series, error
 // Comments API
 // GetComments gets list of comments with optional filtering
 func (g *GammaSDK) GetComments(query *CommentQuery) ([]Comment, error) {
+	return g.getComments(context.Background(), query)
+}
+
+func (g *GammaSDK) getComments(ctx context.Context, query *CommentQuery) ([]Comment, error) {
 	if query == nil {
 		query = &CommentQuery{}
 	}
 
-	resp, err := g.makeRequest("GET", "/comments", query)
+	resp, err := g.makeRequestContext(ctx, "GET", "/comments", query)
 	if err != nil {
 		return nil, err
 	}
@@ -898,7 +873,11 @@ func (g *GammaSDK) GetCommentsByUserAddress(userAddress string, query *CommentsB
 // Search API
 // Search searches across markets, events, and profiles
 func (g *GammaSDK) Search(query SearchQuery) (*SearchResponse, error) {
-	resp, err := g.makeRequest("GET", "/public-search", query)
+	return g.search(context.Background(), query)
+}
+
+func (g *GammaSDK) search(ctx context.Context, query SearchQuery) (*SearchResponse, error) {
+	resp, err := g.makeRequestContext(ctx, "GET", "/public-search", query)
 	if err != nil {
 		return nil, err
 	}
@@ -941,6 +920,28 @@ func (g *GammaSDK) GetFeaturedEvents(query *UpdatedEventQuery) ([]Event, error)
 	return g.GetEvents(query)
 }
 
+// GetMarketsBySlugs gets a known set of markets in a single request.
+// Slugs with no matching market are skipped.
+func (g *GammaSDK) GetMarketsBySlugs(slugs []string) ([]Market, error) {
+	if len(slugs) == 0 {
+		return []Market{}, nil
+	}
+
+	limit := len(slugs)
+	return g.GetMarkets(&UpdatedMarketQuery{Slug: slugs, Limit: &limit})
+}
+
+// GetMarketsByTokenIDs gets the markets trading the given CLOB token IDs in a
+// single request. A market is returned once even when several of its tokens are listed.
+func (g *GammaSDK) GetMarketsByTokenIDs(tokenIDs []string) ([]Market, error) {
+	if len(tokenIDs) == 0 {
+		return []Market{}, nil
+	}
+
+	limit := len(tokenIDs)
+	return g.GetMarkets(&UpdatedMarketQuery{ClobTokenIDs: tokenIDs, Limit: &limit})
+}
+
 // GetActiveMarkets gets active markets
 func (g *GammaSDK) GetActiveMarkets(query *UpdatedMarketQuery) ([]Market, error) {
 	if query == nil {
@@ -0,0 +1,71 @@
+package gamma
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewGammaSDKConfig(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(&GammaSDKConfig{
+		BaseURL:   srv.URL + "/staging/",
+		UserAgent: "bot/2",
+		Headers:   http.Header{"X-Desk": {"alpha"}, "User-Agent": {"bot/1"}},
+	})
+	if _, err := sdk.GetHealth(); err != nil {
+		t.Fatalf("GetHealth() error = %v", err)
+	}
+	if got.URL.Path != "/staging/health" || got.UserAgent() != "bot/2" || got.Header.Get("X-Desk") != "alpha" {
+		t.Errorf("request %s with User-Agent %q and X-Desk %q", got.URL.Path, got.UserAgent(), got.Header.Get("X-Desk"))
+	}
+
+	// Without UserAgent the header replaces the default
+	sdk = NewGammaSDK(&GammaSDKConfig{BaseURL: srv.URL, Headers: http.Header{"User-Agent": {"bot/1"}}})
+	if _, err := sdk.GetHealth(); err != nil {
+		t.Fatalf("GetHealth() error = %v", err)
+	}
+	if got.UserAgent() != "bot/1" {
+		t.Errorf("User-Agent = %q, want the one in Headers", got.UserAgent())
+	}
+
+	if NewGammaSDK(nil).baseURL != GammaAPIBase {
+		t.Error("NewGammaSDK(nil) does not use GammaAPIBase")
+	}
+}
+
+func TestNegRiskConditionIDs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/markets" && r.URL.Query().Get("condition_ids") == "0xa":
+			w.Write([]byte(`[{"id":"1","conditionId":"0xa","negRisk":true,"events":[{"id":"7"}]}]`))
+		case r.URL.Path == "/markets" && r.URL.Query().Get("condition_ids") == "0xz":
+			w.Write([]byte(`[{"id":"9","conditionId":"0xz","negRisk":false}]`))
+		case r.URL.Path == "/markets":
+			w.Write([]byte(`[]`))
+		case r.URL.Path == "/events/7":
+			w.Write([]byte(`{"id":"7","markets":[{"conditionId":"0xa"},{"conditionId":"0xb"},{"conditionId":""},{"conditionId":"0xc"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	sdk := NewGammaSDK(&GammaSDKConfig{BaseURL: srv.URL})
+
+	got, err := sdk.NegRiskConditionIDs(t.Context(), "0xa")
+	if err != nil || strings.Join(got, ",") != "0xa,0xb,0xc" {
+		t.Errorf("NegRiskConditionIDs() = %v, %v, want [0xa 0xb 0xc]", got, err)
+	}
+	for _, conditionID := range []string{"0xz", "0xmissing"} {
+		if _, err := sdk.NegRiskConditionIDs(t.Context(), conditionID); err == nil {
+			t.Errorf("NegRiskConditionIDs(%s) error = nil", conditionID)
+		}
+	}
+}
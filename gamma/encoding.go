@@ -0,0 +1,150 @@
+package gamma
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StringArray is a list of strings that the Gamma API sends either as a JSON
+// array or as a string holding a JSON-encoded array, e.g. "[\"Yes\", \"No\"]".
+// Non-string elements keep their JSON text, so [0.5, 1] decodes to "0.5", "1".
+type StringArray []string
+
+// UnmarshalJSON decodes both the plain and the string-encoded form
+func (a *StringArray) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	kind := "string array"
+	if len(data) > 0 && data[0] == '"' {
+		kind = "string-encoded array"
+		var encoded string
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return fmt.Errorf("invalid %s %s: %w", kind, truncate(data), err)
+		}
+		if encoded == "" {
+			*a = StringArray{}
+			return nil
+		}
+		data = []byte(encoded)
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("invalid %s %s: %w", kind, truncate(data), err)
+	}
+
+	result := make(StringArray, len(items))
+	for i, item := range items {
+		switch {
+		case len(item) > 0 && item[0] == '"':
+			if err := json.Unmarshal(item, &result[i]); err != nil {
+				return fmt.Errorf("invalid string array element %s: %w", truncate(item), err)
+			}
+		case len(item) > 0 && (item[0] == '{' || item[0] == '['):
+			return fmt.Errorf("invalid string array element %s: expected a scalar", truncate(item))
+		case bytes.Equal(item, []byte("null")):
+			result[i] = ""
+		default:
+			result[i] = string(item)
+		}
+	}
+
+	*a = result
+	return nil
+}
+
+// StringFloat is a number that the Gamma API sends either as a JSON number or
+// as a decimal string. An empty string decodes to 0.
+type StringFloat float64
+
+// UnmarshalJSON decodes both the number and the string form
+func (f *StringFloat) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := data
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid number %s: %w", truncate(data), err)
+		}
+		if s == "" {
+			*f = 0
+			return nil
+		}
+		text = []byte(s)
+	}
+
+	v, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s: %w", truncate(data), err)
+	}
+
+	*f = StringFloat(v)
+	return nil
+}
+
+// Float64 returns the value as a float64
+func (f StringFloat) Float64() float64 {
+	return float64(f)
+}
+
+// IDList is a list of numeric IDs that the Gamma API sends as a
+// comma-separated string ("1,745,100639") or as a JSON array
+type IDList []int
+
+// UnmarshalJSON decodes both the comma-separated and the array form
+func (l *IDList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var parts []string
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("invalid ID list %s: %w", truncate(data), err)
+		}
+		parts = strings.Split(s, ",")
+	} else {
+		var items StringArray
+		if err := items.UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("invalid ID list %s: %w", truncate(data), err)
+		}
+		parts = items
+	}
+
+	result := make(IDList, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return fmt.Errorf("invalid ID %q in list %s: %w", part, truncate(data), err)
+		}
+		result = append(result, id)
+	}
+
+	*l = result
+	return nil
+}
+
+// truncate shortens raw JSON for error messages
+func truncate(data []byte) string {
+	const max = 64
+	if len(data) > max {
+		return string(data[:max]) + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,210 @@
+package gamma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStringArrayUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    StringArray
+		wantErr bool
+	}{
+		{name: "string encoded", input: `"[\"Yes\", \"No\"]"`, want: StringArray{"Yes", "No"}},
+		{name: "plain array", input: `["Yes","No"]`, want: StringArray{"Yes", "No"}},
+		{name: "string encoded numbers", input: `"[0.35, 0.65]"`, want: StringArray{"0.35", "0.65"}},
+		{name: "mixed scalars", input: `["a", 1, true, null]`, want: StringArray{"a", "1", "true", ""}},
+		{name: "empty string", input: `""`, want: StringArray{}},
+		{name: "empty array", input: `[]`, want: StringArray{}},
+		{name: "null", input: `null`, want: nil},
+		{name: "invalid encoded", input: `"[\"Yes\""`, wantErr: true},
+		{name: "not an array", input: `"Yes"`, wantErr: true},
+		{name: "object", input: `{"a": 1}`, wantErr: true},
+		{name: "nested array", input: `[["a"]]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringArray
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unmarshal() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringFloatUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    StringFloat
+		wantErr bool
+	}{
+		{name: "number", input: `1234.5`, want: 1234.5},
+		{name: "string", input: `"1234.5"`, want: 1234.5},
+		{name: "exponent string", input: `"1e3"`, want: 1000},
+		{name: "empty string", input: `""`, want: 0},
+		{name: "null", input: `null`, want: 0},
+		{name: "garbage", input: `"abc"`, wantErr: true},
+		{name: "bool", input: `true`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringFloat
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMarketsReportsDecodeErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "1", "outcomes": "[\"Yes\", \"No\""}]`)
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	if _, err := sdk.GetMarkets(nil); err == nil || !strings.Contains(err.Error(), "string-encoded array") {
+		t.Fatalf("GetMarkets() error = %v, want a string-encoded array error", err)
+	}
+}
+
+func TestEventDecodesNestedMarkets(t *testing.T) {
+	input := `{
+		"id": "903",
+		"liquidity": 1500.25,
+		"volume": "98000",
+		"markets": [{
+			"id": "1",
+			"liquidity": "250.5",
+			"outcomes": "[\"Yes\", \"No\"]",
+			"outcomePrices": "[\"0.3\", \"0.7\"]",
+			"clobTokenIds": "[\"111\", \"222\"]"
+		}]
+	}`
+
+	var event Event
+	if err := json.Unmarshal([]byte(input), &event); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if event.Liquidity == nil || *event.Liquidity != 1500.25 || event.Volume == nil || *event.Volume != 98000 {
+		t.Errorf("unexpected event numbers: liquidity %v, volume %v", event.Liquidity, event.Volume)
+	}
+	market := event.Markets[0]
+	if market.Liquidity == nil || *market.Liquidity != 250.5 {
+		t.Errorf("unexpected market liquidity: %v", market.Liquidity)
+	}
+	if !reflect.DeepEqual(market.OutcomePrices, StringArray{"0.3", "0.7"}) || !reflect.DeepEqual(market.ClobTokenIDs, StringArray{"111", "222"}) {
+		t.Errorf("unexpected market arrays: %v %v", market.OutcomePrices, market.ClobTokenIDs)
+	}
+}
+
+// benchmarkMarkets builds a Gamma /markets response with n markets
+func benchmarkMarkets(n int) []byte {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"id":"%d","question":"Will market %d resolve yes?","conditionId":"0x%064x","slug":"market-%d",`+
+			`"liquidity":"%d.25","volume":"%d.5","active":true,"closed":false,"volumeNum":%d.5,"liquidityNum":%d.25,`+
+			`"outcomes":"[\"Yes\", \"No\"]","outcomePrices":"[\"0.42\", \"0.58\"]",`+
+			`"clobTokenIds":"[\"%d1234567890123456789012345678901234567890\", \"%d9876543210987654321098765432109876543210\"]",`+
+			`"description":"A long description of the market that adds some realistic weight to the payload.",`+
+			`"image":"https://example.com/%d.png","icon":"https://example.com/%d-icon.png"}`,
+			i, i, i, i, i*10, i*100, i*100, i*10, i, i, i, i)
+	}
+	sb.WriteString("]")
+	return []byte(sb.String())
+}
+
+// legacyMarket mirrors the fields the previous decoder filled before
+// patching in the string-encoded arrays
+type legacyMarket struct {
+	ID            string   `json:"id"`
+	Question      string   `json:"question"`
+	ConditionID   string   `json:"conditionId"`
+	Slug          string   `json:"slug"`
+	Liquidity     *string  `json:"liquidity,omitempty"`
+	Volume        string   `json:"volume"`
+	Active        bool     `json:"active"`
+	Closed        bool     `json:"closed"`
+	VolumeNum     float64  `json:"volumeNum"`
+	LiquidityNum  *float64 `json:"liquidityNum,omitempty"`
+	Outcomes      []string `json:"outcomes"`
+	OutcomePrices []string `json:"outcomePrices"`
+	ClobTokenIDs  []string `json:"clobTokenIds"`
+	Description   string   `json:"description"`
+	Image         string   `json:"image"`
+	Icon          string   `json:"icon"`
+}
+
+// legacyDecodeMarkets reproduces the previous map round-trip decoding
+func legacyDecodeMarkets(data []byte) ([]legacyMarket, error) {
+	var rawItems []map[string]interface{}
+	if err := json.Unmarshal(data, &rawItems); err != nil {
+		return nil, err
+	}
+
+	markets := make([]legacyMarket, len(rawItems))
+	for i, item := range rawItems {
+		itemBytes, _ := json.Marshal(item)
+		json.Unmarshal(itemBytes, &markets[i])
+
+		parse := func(key string) []string {
+			var parsed []string
+			if s, ok := item[key].(string); ok {
+				json.Unmarshal([]byte(s), &parsed)
+			}
+			return parsed
+		}
+		markets[i].Outcomes = parse("outcomes")
+		markets[i].OutcomePrices = parse("outcomePrices")
+		markets[i].ClobTokenIDs = parse("clobTokenIds")
+	}
+	return markets, nil
+}
+
+func BenchmarkDecodeMarkets(b *testing.B) {
+	data := benchmarkMarkets(1000)
+
+	b.Run("legacy-map-roundtrip", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			if _, err := legacyDecodeMarkets(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("unmarshal-json", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var markets []Market
+			if err := json.Unmarshal(data, &markets); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package gamma
+
+import (
+	"context"
+	"iter"
+
+	"github.com/ybina/polymarket-sdk-go/internal/paging"
+)
+
+// DefaultPageSize is the page size used by the Iter methods when none is set
+const DefaultPageSize = 100
+
+// IterOptions controls how the Iter methods page through results
+type IterOptions struct {
+	// PageSize is the number of items requested per page. Defaults to the
+	// query's Limit, or DefaultPageSize when that is unset too.
+	PageSize int
+	// Prefetch is the number of pages requested ahead, concurrently, while
+	// the current page is consumed. 0 fetches one page at a time.
+	Prefetch int
+}
+
+// pageParams resolves the page size and prefetch depth for an iterator
+func (o *IterOptions) pageParams(limit *int) (int, int) {
+	pageSize, prefetch := DefaultPageSize, 0
+	if limit != nil && *limit > 0 {
+		pageSize = *limit
+	}
+	if o != nil {
+		if o.PageSize > 0 {
+			pageSize = o.PageSize
+		}
+		if o.Prefetch > 0 {
+			prefetch = o.Prefetch
+		}
+	}
+	return pageSize, prefetch
+}
+
+// startOffset returns the offset iteration starts from
+func startOffset(offset *int) int {
+	if offset != nil && *offset > 0 {
+		return *offset
+	}
+	return 0
+}
+
+// IterEvents iterates over every event matching query, paging transparently.
+// The query's Offset sets where iteration starts; Limit is only used as the
+// default page size. Items already yielded stay valid when a later page
+// fails: the error is yielded once as the final element.
+func (g *GammaSDK) IterEvents(ctx context.Context, query *UpdatedEventQuery, options *IterOptions) iter.Seq2[Event, error] {
+	base := UpdatedEventQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	return paging.Iterate(ctx, startOffset(base.Offset), pageSize, prefetch, func(ctx context.Context, limit, offset int) ([]Event, error) {
+		q := base
+		q.Limit, q.Offset = &limit, &offset
+		return g.getEvents(ctx, &q)
+	})
+}
+
+// IterMarkets iterates over every market matching query, paging transparently.
+// See IterEvents for how the query and errors are handled.
+func (g *GammaSDK) IterMarkets(ctx context.Context, query *UpdatedMarketQuery, options *IterOptions) iter.Seq2[Market, error] {
+	base := UpdatedMarketQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	return paging.Iterate(ctx, startOffset(base.Offset), pageSize, prefetch, func(ctx context.Context, limit, offset int) ([]Market, error) {
+		q := base
+		q.Limit, q.Offset = &limit, &offset
+		return g.getMarkets(ctx, &q)
+	})
+}
+
+// IterSeries iterates over every series matching query, paging transparently.
+// See IterEvents for how the query and errors are handled.
+func (g *GammaSDK) IterSeries(ctx context.Context, query SeriesQuery, options *IterOptions) iter.Seq2[Series, error] {
+	pageSize, prefetch := options.pageParams(query.Limit)
+
+	return paging.Iterate(ctx, startOffset(query.Offset), pageSize, prefetch, func(ctx context.Context, limit, offset int) ([]Series, error) {
+		q := query
+		q.Limit, q.Offset = &limit, &offset
+		return g.getSeries(ctx, q)
+	})
+}
+
+// IterTags iterates over every tag matching query, paging transparently.
+// See IterEvents for how the query and errors are handled.
+func (g *GammaSDK) IterTags(ctx context.Context, query TagQuery, options *IterOptions) iter.Seq2[UpdatedTag, error] {
+	pageSize, prefetch := options.pageParams(query.Limit)
+
+	return paging.Iterate(ctx, startOffset(query.Offset), pageSize, prefetch, func(ctx context.Context, limit, offset int) ([]UpdatedTag, error) {
+		q := query
+		q.Limit, q.Offset = &limit, &offset
+		return g.getTags(ctx, q)
+	})
+}
+
+// IterComments iterates over every comment matching query, paging transparently.
+// See IterEvents for how the query and errors are handled.
+func (g *GammaSDK) IterComments(ctx context.Context, query *CommentQuery, options *IterOptions) iter.Seq2[Comment, error] {
+	base := CommentQuery{}
+	if query != nil {
+		base = *query
+	}
+	pageSize, prefetch := options.pageParams(base.Limit)
+
+	return paging.Iterate(ctx, startOffset(base.Offset), pageSize, prefetch, func(ctx context.Context, limit, offset int) ([]Comment, error) {
+		q := base
+		q.Limit, q.Offset = &limit, &offset
+		return g.getComments(ctx, &q)
+	})
+}
+
+// IterSearch iterates over search result pages, starting at the query's Page
+// (default 1) and stopping once the API reports no further results. Each page
+// holds up to LimitPerType hits of every type. A failed request is yielded
+// once as the final element; cancelling ctx aborts an in-flight request,
+// which suits autocomplete where only the latest input matters.
+func (g *GammaSDK) IterSearch(ctx context.Context, query SearchQuery) iter.Seq2[*SearchResponse, error] {
+	return func(yield func(*SearchResponse, error) bool) {
+		page := 1
+		if query.Page != nil && *query.Page > 1 {
+			page = *query.Page
+		}
+
+		for ; ; page++ {
+			q := query
+			q.Page = &page
+
+			result, err := g.search(ctx, q)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(result, nil) {
+				return
+			}
+
+			empty := len(result.Events) == 0 && len(result.Tags) == 0 && len(result.Profiles) == 0
+			if result.Pagination == nil || !result.Pagination.HasMore || empty {
+				return
+			}
+		}
+	}
+}
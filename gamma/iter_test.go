@@ -0,0 +1,92 @@
+package gamma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newPagedServer serves total items of the form {"id": "<n>"} from endpoint,
+// honouring limit and offset, and fails requests at failOffset
+func newPagedServer(t *testing.T, endpoint string, total, failOffset int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != endpoint {
+			http.NotFound(w, r)
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if r.URL.Query().Get("active") != "true" {
+			http.Error(w, "filter not forwarded", http.StatusBadRequest)
+			return
+		}
+		if offset == failOffset {
+			http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+			return
+		}
+
+		items := []map[string]string{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			items = append(items, map[string]string{"id": strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(items)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestIterEvents(t *testing.T) {
+	srv := newPagedServer(t, "/events", 23, -1)
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	active := true
+	var ids []string
+	for event, err := range sdk.IterEvents(context.Background(), &UpdatedEventQuery{Active: &active}, &IterOptions{PageSize: 5, Prefetch: 2}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+
+	if len(ids) != 23 {
+		t.Fatalf("got %d events, want 23", len(ids))
+	}
+	for i, id := range ids {
+		if id != strconv.Itoa(i) {
+			t.Fatalf("event %d has id %s", i, id)
+		}
+	}
+}
+
+func TestIterMarketsError(t *testing.T) {
+	srv := newPagedServer(t, "/markets", 100, 20)
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	active, offset := true, 10
+	var count int
+	var iterErr error
+	for market, err := range sdk.IterMarkets(context.Background(), &UpdatedMarketQuery{Active: &active, Offset: &offset, Limit: &offset}, nil) {
+		if err != nil {
+			iterErr = err
+			break
+		}
+		if want := fmt.Sprint(offset + count); market.ID != want {
+			t.Fatalf("market %d has id %s, want %s", count, market.ID, want)
+		}
+		count++
+	}
+
+	if iterErr == nil {
+		t.Fatal("expected an error from the failing page")
+	}
+	if count != 10 {
+		t.Fatalf("got %d markets before the error, want 10", count)
+	}
+}
@@ -0,0 +1,91 @@
+package gamma
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// MarketOutcome pairs an outcome with its CLOB token and current price
+type MarketOutcome struct {
+	Outcome string  // Outcome label, e.g. "Yes"
+	TokenID string  // CLOB token ID used for orders and book subscriptions
+	Price   float64 // Last indicative price, 0 when Gamma sends no prices
+}
+
+// OutcomeTokens pairs each outcome with its CLOB token ID and price, in the
+// order Gamma lists them. It fails when the lists do not line up.
+func (m *Market) OutcomeTokens() ([]MarketOutcome, error) {
+	if len(m.ClobTokenIDs) != len(m.Outcomes) {
+		return nil, fmt.Errorf("market %s has %d outcomes but %d token IDs", m.ID, len(m.Outcomes), len(m.ClobTokenIDs))
+	}
+	if len(m.OutcomePrices) != 0 && len(m.OutcomePrices) != len(m.Outcomes) {
+		return nil, fmt.Errorf("market %s has %d outcomes but %d prices", m.ID, len(m.Outcomes), len(m.OutcomePrices))
+	}
+
+	outcomes := make([]MarketOutcome, len(m.Outcomes))
+	for i, outcome := range m.Outcomes {
+		outcomes[i] = MarketOutcome{Outcome: outcome, TokenID: m.ClobTokenIDs[i]}
+		if len(m.OutcomePrices) == 0 {
+			continue
+		}
+
+		price, err := strconv.ParseFloat(m.OutcomePrices[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("market %s has invalid price %q for outcome %q: %w", m.ID, m.OutcomePrices[i], outcome, err)
+		}
+		outcomes[i].Price = price
+	}
+
+	return outcomes, nil
+}
+
+// Outcome returns the outcome whose label matches name, ignoring case
+func (m *Market) Outcome(name string) (MarketOutcome, error) {
+	outcomes, err := m.OutcomeTokens()
+	if err != nil {
+		return MarketOutcome{}, err
+	}
+
+	for _, outcome := range outcomes {
+		if strings.EqualFold(outcome.Outcome, name) {
+			return outcome, nil
+		}
+	}
+	return MarketOutcome{}, fmt.Errorf("market %s has no outcome %q", m.ID, name)
+}
+
+// CreateOrderOptions returns the tick size and neg-risk flag the CLOB needs to
+// build orders for this market, without querying the CLOB. It fails when the
+// market has no order book or an unsupported tick size.
+func (m *Market) CreateOrderOptions() (types.CreateOrderOptions, error) {
+	if m.EnableOrderBook != nil && !*m.EnableOrderBook {
+		return types.CreateOrderOptions{}, fmt.Errorf("market %s has no order book", m.ID)
+	}
+	if m.OrderPriceMinTickSize == nil {
+		return types.CreateOrderOptions{}, fmt.Errorf("market %s has no tick size", m.ID)
+	}
+
+	tickSize, err := tickSizeFromFloat(m.OrderPriceMinTickSize.Float64())
+	if err != nil {
+		return types.CreateOrderOptions{}, fmt.Errorf("market %s: %w", m.ID, err)
+	}
+
+	negRisk := m.NegRisk != nil && *m.NegRisk
+	return types.CreateOrderOptions{
+		TickSize: tickSize,
+		NegRisk:  &negRisk,
+	}, nil
+}
+
+// tickSizeFromFloat maps a numeric tick size onto the CLOB's TickSize values
+func tickSizeFromFloat(v float64) (types.TickSize, error) {
+	switch tickSize := types.TickSize(strconv.FormatFloat(v, 'f', -1, 64)); tickSize {
+	case types.TickSize01, types.TickSize001, types.TickSize0001, types.TickSize00001:
+		return tickSize, nil
+	default:
+		return "", fmt.Errorf("unsupported tick size %v", v)
+	}
+}
@@ -0,0 +1,160 @@
+package gamma
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+const sampleMarketJSON = `{
+	"id": "516710",
+	"question": "Will the Fed cut rates in March?",
+	"conditionId": "0xabc",
+	"outcomes": "[\"Yes\", \"No\"]",
+	"outcomePrices": "[\"0.215\", \"0.785\"]",
+	"clobTokenIds": "[\"1111\", \"2222\"]",
+	"volume": "1234567.89",
+	"enableOrderBook": true,
+	"acceptingOrders": true,
+	"negRisk": true,
+	"negRiskMarketID": "0xneg",
+	"orderPriceMinTickSize": 0.001,
+	"orderMinSize": 5,
+	"bestBid": 0.21,
+	"bestAsk": 0.22,
+	"spread": 0.01,
+	"lastTradePrice": 0.215,
+	"umaResolutionStatus": "proposed",
+	"events": [{"id": "903", "slug": "fed-march", "title": "Fed decision in March"}]
+}`
+
+func TestMarketDecodesClobFields(t *testing.T) {
+	var m Market
+	if err := json.Unmarshal([]byte(sampleMarketJSON), &m); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if m.NegRisk == nil || !*m.NegRisk || m.EnableOrderBook == nil || !*m.EnableOrderBook {
+		t.Errorf("flags not decoded: negRisk %v, enableOrderBook %v", m.NegRisk, m.EnableOrderBook)
+	}
+	if *m.OrderPriceMinTickSize != 0.001 || *m.OrderMinSize != 5 || *m.BestBid != 0.21 || *m.BestAsk != 0.22 || *m.Spread != 0.01 {
+		t.Errorf("numbers not decoded: %+v", m)
+	}
+	if m.UmaResolutionStatus == nil || *m.UmaResolutionStatus != "proposed" {
+		t.Errorf("umaResolutionStatus = %v", m.UmaResolutionStatus)
+	}
+	if len(m.Events) != 1 || m.Events[0].Slug != "fed-march" {
+		t.Errorf("events = %+v", m.Events)
+	}
+}
+
+func TestMarketOutcomeTokens(t *testing.T) {
+	var m Market
+	if err := json.Unmarshal([]byte(sampleMarketJSON), &m); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	got, err := m.OutcomeTokens()
+	if err != nil {
+		t.Fatalf("OutcomeTokens() error = %v", err)
+	}
+	want := []MarketOutcome{
+		{Outcome: "Yes", TokenID: "1111", Price: 0.215},
+		{Outcome: "No", TokenID: "2222", Price: 0.785},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OutcomeTokens() = %+v, want %+v", got, want)
+	}
+
+	no, err := m.Outcome("no")
+	if err != nil || no.TokenID != "2222" {
+		t.Errorf("Outcome(no) = %+v, %v", no, err)
+	}
+	if _, err := m.Outcome("Maybe"); err == nil {
+		t.Error("Outcome(Maybe) should fail")
+	}
+}
+
+func TestMarketOutcomeTokensErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		market Market
+	}{
+		{name: "token count mismatch", market: Market{Outcomes: StringArray{"Yes", "No"}, ClobTokenIDs: StringArray{"1"}}},
+		{name: "price count mismatch", market: Market{Outcomes: StringArray{"Yes", "No"}, ClobTokenIDs: StringArray{"1", "2"}, OutcomePrices: StringArray{"0.5"}}},
+		{name: "bad price", market: Market{Outcomes: StringArray{"Yes"}, ClobTokenIDs: StringArray{"1"}, OutcomePrices: StringArray{"x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.market.OutcomeTokens(); err == nil {
+				t.Fatal("OutcomeTokens() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestMarketCreateOrderOptions(t *testing.T) {
+	tick := func(v StringFloat) *StringFloat { return &v }
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		market   Market
+		wantTick types.TickSize
+		wantNeg  bool
+		wantErr  bool
+	}{
+		{name: "neg risk", market: Market{OrderPriceMinTickSize: tick(0.001), NegRisk: &yes, EnableOrderBook: &yes}, wantTick: types.TickSize0001, wantNeg: true},
+		{name: "default neg risk", market: Market{OrderPriceMinTickSize: tick(0.01)}, wantTick: types.TickSize001},
+		{name: "coarse tick", market: Market{OrderPriceMinTickSize: tick(0.1)}, wantTick: types.TickSize01},
+		{name: "fine tick", market: Market{OrderPriceMinTickSize: tick(0.0001)}, wantTick: types.TickSize00001},
+		{name: "missing tick", market: Market{}, wantErr: true},
+		{name: "unsupported tick", market: Market{OrderPriceMinTickSize: tick(0.05)}, wantErr: true},
+		{name: "no order book", market: Market{OrderPriceMinTickSize: tick(0.01), EnableOrderBook: &no}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.market.CreateOrderOptions()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateOrderOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.TickSize != tt.wantTick || got.NegRisk == nil || *got.NegRisk != tt.wantNeg {
+				t.Errorf("CreateOrderOptions() = {%s %v}, want {%s %v}", got.TickSize, *got.NegRisk, tt.wantTick, tt.wantNeg)
+			}
+		})
+	}
+}
+
+func TestGetMarketsBySlugs(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		fmt.Fprint(w, `[{"id": "1", "slug": "a"}, {"id": "2", "slug": "b"}]`)
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	markets, err := sdk.GetMarketsBySlugs([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("GetMarketsBySlugs() error = %v", err)
+	}
+	if len(markets) != 2 {
+		t.Errorf("got %d markets, want 2", len(markets))
+	}
+	if !reflect.DeepEqual(got["slug"], []string{"a", "b", "c"}) || got.Get("limit") != "3" {
+		t.Errorf("unexpected query %v", got)
+	}
+}
@@ -0,0 +1,296 @@
+package gamma
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildURLQueries(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+	flag := func(b bool) *bool { return &b }
+	float := func(f float64) *float64 { return &f }
+	at := func(s string) *time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		query    interface{}
+		want     string
+	}{
+		{
+			name:     "nil query",
+			endpoint: "/events",
+			query:    (*UpdatedEventQuery)(nil),
+			want:     "/events",
+		},
+		{
+			name:     "TeamQuery",
+			endpoint: "/teams",
+			query: &TeamQuery{
+				Limit:     num(10),
+				Offset:    num(20),
+				Order:     str("name"),
+				Ascending: flag(true),
+				League:    str("nba"),
+			},
+			want: "/teams?ascending=true&league=nba&limit=10&offset=20&order=name",
+		},
+		{
+			name:     "TagQuery",
+			endpoint: "/tags",
+			query: TagQuery{
+				Limit:      num(5),
+				Offset:     num(0),
+				Order:      str("label"),
+				Ascending:  flag(false),
+				Search:     str("crypto"),
+				IsCarousel: flag(true),
+			},
+			want: "/tags?ascending=false&is_carousel=true&limit=5&offset=0&order=label&search=crypto",
+		},
+		{
+			name:     "TagByIdQuery",
+			endpoint: "/tags/1",
+			query:    &TagByIdQuery{IncludeTemplate: flag(true)},
+			want:     "/tags/1?include_template=true",
+		},
+		{
+			name:     "RelatedTagsQuery",
+			endpoint: "/tags/1/related-tags",
+			query: &RelatedTagsQuery{
+				Limit:     num(3),
+				Offset:    num(6),
+				Order:     str("rank"),
+				Ascending: flag(true),
+			},
+			want: "/tags/1/related-tags?ascending=true&limit=3&offset=6&order=rank",
+		},
+		{
+			name:     "UpdatedEventQuery",
+			endpoint: "/events",
+			query: &UpdatedEventQuery{
+				Limit:        num(100),
+				Offset:       num(200),
+				Order:        str("volume"),
+				Ascending:    flag(false),
+				Search:       str("fed rates"),
+				Active:       flag(true),
+				Closed:       flag(false),
+				Archived:     flag(false),
+				Featured:     flag(true),
+				New:          flag(false),
+				Restricted:   flag(false),
+				MinVolume:    float(1000),
+				MaxVolume:    float(2500.5),
+				MinLiquidity: float(0.5),
+				MaxLiquidity: float(1e6),
+				Series:       str("nba-2025"),
+				Tag:          str("politics"),
+				TagID:        num(745),
+				StartDate:    str("2025-01-01"),
+				EndDate:      str("2025-12-31"),
+			},
+			want: "/events?active=true&archived=false&ascending=false&closed=false&endDate=2025-12-31&featured=true&limit=100&maxLiquidity=1000000&maxVolume=2500.5&minLiquidity=0.5&minVolume=1000&new=false&offset=200&order=volume&restricted=false&search=fed+rates&series=nba-2025&startDate=2025-01-01&tag=politics&tag_id=745",
+		},
+		{
+			name:     "UpdatedEventQuery multi-valued filters",
+			endpoint: "/events",
+			query: &UpdatedEventQuery{
+				ID:           []int{16167, 16168},
+				Slug:         []string{"fed-march", "fed-may"},
+				TagID:        num(2),
+				RelatedTags:  flag(true),
+				ExcludeTagID: []int{100639, 1},
+				StartDateMin: at("2025-01-01T00:00:00Z"),
+				StartDateMax: at("2025-02-01T09:30:00+02:00"),
+				EndDateMin:   at("2025-03-01T00:00:00Z"),
+				EndDateMax:   at("2025-04-01T00:00:00Z"),
+			},
+			want: "/events?end_date_max=2025-04-01T00%3A00%3A00Z&end_date_min=2025-03-01T00%3A00%3A00Z&exclude_tag_id=100639&exclude_tag_id=1&id=16167&id=16168&related_tags=true&slug=fed-march&slug=fed-may&start_date_max=2025-02-01T07%3A30%3A00Z&start_date_min=2025-01-01T00%3A00%3A00Z&tag_id=2",
+		},
+		{
+			name:     "PaginatedEventQuery",
+			endpoint: "/events/pagination",
+			query: PaginatedEventQuery{
+				Limit:      num(20),
+				Offset:     num(40),
+				Order:      str("startDate"),
+				Ascending:  flag(true),
+				Search:     str("cup"),
+				Active:     flag(true),
+				Closed:     flag(true),
+				Archived:   flag(true),
+				Featured:   flag(false),
+				New:        flag(true),
+				Restricted: flag(true),
+				Series:     str("epl"),
+				Tag:        str("sports"),
+				StartDate:  str("2025-02-01"),
+				EndDate:    str("2025-03-01"),
+			},
+			want: "/events/pagination?active=true&archived=true&ascending=true&closed=true&endDate=2025-03-01&featured=false&limit=20&new=true&offset=40&order=startDate&restricted=true&search=cup&series=epl&startDate=2025-02-01&tag=sports",
+		},
+		{
+			name:     "EventByIdQuery",
+			endpoint: "/events/1",
+			query:    &EventByIdQuery{IncludeChat: flag(true)},
+			want:     "/events/1?include_chat=true",
+		},
+		{
+			name:     "UpdatedMarketQuery",
+			endpoint: "/markets",
+			query: &UpdatedMarketQuery{
+				Limit:        num(50),
+				Offset:       num(0),
+				Order:        str("liquidity"),
+				Ascending:    flag(false),
+				Search:       str("btc"),
+				Active:       flag(true),
+				Closed:       flag(false),
+				New:          flag(true),
+				Event:        str("123"),
+				EventSlug:    str("btc-price"),
+				Series:       str("9"),
+				SeriesSlug:   str("btc-daily"),
+				Tag:          str("crypto"),
+				MinVolume:    float(10),
+				MaxVolume:    float(20),
+				MinLiquidity: float(30),
+				MaxLiquidity: float(40),
+				StartDate:    str("2025-04-01"),
+				EndDate:      str("2025-04-02"),
+				QuestionID:   str("0xq"),
+				ConditionID:  str("0xc"),
+			},
+			want: "/markets?active=true&ascending=false&closed=false&conditionId=0xc&endDate=2025-04-02&event=123&eventSlug=btc-price&limit=50&maxLiquidity=40&maxVolume=20&minLiquidity=30&minVolume=10&new=true&offset=0&order=liquidity&questionId=0xq&search=btc&series=9&seriesSlug=btc-daily&startDate=2025-04-01&tag=crypto",
+		},
+		{
+			name:     "UpdatedMarketQuery multi-valued filters",
+			endpoint: "/markets",
+			query: &UpdatedMarketQuery{
+				ID:           []int{1, 2},
+				Slug:         []string{"a", "b"},
+				ClobTokenIDs: []string{"111", "222"},
+				ConditionIDs: []string{"0xc1", "0xc2"},
+				QuestionIDs:  []string{"0xq1"},
+				TagID:        num(21),
+				RelatedTags:  flag(false),
+				StartDateMin: at("2025-05-01T00:00:00Z"),
+				StartDateMax: at("2025-05-02T00:00:00Z"),
+				EndDateMin:   at("2025-06-01T00:00:00Z"),
+				EndDateMax:   at("2025-06-02T00:00:00Z"),
+			},
+			want: "/markets?clob_token_ids=111&clob_token_ids=222&condition_ids=0xc1&condition_ids=0xc2&end_date_max=2025-06-02T00%3A00%3A00Z&end_date_min=2025-06-01T00%3A00%3A00Z&id=1&id=2&question_ids=0xq1&related_tags=false&slug=a&slug=b&start_date_max=2025-05-02T00%3A00%3A00Z&start_date_min=2025-05-01T00%3A00%3A00Z&tag_id=21",
+		},
+		{
+			name:     "MarketByIdQuery",
+			endpoint: "/markets/1",
+			query:    &MarketByIdQuery{IncludeTag: flag(false)},
+			want:     "/markets/1?include_tag=false",
+		},
+		{
+			name:     "SeriesQuery",
+			endpoint: "/series",
+			query: SeriesQuery{
+				Limit:     num(10),
+				Offset:    num(10),
+				Order:     str("title"),
+				Ascending: flag(true),
+				Search:    str("nfl"),
+				Active:    flag(true),
+				Closed:    flag(false),
+				Archived:  flag(false),
+				MinVolume: float(1),
+				MaxVolume: float(2),
+				StartDate: str("2025-09-01"),
+				EndDate:   str("2026-02-01"),
+			},
+			want: "/series?active=true&archived=false&ascending=true&closed=false&endDate=2026-02-01&limit=10&maxVolume=2&minVolume=1&offset=10&order=title&search=nfl&startDate=2025-09-01",
+		},
+		{
+			name:     "SeriesByIdQuery",
+			endpoint: "/series/1",
+			query:    &SeriesByIdQuery{IncludeChat: flag(false)},
+			want:     "/series/1?include_chat=false",
+		},
+		{
+			name:     "CommentQuery",
+			endpoint: "/comments",
+			query: CommentQuery{
+				Limit:            num(25),
+				Offset:           num(50),
+				Order:            str("createdAt"),
+				Ascending:        flag(false),
+				ParentEntityType: str("Event"),
+				ParentEntityID:   num(903),
+			},
+			want: "/comments?ascending=false&limit=25&offset=50&order=createdAt&parent_entity_id=903&parent_entity_type=Event",
+		},
+		{
+			name:     "CommentByIdQuery",
+			endpoint: "/comments/1",
+			query: &CommentByIdQuery{
+				Limit:     num(1),
+				Offset:    num(2),
+				Order:     str("id"),
+				Ascending: flag(true),
+			},
+			want: "/comments/1?ascending=true&limit=1&offset=2&order=id",
+		},
+		{
+			name:     "CommentsByUserQuery",
+			endpoint: "/comments/user_address/0xabc",
+			query: &CommentsByUserQuery{
+				Limit:     num(3),
+				Offset:    num(4),
+				Order:     str("createdAt"),
+				Ascending: flag(false),
+			},
+			want: "/comments/user_address/0xabc?ascending=false&limit=3&offset=4&order=createdAt",
+		},
+		{
+			name:     "SearchQuery",
+			endpoint: "/public-search",
+			query: SearchQuery{
+				Q:              str("trump & co"),
+				LimitPerType:   num(5),
+				EventsStatus:   str("active"),
+				EventsActive:   flag(true),
+				EventsClosed:   flag(false),
+				EventsArchived: flag(false),
+				EventsFeatured: flag(true),
+				MarketsActive:  flag(true),
+				MarketsClosed:  flag(false),
+				TagsCarousel:   flag(true),
+				SeriesActive:   flag(true),
+				SeriesClosed:   flag(false),
+				SearchTags:     flag(true),
+				SearchProfiles: flag(false),
+				Sort:           str("volume"),
+				Ascending:      flag(false),
+				Page:           num(2),
+			},
+			want: "/public-search?ascending=false&events_active=true&events_archived=false&events_closed=false&events_featured=true&events_status=active&limit_per_type=5&markets_active=true&markets_closed=false&page=2&q=trump+%26+co&search_profiles=false&search_tags=true&series_active=true&series_closed=false&sort=volume&tags_carousel=true",
+		},
+	}
+
+	sdk := NewGammaSDK(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sdk.buildURL(tt.endpoint, tt.query)
+			if err != nil {
+				t.Fatalf("buildURL() error = %v", err)
+			}
+			if want := GammaAPIBase + tt.want; got != want {
+				t.Errorf("buildURL() = %q, want %q", got, want)
+			}
+		})
+	}
+}
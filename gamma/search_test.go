@@ -0,0 +1,88 @@
+package gamma
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchDecodesTypedResults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"events": [{
+				"id": "903",
+				"slug": "fed-decision",
+				"title": "Fed decision",
+				"markets": [{
+					"id": "1",
+					"question": "Cut?",
+					"outcomes": "[\"Yes\", \"No\"]",
+					"outcomePrices": "[\"0.3\", \"0.7\"]",
+					"clobTokenIds": "[\"111\", \"222\"]"
+				}]
+			}],
+			"tags": [{"id": "2", "label": "Politics", "slug": "politics", "event_count": 412}],
+			"profiles": [{"id": "7", "name": "whale", "proxyWallet": "0xabc", "displayUsernamePublic": true}],
+			"pagination": {"hasMore": true, "totalResults": 40}
+		}`)
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	q := "fed"
+	result, err := sdk.Search(SearchQuery{Q: &q})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	if len(result.Events) != 1 || result.Events[0].Slug != "fed-decision" {
+		t.Fatalf("unexpected events: %+v", result.Events)
+	}
+	market := result.Events[0].Markets[0]
+	if len(market.Outcomes) != 2 || market.Outcomes[1] != "No" {
+		t.Errorf("outcomes not decoded from JSON string: %v", market.Outcomes)
+	}
+	if len(market.ClobTokenIDs) != 2 || market.ClobTokenIDs[0] != "111" {
+		t.Errorf("token IDs not decoded from JSON string: %v", market.ClobTokenIDs)
+	}
+
+	if len(result.Tags) != 1 || result.Tags[0].EventCount != 412 || result.Tags[0].Slug != "politics" {
+		t.Errorf("unexpected tags: %+v", result.Tags)
+	}
+	if len(result.Profiles) != 1 || result.Profiles[0].ProxyWallet == nil || *result.Profiles[0].ProxyWallet != "0xabc" {
+		t.Errorf("unexpected profiles: %+v", result.Profiles)
+	}
+	if result.Pagination == nil || !result.Pagination.HasMore || result.Pagination.TotalResults == nil || *result.Pagination.TotalResults != 40 {
+		t.Errorf("unexpected pagination: %+v", result.Pagination)
+	}
+}
+
+func TestIterSearchPages(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		hasMore := page != "3"
+		fmt.Fprintf(w, `{"tags": [{"id": "%s", "label": "t", "slug": "t", "event_count": 1}], "pagination": {"hasMore": %t}}`, page, hasMore)
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	var ids []string
+	for result, err := range sdk.IterSearch(context.Background(), SearchQuery{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, result.Tags[0].ID)
+	}
+
+	if fmt.Sprint(ids) != "[1 2 3]" || fmt.Sprint(pages) != "[1 2 3]" {
+		t.Fatalf("got tag ids %v from pages %v, want pages 1-3", ids, pages)
+	}
+}
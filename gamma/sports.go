@@ -0,0 +1,109 @@
+package gamma
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GetLeague gets the sports metadata for one league code, e.g. "nba"
+func (g *GammaSDK) GetLeague(league string) (*Sport, error) {
+	sports, err := g.GetSports()
+	if err != nil {
+		return nil, err
+	}
+	return findLeague(sports, league)
+}
+
+// findLeague looks up a league code, ignoring case
+func findLeague(sports []Sport, league string) (*Sport, error) {
+	for i := range sports {
+		if strings.EqualFold(sports[i].Sport, league) {
+			return &sports[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown league %q", league)
+}
+
+// leagueTagIDs returns the tags that identify sport's events. Tags shared
+// with other leagues, such as the generic Sports tag, are left out unless
+// the league has no tag of its own.
+func leagueTagIDs(sports []Sport, sport *Sport) []int {
+	var own []int
+	for _, tag := range sport.Tags {
+		shared := false
+		for i := range sports {
+			if sports[i].Sport != sport.Sport && slices.Contains(sports[i].Tags, tag) {
+				shared = true
+				break
+			}
+		}
+		if !shared {
+			own = append(own, tag)
+		}
+	}
+
+	if len(own) == 0 {
+		return sport.Tags
+	}
+	return own
+}
+
+// GetLiveGameEvents resolves a league code to its open game events: active,
+// not closed events carrying the league's tag. When the league has a games
+// series, futures such as championship winners are left out by keeping only
+// events in that series. All pages are fetched.
+func (g *GammaSDK) GetLiveGameEvents(ctx context.Context, league string, options *IterOptions) ([]Event, error) {
+	sports, err := g.getSports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sport, err := findLeague(sports, league)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := leagueTagIDs(sports, sport)
+	if len(tagIDs) == 0 {
+		return nil, fmt.Errorf("league %q has no tags to filter events by", league)
+	}
+
+	active, closed := true, false
+	seen := make(map[string]bool)
+	var events []Event
+	for _, tagID := range tagIDs {
+		query := &UpdatedEventQuery{
+			Active: &active,
+			Closed: &closed,
+			TagID:  &tagID,
+		}
+
+		for event, err := range g.IterEvents(ctx, query, options) {
+			if err != nil {
+				return events, err
+			}
+			if seen[event.ID] || !inSeries(event, sport.Series) {
+				continue
+			}
+			seen[event.ID] = true
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// inSeries reports whether event belongs to seriesID; an empty seriesID matches every event
+func inSeries(event Event, seriesID string) bool {
+	if seriesID == "" {
+		return true
+	}
+	for _, series := range event.Series {
+		if series.ID == seriesID {
+			return true
+		}
+	}
+	return false
+}
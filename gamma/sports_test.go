@@ -0,0 +1,108 @@
+package gamma
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+const sportsJSON = `[
+	{"id": 1, "sport": "nba", "image": "nba.png", "resolution": "https://www.nba.com/", "ordering": "home", "tags": "1,745,100639", "series": "10345"},
+	{"id": 2, "sport": "nfl", "image": "nfl.png", "resolution": "https://www.nfl.com/", "ordering": "away", "tags": "1,450,100639", "series": "10187"}
+]`
+
+func TestIDListUnmarshal(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    IDList
+		wantErr bool
+	}{
+		{input: `"1,745,100639"`, want: IDList{1, 745, 100639}},
+		{input: `" 1, 2 ,"`, want: IDList{1, 2}},
+		{input: `[1, "2"]`, want: IDList{1, 2}},
+		{input: `""`, want: IDList{}},
+		{input: `"1,x"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got IDList
+		err := json.Unmarshal([]byte(tt.input), &got)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestGetLeague(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, sportsJSON)
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	nfl, err := sdk.GetLeague("NFL")
+	if err != nil {
+		t.Fatalf("GetLeague() error = %v", err)
+	}
+	if nfl.Resolution != "https://www.nfl.com/" || !reflect.DeepEqual(nfl.Tags, IDList{1, 450, 100639}) || nfl.Series != "10187" {
+		t.Errorf("GetLeague() = %+v", nfl)
+	}
+
+	if _, err := sdk.GetLeague("curling"); err == nil {
+		t.Error("GetLeague(curling) should fail")
+	}
+}
+
+func TestGetLiveGameEvents(t *testing.T) {
+	var tagQueries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sports":
+			fmt.Fprint(w, sportsJSON)
+		case "/events":
+			q := r.URL.Query()
+			if offset, _ := strconv.Atoi(q.Get("offset")); offset > 0 {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			tagQueries = append(tagQueries, q.Get("tag_id"))
+			if q.Get("active") != "true" || q.Get("closed") != "false" {
+				http.Error(w, "expected open events", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `[
+				{"id": "1", "title": "Lakers vs Celtics", "series": [{"id": "10345"}]},
+				{"id": "2", "title": "NBA Champion 2026", "series": []},
+				{"id": "3", "title": "Knicks vs Heat", "series": [{"id": "10345"}]}
+			]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sdk := NewGammaSDK(nil)
+	sdk.baseURL = srv.URL
+
+	events, err := sdk.GetLiveGameEvents(context.Background(), "nba", nil)
+	if err != nil {
+		t.Fatalf("GetLiveGameEvents() error = %v", err)
+	}
+
+	if fmt.Sprint(tagQueries) != "[745]" {
+		t.Errorf("queried tags %v, want only the league tag 745", tagQueries)
+	}
+	if len(events) != 2 || events[0].ID != "1" || events[1].ID != "3" {
+		t.Errorf("GetLiveGameEvents() = %+v, want the two games", events)
+	}
+}
@@ -0,0 +1,53 @@
+// Package instrument sends the SDK clients' HTTP requests, reporting them to
+// an observe.Observer and logging them with credentials redacted.
+package instrument
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/internal/logging"
+	"github.com/ybina/polymarket-sdk-go/observe"
+)
+
+// Do sends req with hc on behalf of client ("clob", "gamma" or "data")
+func Do(client string, hc *http.Client, logger *slog.Logger, observer observe.Observer, req *http.Request) (*http.Response, error) {
+	info := observe.Request{
+		Client:   client,
+		Method:   req.Method,
+		Endpoint: observe.Endpoint(req.URL.Path),
+		Path:     req.URL.Path,
+	}
+	ctx := observer.RequestStart(req.Context(), info)
+	req = req.WithContext(ctx)
+
+	start := time.Now()
+	resp, err := logging.Do(logger, hc, req)
+	result := observe.Result{Elapsed: time.Since(start), Err: err}
+	if resp != nil {
+		result.Status = resp.StatusCode
+		if resp.StatusCode == http.StatusTooManyRequests {
+			observer.RateLimited(ctx, info, RetryAfter(resp.Header, time.Now()))
+		}
+	}
+	observer.RequestEnd(ctx, info, result)
+	return resp, err
+}
+
+// RetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date, returning 0 when it is absent or invalid
+func RetryAfter(h http.Header, now time.Time) time.Duration {
+	value := h.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
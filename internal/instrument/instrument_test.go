@@ -0,0 +1,92 @@
+package instrument
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/internal/logging"
+	"github.com/ybina/polymarket-sdk-go/observe"
+)
+
+// recorder records the requests and rate limits it observes
+type recorder struct {
+	observe.Nop
+	ended       []observe.Result
+	requests    []observe.Request
+	rateLimited []time.Duration
+}
+
+func (r *recorder) RequestEnd(_ context.Context, req observe.Request, result observe.Result) {
+	r.requests = append(r.requests, req)
+	r.ended = append(r.ended, result)
+}
+
+func (r *recorder) RateLimited(_ context.Context, _ observe.Request, retryAfter time.Duration) {
+	r.rateLimited = append(r.rateLimited, retryAfter)
+}
+
+func TestDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/markets/42" {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	rec := &recorder{}
+	for _, path := range []string{"/book", "/markets/42"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		resp, err := Do("gamma", srv.Client(), logging.Discard(), rec, req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	closed, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:1/book", nil)
+	if _, err := Do("gamma", srv.Client(), logging.Discard(), rec, closed); err == nil {
+		t.Fatal("Do() to a closed port succeeded")
+	}
+
+	if len(rec.ended) != 3 {
+		t.Fatalf("RequestEnd calls = %d, want 3", len(rec.ended))
+	}
+	if got := rec.requests[1]; got.Client != "gamma" || got.Endpoint != "/markets/:id" || got.Path != "/markets/42" {
+		t.Errorf("request = %+v, want gamma /markets/:id", got)
+	}
+	if rec.ended[0].Status != 200 || rec.ended[1].Status != 429 || rec.ended[2].Err == nil {
+		t.Errorf("results = %+v", rec.ended)
+	}
+	if len(rec.rateLimited) != 1 || rec.rateLimited[0] != 3*time.Second {
+		t.Errorf("rate limited = %v, want [3s]", rec.rateLimited)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "120", want: 2 * time.Minute},
+		{value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second},
+		{value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Retry-After", tt.value)
+		}
+		if got := RetryAfter(h, now); got != tt.want {
+			t.Errorf("RetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
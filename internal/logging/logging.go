@@ -0,0 +1,200 @@
+// Package logging writes the debug logs of the SDK's HTTP clients with
+// credentials redacted.
+//
+// Request and response bodies are logged only when the logger has debug
+// enabled. Authentication headers (POLY_SIGNATURE, POLY_PASSPHRASE and the
+// builder equivalents), proxy credentials and JSON fields holding secrets,
+// passphrases, signatures or private keys are replaced by Redacted.
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Redacted replaces sensitive values in logs
+const Redacted = "[REDACTED]"
+
+// maxBody is the number of body bytes logged
+const maxBody = 4096
+
+// sensitiveHeaders are the headers whose values are never logged, lower case
+var sensitiveHeaders = map[string]bool{
+	"poly_signature":          true,
+	"poly_passphrase":         true,
+	"poly_builder_signature":  true,
+	"poly_builder_passphrase": true,
+	"authorization":           true,
+	"proxy-authorization":     true,
+	"cookie":                  true,
+	"set-cookie":              true,
+}
+
+// sensitiveParams are the query parameters whose values are never logged
+var sensitiveParams = map[string]bool{
+	"geo_block_token": true,
+}
+
+// Discard returns a logger that drops everything, for clients without one
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// OrDiscard returns logger, or a discarding logger when it is nil
+func OrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return Discard()
+	}
+	return logger
+}
+
+// Do sends req with client and logs the request and its outcome at debug
+// level. Bodies are buffered for logging only when debug is enabled.
+func Do(logger *slog.Logger, client *http.Client, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return client.Do(req)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(req.URL)),
+		slog.Any("headers", RedactHeaders(req.Header)),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			attrs = append(attrs, slog.String("body", RedactBody(data)))
+		}
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, "http request", attrs...)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelDebug, "http request failed",
+			slog.String("method", req.Method),
+			slog.String("url", RedactURL(req.URL)),
+			slog.Duration("elapsed", elapsed),
+			slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	data, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{readErr}))
+	logger.LogAttrs(ctx, slog.LevelDebug, "http response",
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(req.URL)),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("elapsed", elapsed),
+		slog.Any("headers", RedactHeaders(resp.Header)),
+		slog.String("body", RedactBody(data)))
+	return resp, nil
+}
+
+// errReader returns err, or io.EOF when err is nil, so a failed read of the
+// original body still reaches the caller
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+// RedactHeaders returns a copy of h with sensitive values replaced
+func RedactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for key, values := range h {
+		if sensitiveHeaders[strings.ToLower(key)] {
+			out[key] = Redacted
+		} else {
+			out[key] = strings.Join(values, ", ")
+		}
+	}
+	return out
+}
+
+// RedactURL returns u as a string without credentials or sensitive query
+// parameters
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	redacted.User = nil
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		changed := false
+		for key := range query {
+			if sensitiveParams[strings.ToLower(key)] {
+				query.Set(key, Redacted)
+				changed = true
+			}
+		}
+		if changed {
+			redacted.RawQuery = query.Encode()
+		}
+	}
+	return redacted.String()
+}
+
+// RedactBody returns a JSON body with sensitive fields replaced, truncated
+// for logging. Bodies that are not JSON are only truncated.
+func RedactBody(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err == nil {
+		if redacted, err := json.Marshal(redactValue(v)); err == nil {
+			data = redacted
+		}
+	}
+	if len(data) > maxBody {
+		return string(data[:maxBody]) + "...(truncated)"
+	}
+	return string(data)
+}
+
+// redactValue replaces sensitive fields of decoded JSON at any depth
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if IsSensitiveKey(key) {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
+
+// IsSensitiveKey reports whether a field or header named key holds a secret
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	for _, s := range []string{"secret", "passphrase", "signature", "privatekey", "password", "mnemonic"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Package paging drives offset-paginated API endpoints as iterators.
+package paging
+
+import (
+	"context"
+	"iter"
+)
+
+// FetchFunc fetches up to limit items starting at offset
+type FetchFunc[T any] func(ctx context.Context, limit, offset int) ([]T, error)
+
+// page is the outcome of one fetch
+type page[T any] struct {
+	items []T
+	err   error
+}
+
+// request is a fetch in flight
+type request[T any] struct {
+	offset, limit int
+	result        chan page[T]
+	cancel        context.CancelFunc
+}
+
+// Iterate yields every item from offset start onwards, pageSize items per
+// request. Up to prefetch further pages are requested concurrently while the
+// current one is being consumed. Iteration ends at the first empty page.
+//
+// A page shorter than requested is either the last one or a sign that the
+// server caps the page size below pageSize, which cannot be told apart. The
+// next pages are then requested right after its items, at its size, so no
+// items are skipped either way.
+//
+// A fetch error is yielded once, as (zero, err), after all items from earlier
+// pages; iteration stops there. Stopping early cancels outstanding requests.
+func Iterate[T any](ctx context.Context, start, pageSize, prefetch int, fetch FetchFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		offset, limit := start, pageSize
+		var pending []*request[T]
+		launch := func() {
+			ctx, cancel := context.WithCancel(ctx)
+			r := &request[T]{offset: offset, limit: limit, result: make(chan page[T], 1), cancel: cancel}
+			go func() {
+				items, err := fetch(ctx, r.limit, r.offset)
+				r.result <- page[T]{items: items, err: err}
+			}()
+			pending = append(pending, r)
+			offset += limit
+		}
+		fill := func() {
+			for len(pending) <= prefetch {
+				launch()
+			}
+		}
+
+		fill()
+		var zero T
+		for len(pending) > 0 {
+			r := pending[0]
+			var p page[T]
+			select {
+			case p = <-r.result:
+			case <-ctx.Done():
+				yield(zero, ctx.Err())
+				return
+			}
+			pending = pending[1:]
+			r.cancel()
+
+			if p.err != nil {
+				yield(zero, p.err)
+				return
+			}
+			if len(p.items) == 0 {
+				return
+			}
+
+			if len(p.items) < r.limit {
+				// Requests in flight assumed a full page: restart them after
+				// the items received, at the size the server returned
+				for _, stale := range pending {
+					stale.cancel()
+				}
+				pending = nil
+				offset, limit = r.offset+len(p.items), len(p.items)
+			}
+			fill()
+
+			for _, item := range p.items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
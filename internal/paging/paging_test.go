@@ -0,0 +1,156 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// source serves the integers [0, total) one page at a time
+type source struct {
+	total   int
+	failAt  int // offset whose fetch fails, or -1
+	maxPage int // page size the source caps requests at, 0 for none
+	mu      sync.Mutex
+	offsets []int
+	active  atomic.Int32
+	peak    atomic.Int32
+}
+
+func (s *source) fetch(ctx context.Context, limit, offset int) ([]int, error) {
+	n := s.active.Add(1)
+	defer s.active.Add(-1)
+	for {
+		peak := s.peak.Load()
+		if n <= peak || s.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.offsets = append(s.offsets, offset)
+	s.mu.Unlock()
+
+	if offset == s.failAt {
+		return nil, errors.New("boom")
+	}
+	if s.maxPage > 0 {
+		limit = min(limit, s.maxPage)
+	}
+	var items []int
+	for i := offset; i < offset+limit && i < s.total; i++ {
+		items = append(items, i)
+	}
+	return items, ctx.Err()
+}
+
+func collect(seq func(func(int, error) bool)) ([]int, error) {
+	var items []int
+	for item, err := range seq {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func TestIterate(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		start    int
+		pageSize int
+		prefetch int
+		maxPage  int
+		want     int
+	}{
+		{name: "empty", total: 0, pageSize: 10, want: 0},
+		{name: "single short page", total: 7, pageSize: 10, want: 7},
+		{name: "exact multiple", total: 30, pageSize: 10, want: 30},
+		{name: "partial last page", total: 35, pageSize: 10, want: 35},
+		{name: "start offset", total: 35, start: 12, pageSize: 10, want: 23},
+		{name: "prefetch", total: 95, pageSize: 10, prefetch: 3, want: 95},
+		{name: "prefetch past end", total: 5, pageSize: 10, prefetch: 4, want: 5},
+		{name: "capped page size", total: 35, pageSize: 10, maxPage: 7, want: 35},
+		{name: "capped page size with prefetch", total: 95, pageSize: 10, prefetch: 3, maxPage: 7, want: 95},
+		{name: "capped page size from offset", total: 35, start: 12, pageSize: 10, prefetch: 1, maxPage: 4, want: 23},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &source{total: tt.total, failAt: -1, maxPage: tt.maxPage}
+			items, err := collect(Iterate(context.Background(), tt.start, tt.pageSize, tt.prefetch, src.fetch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != tt.want {
+				t.Fatalf("got %d items, want %d", len(items), tt.want)
+			}
+			for i, item := range items {
+				if item != tt.start+i {
+					t.Fatalf("item %d = %d, want %d (out of order)", i, item, tt.start+i)
+				}
+			}
+			if peak := int(src.peak.Load()); peak > tt.prefetch+1 {
+				t.Errorf("peak concurrency %d exceeds bound %d", peak, tt.prefetch+1)
+			}
+		})
+	}
+}
+
+func TestIterateErrorKeepsYieldedItems(t *testing.T) {
+	for _, prefetch := range []int{0, 2} {
+		src := &source{total: 100, failAt: 30}
+		items, err := collect(Iterate(context.Background(), 0, 10, prefetch, src.fetch))
+		if err == nil || err.Error() != "boom" {
+			t.Fatalf("prefetch %d: err = %v, want boom", prefetch, err)
+		}
+		if len(items) != 30 {
+			t.Fatalf("prefetch %d: got %d items before error, want 30", prefetch, len(items))
+		}
+	}
+}
+
+func TestIterateErrorYieldedOnce(t *testing.T) {
+	src := &source{total: 100, failAt: 0}
+	errs := 0
+	for _, err := range Iterate(context.Background(), 0, 10, 0, src.fetch) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Fatalf("got %d errors, want 1", errs)
+	}
+}
+
+func TestIterateEarlyBreak(t *testing.T) {
+	src := &source{total: 1000, failAt: -1}
+	count := 0
+	for range Iterate(context.Background(), 0, 10, 0, src.fetch) {
+		count++
+		if count == 15 {
+			break
+		}
+	}
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if len(src.offsets) != 2 {
+		t.Fatalf("fetched %d pages, want 2", len(src.offsets))
+	}
+}
+
+func TestIterateCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	src := &source{total: 1000, failAt: -1}
+	_, err := collect(Iterate(ctx, 0, 10, 1, src.fetch))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package query encodes SDK query structs into URL query strings.
+//
+// Field names come from the `url` struct tag, falling back to the `json` tag.
+// Supported tag options:
+//
+//	omitempty  skip the field when it holds its zero value
+//	comma      encode slices as a single comma-joined value (a,b,c)
+//	repeat     encode slices as one value per element (x=a&x=b); the default
+//	unix       encode time.Time as Unix seconds instead of RFC 3339
+//
+// Nil pointers, at any depth, are always skipped. A tag of "-" skips the field.
+package query
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// fieldOptions holds the parsed tag of a single struct field
+type fieldOptions struct {
+	name      string
+	omitEmpty bool
+	comma     bool
+	unix      bool
+}
+
+// Encode converts a struct, or a pointer to one, into url.Values.
+// A nil pointer yields empty values.
+func Encode(v interface{}) (url.Values, error) {
+	values := url.Values{}
+	if v == nil {
+		return values, nil
+	}
+
+	rv, ok := deref(reflect.ValueOf(v))
+	if !ok {
+		return values, nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query: expected struct, got %s", rv.Kind())
+	}
+
+	if err := encodeStruct(values, rv); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Apply encodes v and sets it as the raw query of u
+func Apply(u *url.URL, v interface{}) error {
+	values, err := Encode(v)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = values.Encode()
+	return nil
+}
+
+func encodeStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		// Flatten embedded structs without a tag of their own
+		if field.Anonymous && field.Tag.Get("url") == "" && field.Tag.Get("json") == "" {
+			if inner, ok := deref(fv); ok && inner.Kind() == reflect.Struct {
+				if err := encodeStruct(values, inner); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		opts, ok := parseTag(field)
+		if !ok {
+			continue
+		}
+
+		if opts.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		fv, ok = deref(fv)
+		if !ok {
+			continue
+		}
+
+		if err := encodeField(values, opts, fv); err != nil {
+			return fmt.Errorf("query: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func encodeField(values url.Values, opts fieldOptions, fv reflect.Value) error {
+	if (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && fv.Type().Elem().Kind() != reflect.Uint8 {
+		items := make([]string, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			item, ok := deref(fv.Index(i))
+			if !ok {
+				continue
+			}
+			s, err := formatScalar(item, opts)
+			if err != nil {
+				return err
+			}
+			items = append(items, s)
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+		if opts.comma {
+			values.Add(opts.name, strings.Join(items, ","))
+			return nil
+		}
+		for _, item := range items {
+			values.Add(opts.name, item)
+		}
+		return nil
+	}
+
+	s, err := formatScalar(fv, opts)
+	if err != nil {
+		return err
+	}
+	values.Add(opts.name, s)
+	return nil
+}
+
+func formatScalar(v reflect.Value, opts fieldOptions) (string, error) {
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if opts.unix {
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		return t.UTC().Format(time.RFC3339), nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return string(text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported kind %s", v.Kind())
+	}
+}
+
+// deref follows pointers and interfaces, reporting false when a nil is reached
+func deref(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
+
+func parseTag(field reflect.StructField) (fieldOptions, bool) {
+	tag, ok := field.Tag.Lookup("url")
+	if !ok {
+		tag, ok = field.Tag.Lookup("json")
+	}
+	if !ok || tag == "-" {
+		return fieldOptions{}, false
+	}
+
+	parts := strings.Split(tag, ",")
+	opts := fieldOptions{name: parts[0]}
+	if opts.name == "" {
+		return fieldOptions{}, false
+	}
+
+	for _, part := range parts[1:] {
+		switch part {
+		case "omitempty":
+			opts.omitEmpty = true
+		case "comma":
+			opts.comma = true
+		case "repeat":
+			opts.comma = false
+		case "unix":
+			opts.unix = true
+		}
+	}
+	return opts, true
+}
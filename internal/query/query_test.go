@@ -0,0 +1,209 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+type level string
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte("level-" + string(l)), nil
+}
+
+type Embedded struct {
+	Page *int `json:"page,omitempty"`
+}
+
+func TestEncode(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+	ids := []string{"a", "b"}
+	idsPtr := &ids
+	when := time.Date(2025, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+2", 2*3600))
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			name:  "nil input",
+			input: nil,
+			want:  "",
+		},
+		{
+			name:  "nil struct pointer",
+			input: (*Embedded)(nil),
+			want:  "",
+		},
+		{
+			name: "nil pointers skipped",
+			input: struct {
+				A *string `json:"a,omitempty"`
+				B *int    `json:"b"`
+			}{},
+			want: "",
+		},
+		{
+			name: "scalars",
+			input: struct {
+				S  *string  `json:"s,omitempty"`
+				I  *int     `json:"i,omitempty"`
+				B  *bool    `json:"b,omitempty"`
+				F  *float64 `json:"f,omitempty"`
+				U  uint     `json:"u"`
+				NI int      `json:"ni"`
+			}{S: str("x y"), I: num(0), B: new(bool), F: func() *float64 { f := 0.25; return &f }(), U: 7},
+			want: "b=false&f=0.25&i=0&ni=0&s=x+y&u=7",
+		},
+		{
+			name: "omitempty on values",
+			input: struct {
+				A int    `json:"a,omitempty"`
+				B string `json:"b,omitempty"`
+			}{},
+			want: "",
+		},
+		{
+			name: "slice repeat by default",
+			input: struct {
+				IDs []string `json:"id"`
+			}{IDs: ids},
+			want: "id=a&id=b",
+		},
+		{
+			name: "pointer to slice comma",
+			input: struct {
+				IDs *[]string `json:"id,omitempty" url:"id,omitempty,comma"`
+			}{IDs: idsPtr},
+			want: "id=a%2Cb",
+		},
+		{
+			name: "pointer to slice repeat",
+			input: struct {
+				IDs *[]int `url:"id,repeat"`
+			}{IDs: &[]int{1, 2}},
+			want: "id=1&id=2",
+		},
+		{
+			name: "slice of pointers skips nil",
+			input: struct {
+				IDs []*string `url:"id,comma"`
+			}{IDs: []*string{str("a"), nil, str("c")}},
+			want: "id=a%2Cc",
+		},
+		{
+			name: "empty slice",
+			input: struct {
+				IDs []string `url:"id,comma"`
+			}{IDs: []string{}},
+			want: "",
+		},
+		{
+			name: "nested pointers",
+			input: struct {
+				S **string `json:"s"`
+			}{S: func() **string { s := str("deep"); return &s }()},
+			want: "s=deep",
+		},
+		{
+			name: "time RFC3339 in UTC",
+			input: struct {
+				T *time.Time `json:"t,omitempty"`
+			}{T: &when},
+			want: "t=2025-01-02T01%3A04%3A05Z",
+		},
+		{
+			name: "time unix",
+			input: struct {
+				T time.Time `url:"t,unix"`
+			}{T: when},
+			want: "t=1735779845",
+		},
+		{
+			name: "zero time omitted",
+			input: struct {
+				T time.Time `url:"t,omitempty"`
+			}{},
+			want: "",
+		},
+		{
+			name: "text marshaler",
+			input: struct {
+				L  level   `url:"l"`
+				Ls []level `url:"ls,comma"`
+			}{L: "a", Ls: []level{"b", "c"}},
+			want: "l=level-a&ls=level-b%2Clevel-c",
+		},
+		{
+			name: "url tag overrides json tag",
+			input: struct {
+				A string `json:"a" url:"alpha"`
+			}{A: "1"},
+			want: "alpha=1",
+		},
+		{
+			name: "skipped fields",
+			input: struct {
+				A string `json:"-"`
+				B string
+			}{A: "1", B: "2"},
+			want: "",
+		},
+		{
+			name: "embedded struct flattened",
+			input: struct {
+				Embedded
+				Q string `json:"q"`
+			}{Embedded: Embedded{Page: num(3)}, Q: "x"},
+			want: "page=3&q=x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Encode(tt.input)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if got := values.Encode(); got != tt.want {
+				t.Errorf("Encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{name: "not a struct", input: 42},
+		{name: "unsupported field", input: struct {
+			M map[string]string `json:"m"`
+		}{M: map[string]string{"a": "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Encode(tt.input); err == nil {
+				t.Fatal("Encode() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	u, _ := url.Parse("https://example.com/path?stale=1")
+	err := Apply(u, struct {
+		A string `json:"a"`
+	}{A: "b"})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got, want := u.String(), "https://example.com/path?a=b"; got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+// Package observe reports the SDK's HTTP and WebSocket traffic to metrics
+// and tracing systems.
+//
+// Every client accepts an Observer. It is called when a request starts and
+// ends, when the API answers 429 Too Many Requests, when the WebSocket client
+// schedules a reconnect and for every WebSocket message received. The
+// promobserve and otelobserve modules adapt the events to the Prometheus
+// client library and to OpenTelemetry tracing; they are separate modules so
+// the SDK itself does not depend on either.
+package observe
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Request identifies one HTTP call of an SDK client
+type Request struct {
+	Client   string // "clob", "gamma" or "data"
+	Method   string // HTTP method
+	Endpoint string // URL path with IDs replaced by ":id", see Endpoint
+	Path     string // URL path as sent
+}
+
+// Result is the outcome of a request
+type Result struct {
+	Status  int           // HTTP status, 0 when no response was received
+	Elapsed time.Duration // time until the response headers arrived
+	Err     error         // transport error, nil for any HTTP response
+}
+
+// Observer receives the SDK's traffic events. Implementations must be safe
+// for concurrent use and should return quickly. Embed Nop to implement only
+// some of the methods.
+type Observer interface {
+	// RequestStart is called before a request is sent. The returned context
+	// is used for the request and passed to RequestEnd, e.g. to carry a span.
+	RequestStart(ctx context.Context, req Request) context.Context
+
+	// RequestEnd is called once the response headers arrived or the request
+	// failed
+	RequestEnd(ctx context.Context, req Request, result Result)
+
+	// RateLimited is called when the API answered 429 Too Many Requests.
+	// retryAfter is the wait the server asked for, 0 when it named none.
+	RateLimited(ctx context.Context, req Request, retryAfter time.Duration)
+
+	// Reconnect is called when the WebSocket client of channel schedules
+	// reconnect attempt after delay
+	Reconnect(channel string, attempt int, delay time.Duration)
+
+	// MessageReceived is called for every WebSocket message received on
+	// channel, e.g. eventType "book" or "price_change"
+	MessageReceived(channel, eventType string)
+}
+
+// Nop is an Observer that ignores every event
+type Nop struct{}
+
+func (Nop) RequestStart(ctx context.Context, _ Request) context.Context { return ctx }
+func (Nop) RequestEnd(context.Context, Request, Result)                 {}
+func (Nop) RateLimited(context.Context, Request, time.Duration)         {}
+func (Nop) Reconnect(string, int, time.Duration)                        {}
+func (Nop) MessageReceived(string, string)                              {}
+
+// OrNop returns o, or Nop when o is nil
+func OrNop(o Observer) Observer {
+	if o == nil {
+		return Nop{}
+	}
+	return o
+}
+
+// Multi returns an Observer that reports every event to each of observers in
+// turn, e.g. to both a metrics collector and a tracer
+func Multi(observers ...Observer) Observer {
+	return multi(observers)
+}
+
+type multi []Observer
+
+func (m multi) RequestStart(ctx context.Context, req Request) context.Context {
+	for _, o := range m {
+		ctx = o.RequestStart(ctx, req)
+	}
+	return ctx
+}
+
+func (m multi) RequestEnd(ctx context.Context, req Request, result Result) {
+	for _, o := range m {
+		o.RequestEnd(ctx, req, result)
+	}
+}
+
+func (m multi) RateLimited(ctx context.Context, req Request, retryAfter time.Duration) {
+	for _, o := range m {
+		o.RateLimited(ctx, req, retryAfter)
+	}
+}
+
+func (m multi) Reconnect(channel string, attempt int, delay time.Duration) {
+	for _, o := range m {
+		o.Reconnect(channel, attempt, delay)
+	}
+}
+
+func (m multi) MessageReceived(channel, eventType string) {
+	for _, o := range m {
+		o.MessageReceived(channel, eventType)
+	}
+}
+
+// Endpoint returns path with its ID segments replaced by ":id", so that it
+// can label metrics without unbounded cardinality: "/markets/12345" and
+// "/markets/0xabc..." both become "/markets/:id".
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isID(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isID reports whether a path segment is a number, a hex string or a long
+// token rather than a route name
+func isID(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if strings.HasPrefix(segment, "0x") || strings.HasPrefix(segment, "0X") {
+		return true
+	}
+	digits := 0
+	for _, r := range segment {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits == len(segment) || (digits > 0 && len(segment) >= 16)
+}
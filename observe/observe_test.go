@@ -0,0 +1,50 @@
+package observe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/book", want: "/book"},
+		{path: "/prices-history", want: "/prices-history"},
+		{path: "/markets/12345", want: "/markets/:id"},
+		{path: "/markets/0xbd31dc8a20211944f6b70f31557f1001557b59905b7738480ca09bd4532f84af", want: "/markets/:id"},
+		{path: "/data/order/0xabc", want: "/data/order/:id"},
+		{path: "/events/slug/will-it-rain-in-2025", want: "/events/slug/:id"},
+		{path: "/v1/leaderboard", want: "/v1/leaderboard"},
+	}
+
+	for _, tt := range tests {
+		if got := Endpoint(tt.path); got != tt.want {
+			t.Errorf("Endpoint(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// recorder records the events it receives
+type recorder struct {
+	Nop
+	events []string
+}
+
+func (r *recorder) MessageReceived(channel, eventType string) {
+	r.events = append(r.events, channel+"/"+eventType)
+}
+
+func TestMulti(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	o := Multi(a, b)
+	o.MessageReceived("market", "book")
+	o.RequestEnd(context.Background(), Request{}, Result{})
+
+	for _, r := range []*recorder{a, b} {
+		if len(r.events) != 1 || r.events[0] != "market/book" {
+			t.Errorf("events = %v, want [market/book]", r.events)
+		}
+	}
+}
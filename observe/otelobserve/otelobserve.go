@@ -0,0 +1,78 @@
+// Package otelobserve traces the SDK's HTTP requests with OpenTelemetry.
+//
+// Every request becomes a client span named after the SDK client, method
+// and endpoint, e.g. "clob GET /book", started from the caller's context
+// where one is available. Rate limit responses are recorded as span events.
+//
+//	obs := otelobserve.New(nil) // the global TracerProvider
+//	clob, err := client.NewClobClient(&client.ClientConfig{Observer: obs})
+//
+// Combine it with a metrics observer with observe.Multi.
+package otelobserve
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ybina/polymarket-sdk-go/observe"
+)
+
+// ScopeName is the instrumentation scope of the spans
+const ScopeName = "github.com/ybina/polymarket-sdk-go"
+
+// Observer is an observe.Observer that traces requests
+type Observer struct {
+	observe.Nop
+	tracer trace.Tracer
+}
+
+var _ observe.Observer = (*Observer)(nil)
+
+// New creates an Observer that creates spans with provider, or with the
+// global TracerProvider when provider is nil
+func New(provider trace.TracerProvider) *Observer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Observer{tracer: provider.Tracer(ScopeName)}
+}
+
+// RequestStart starts the request's span
+func (o *Observer) RequestStart(ctx context.Context, req observe.Request) context.Context {
+	ctx, _ = o.tracer.Start(ctx, req.Client+" "+req.Method+" "+req.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("polymarket.client", req.Client),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("http.route", req.Endpoint),
+			attribute.String("url.path", req.Path),
+		))
+	return ctx
+}
+
+// RequestEnd records the outcome on the request's span and ends it
+func (o *Observer) RequestEnd(ctx context.Context, _ observe.Request, result observe.Result) {
+	span := trace.SpanFromContext(ctx)
+	switch {
+	case result.Err != nil:
+		span.RecordError(result.Err)
+		span.SetStatus(codes.Error, result.Err.Error())
+	default:
+		span.SetAttributes(attribute.Int("http.response.status_code", result.Status))
+		if result.Status >= 400 {
+			span.SetStatus(codes.Error, "")
+		}
+	}
+	span.End()
+}
+
+// RateLimited adds a rate_limited event to the request's span
+func (o *Observer) RateLimited(ctx context.Context, _ observe.Request, retryAfter time.Duration) {
+	trace.SpanFromContext(ctx).AddEvent("rate_limited",
+		trace.WithAttributes(attribute.Float64("retry_after_seconds", retryAfter.Seconds())))
+}
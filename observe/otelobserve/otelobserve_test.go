@@ -0,0 +1,78 @@
+package otelobserve
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ybina/polymarket-sdk-go/observe"
+)
+
+func TestObserver(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     observe.Result
+		rateLimit  bool
+		wantStatus codes.Code
+		wantEvents []string
+	}{
+		{name: "ok", result: observe.Result{Status: 200}, wantStatus: codes.Unset},
+		{name: "rate limited", result: observe.Result{Status: 429}, rateLimit: true, wantStatus: codes.Error, wantEvents: []string{"rate_limited"}},
+		{name: "transport error", result: observe.Result{Err: errors.New("refused")}, wantStatus: codes.Error, wantEvents: []string{"exception"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			obs := New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			req := observe.Request{Client: "clob", Method: "GET", Endpoint: "/markets/:id", Path: "/markets/0xabc"}
+			ctx := obs.RequestStart(context.Background(), req)
+			if tt.rateLimit {
+				obs.RateLimited(ctx, req, time.Second)
+			}
+			obs.RequestEnd(ctx, req, tt.result)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ended spans = %d, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != "clob GET /markets/:id" {
+				t.Errorf("Name() = %q", span.Name())
+			}
+			if span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("SpanKind() = %v, want client", span.SpanKind())
+			}
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("Status() = %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+			if !hasAttribute(span.Attributes(), attribute.String("url.path", "/markets/0xabc")) {
+				t.Errorf("Attributes() = %v, miss url.path", span.Attributes())
+			}
+			var events []string
+			for _, event := range span.Events() {
+				events = append(events, event.Name)
+			}
+			if len(events) != len(tt.wantEvents) || (len(events) > 0 && events[0] != tt.wantEvents[0]) {
+				t.Errorf("Events() = %v, want %v", events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
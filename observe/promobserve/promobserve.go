@@ -0,0 +1,161 @@
+// Package promobserve exports the SDK's HTTP and WebSocket traffic as
+// Prometheus metrics through the official client library.
+//
+//	obs := promobserve.New(nil)
+//	prometheus.MustRegister(obs)
+//	clob, err := client.NewClobClient(&client.ClientConfig{Observer: obs})
+//
+// It exports, prefixed with the namespace:
+//
+//	_http_requests_total{client,method,endpoint,status}
+//	_http_request_duration_seconds{client,method,endpoint} (histogram)
+//	_http_requests_in_flight{client}
+//	_http_rate_limited_total{client,endpoint}
+//	_websocket_reconnects_total{channel}
+//	_websocket_messages_total{channel,event_type}
+//
+// status is the HTTP status code, or "error" when no response was received.
+package promobserve
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ybina/polymarket-sdk-go/observe"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the request duration
+// histogram
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Options configures an Observer
+type Options struct {
+	// Prefix of every metric name (default "polymarket")
+	Namespace string
+
+	// Upper bounds of the request duration histogram in seconds
+	// (default DefaultBuckets)
+	Buckets []float64
+
+	// Labels added to every metric, e.g. the service name
+	ConstLabels prometheus.Labels
+}
+
+// Observer is an observe.Observer and a prometheus.Collector
+type Observer struct {
+	requests    *prometheus.CounterVec
+	durations   *prometheus.HistogramVec
+	inFlight    *prometheus.GaugeVec
+	rateLimited *prometheus.CounterVec
+	reconnects  *prometheus.CounterVec
+	messages    *prometheus.CounterVec
+}
+
+var _ observe.Observer = (*Observer)(nil)
+
+// New creates an Observer; register it with a prometheus.Registerer to
+// export its metrics
+func New(options *Options) *Observer {
+	var opts Options
+	if options != nil {
+		opts = *options
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "polymarket"
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = DefaultBuckets
+	}
+
+	return &Observer{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_requests_total",
+			Help:        "HTTP requests by client, endpoint and status.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"client", "method", "endpoint", "status"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_request_duration_seconds",
+			Help:        "HTTP request latency until the response headers arrived.",
+			Buckets:     opts.Buckets,
+			ConstLabels: opts.ConstLabels,
+		}, []string{"client", "method", "endpoint"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_requests_in_flight",
+			Help:        "HTTP requests being sent.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"client"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_rate_limited_total",
+			Help:        "HTTP 429 Too Many Requests responses.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"client", "endpoint"}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "websocket_reconnects_total",
+			Help:        "WebSocket reconnect attempts.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"channel"}),
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "websocket_messages_total",
+			Help:        "WebSocket messages received by event type.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"channel", "event_type"}),
+	}
+}
+
+func (o *Observer) collectors() []prometheus.Collector {
+	return []prometheus.Collector{o.requests, o.durations, o.inFlight, o.rateLimited, o.reconnects, o.messages}
+}
+
+// Describe implements prometheus.Collector
+func (o *Observer) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range o.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (o *Observer) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range o.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// RequestStart counts the request as in flight
+func (o *Observer) RequestStart(ctx context.Context, req observe.Request) context.Context {
+	o.inFlight.WithLabelValues(req.Client).Inc()
+	return ctx
+}
+
+// RequestEnd counts the request by status and records its duration
+func (o *Observer) RequestEnd(_ context.Context, req observe.Request, result observe.Result) {
+	status := "error"
+	if result.Err == nil {
+		status = strconv.Itoa(result.Status)
+	}
+	o.inFlight.WithLabelValues(req.Client).Dec()
+	o.requests.WithLabelValues(req.Client, req.Method, req.Endpoint, status).Inc()
+	o.durations.WithLabelValues(req.Client, req.Method, req.Endpoint).Observe(result.Elapsed.Seconds())
+}
+
+// RateLimited counts 429 responses
+func (o *Observer) RateLimited(_ context.Context, req observe.Request, _ time.Duration) {
+	o.rateLimited.WithLabelValues(req.Client, req.Endpoint).Inc()
+}
+
+// Reconnect counts WebSocket reconnect attempts
+func (o *Observer) Reconnect(channel string, _ int, _ time.Duration) {
+	o.reconnects.WithLabelValues(channel).Inc()
+}
+
+// MessageReceived counts WebSocket messages by event type
+func (o *Observer) MessageReceived(channel, eventType string) {
+	o.messages.WithLabelValues(channel, eventType).Inc()
+}
@@ -0,0 +1,63 @@
+package promobserve
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/ybina/polymarket-sdk-go/observe"
+)
+
+func TestObserver(t *testing.T) {
+	obs := New(&Options{Namespace: "test"})
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(obs); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	book := observe.Request{Client: "clob", Method: "GET", Endpoint: "/book", Path: "/book"}
+	ctx := obs.RequestStart(context.Background(), book)
+	obs.RequestEnd(ctx, book, observe.Result{Status: 200, Elapsed: 30 * time.Millisecond})
+	ctx = obs.RequestStart(context.Background(), book)
+	obs.RateLimited(ctx, book, time.Second)
+	obs.RequestEnd(ctx, book, observe.Result{Status: 429, Elapsed: 10 * time.Millisecond})
+	ctx = obs.RequestStart(context.Background(), book)
+	obs.RequestEnd(ctx, book, observe.Result{Err: errors.New("refused")})
+	obs.Reconnect("market", 1, time.Second)
+	obs.MessageReceived("market", "book")
+	obs.MessageReceived("market", "book")
+
+	want := `
+# HELP test_http_requests_total HTTP requests by client, endpoint and status.
+# TYPE test_http_requests_total counter
+test_http_requests_total{client="clob",endpoint="/book",method="GET",status="200"} 1
+test_http_requests_total{client="clob",endpoint="/book",method="GET",status="429"} 1
+test_http_requests_total{client="clob",endpoint="/book",method="GET",status="error"} 1
+# HELP test_http_requests_in_flight HTTP requests being sent.
+# TYPE test_http_requests_in_flight gauge
+test_http_requests_in_flight{client="clob"} 0
+# HELP test_http_rate_limited_total HTTP 429 Too Many Requests responses.
+# TYPE test_http_rate_limited_total counter
+test_http_rate_limited_total{client="clob",endpoint="/book"} 1
+# HELP test_websocket_reconnects_total WebSocket reconnect attempts.
+# TYPE test_websocket_reconnects_total counter
+test_websocket_reconnects_total{channel="market"} 1
+# HELP test_websocket_messages_total WebSocket messages received by event type.
+# TYPE test_websocket_messages_total counter
+test_websocket_messages_total{channel="market",event_type="book"} 2
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"test_http_requests_total", "test_http_requests_in_flight", "test_http_rate_limited_total",
+		"test_websocket_reconnects_total", "test_websocket_messages_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(obs, "test_http_request_duration_seconds"); n != 1 {
+		t.Errorf("duration histograms = %d, want 1", n)
+	}
+}
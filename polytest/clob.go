@@ -0,0 +1,677 @@
+package polytest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/client"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// maxBatchOrders is the most orders POST /orders accepts at once
+const maxBatchOrders = 15
+
+var errUnauthorized = errors.New("Unauthorized/Invalid api key")
+
+// l2Handler serves an authenticated request; body is the raw request body
+type l2Handler func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte)
+
+// clobHandler routes the CLOB REST API and the WebSocket channels
+func (s *Server) clobHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, "OK")
+	})
+	mux.HandleFunc("GET "+client.Time, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.options.Now().Unix())
+	})
+	mux.Handle("GET /ws/market", s.hub)
+	mux.HandleFunc("GET /ws/user", s.hub.serveUser)
+
+	// Markets and prices
+	mux.HandleFunc("GET "+client.GetMarkets, s.handleMarkets)
+	mux.HandleFunc("GET "+client.GetSimplifiedMarkets, s.handleMarkets)
+	mux.HandleFunc("GET "+client.GetSamplingMarkets, s.handleMarkets)
+	mux.HandleFunc("GET "+client.GetSamplingSimplifiedMarkets, s.handleMarkets)
+	mux.HandleFunc("GET "+client.GetMarket+"{condition_id}", s.handleMarket)
+	mux.HandleFunc("GET "+client.GetOrderBook, s.handleBook)
+	mux.HandleFunc("POST "+client.GetOrderBooks, s.handleBooks)
+	mux.HandleFunc("GET "+client.GetTickSize, s.handleTokenInfo)
+	mux.HandleFunc("GET "+client.GetNegRisk, s.handleTokenInfo)
+	mux.HandleFunc("GET "+client.GetFeeRate, s.handleTokenInfo)
+	mux.HandleFunc("GET "+client.GetMidpoint, s.handleQuote)
+	mux.HandleFunc("GET "+client.GetPrice, s.handleQuote)
+	mux.HandleFunc("GET "+client.GetSpread, s.handleQuote)
+	mux.HandleFunc("GET "+client.GetLastTradePrice, s.handleQuote)
+	mux.HandleFunc("POST "+client.GetMidpoints, s.handleQuotes)
+	mux.HandleFunc("POST "+client.GetPrices, s.handleQuotes)
+	mux.HandleFunc("POST "+client.GetSpreads, s.handleQuotes)
+	mux.HandleFunc("POST "+client.GetLastTradesPrices, s.handleQuotes)
+	mux.HandleFunc("GET "+client.GetPricesHistory, s.handlePricesHistory)
+	mux.HandleFunc("GET "+client.GetMarketTradesEvents+"{condition_id}", s.handleEmpty)
+
+	// L1
+	mux.HandleFunc("POST "+client.CreateApiKey, s.handleCreateAPIKey)
+	mux.HandleFunc("GET "+client.DeriveApiKey, s.handleDeriveAPIKey)
+
+	// L2
+	mux.HandleFunc("GET "+client.GetApiKeys, s.l2(s.handleGetAPIKeys))
+	mux.HandleFunc("DELETE "+client.DeleteApiKey, s.l2(s.handleDeleteAPIKey))
+	mux.HandleFunc("GET "+client.ClosedOnly, s.l2(func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+		writeJSON(w, http.StatusOK, types.BanStatus{})
+	}))
+	mux.HandleFunc("POST "+client.PostOrder, s.l2(s.handlePostOrder))
+	mux.HandleFunc("POST "+client.PostOrders, s.l2(s.handlePostOrders))
+	mux.HandleFunc("DELETE "+client.CancelOrder, s.l2(s.handleCancelOrder))
+	mux.HandleFunc("DELETE "+client.CancelOrders, s.l2(s.handleCancelOrders))
+	mux.HandleFunc("DELETE "+client.CancelAll, s.l2(s.handleCancelAll))
+	mux.HandleFunc("DELETE "+client.CancelMarketOrders, s.l2(s.handleCancelMarketOrders))
+	mux.HandleFunc("GET "+client.GetOrder+"{id}", s.l2(s.handleGetOrder))
+	mux.HandleFunc("GET "+client.GetOpenOrders, s.l2(s.handleOpenOrders))
+	mux.HandleFunc("GET "+client.GetTrades, s.l2(s.handleTrades))
+	mux.HandleFunc("GET "+client.GetBalanceAllowance, s.l2(func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+		writeJSON(w, http.StatusOK, types.BalanceAllowanceResponse{Balance: s.options.Balance, Allowance: s.options.Balance})
+	}))
+	mux.HandleFunc("GET "+client.UpdateBalanceAllowance, s.l2(func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]any{})
+	}))
+	mux.HandleFunc("GET "+client.IsOrderScoring, s.l2(func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+		writeJSON(w, http.StatusOK, types.OrderScoring{})
+	}))
+	mux.HandleFunc("POST "+client.AreOrdersScoring, s.l2(func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+		var params types.OrdersScoringParams
+		json.Unmarshal(body, &params)
+		scoring := types.OrdersScoring{}
+		for _, id := range params.OrderIDs {
+			scoring[id] = false
+		}
+		writeJSON(w, http.StatusOK, scoring)
+	}))
+	mux.HandleFunc("GET "+client.GetNotifications, s.l2(s.handleEmptyL2))
+	mux.HandleFunc("DELETE "+client.DropNotifications, s.l2(func(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+		writeJSON(w, http.StatusOK, "OK")
+	}))
+	for _, path := range []string{
+		client.GetEarningsForUserForDay,
+		client.GetLiquidityRewardPercentages,
+		client.GetRewardsEarningsPercentages,
+		client.GetTotalEarningsForUserForDay,
+		client.GetBuilderTrades,
+	} {
+		mux.HandleFunc("GET "+path, s.l2(s.handleEmptyL2))
+	}
+	mux.HandleFunc("GET "+client.GetRewardsMarketsCurrent, s.handleEmpty)
+	mux.HandleFunc("GET "+client.GetRewardsMarkets+"{condition_id}", s.handleEmpty)
+
+	return mux
+}
+
+// authL1 checks the L1 headers of r and returns the signer and the nonce
+func (s *Server) authL1(r *http.Request) (common.Address, uint64, error) {
+	address := r.Header.Get("POLY_ADDRESS")
+	timestamp, err := strconv.ParseInt(r.Header.Get("POLY_TIMESTAMP"), 10, 64)
+	if err != nil {
+		return common.Address{}, 0, fmt.Errorf("invalid POLY_TIMESTAMP")
+	}
+	nonce, err := strconv.ParseUint(r.Header.Get("POLY_NONCE"), 10, 64)
+	if err != nil {
+		return common.Address{}, 0, fmt.Errorf("invalid POLY_NONCE")
+	}
+	if err := s.checkTimestamp(timestamp); err != nil {
+		return common.Address{}, 0, err
+	}
+
+	ok, err := auth.VerifyEIP712Signature(address, r.Header.Get("POLY_SIGNATURE"), timestamp, nonce, s.options.ChainID)
+	if err != nil || !ok {
+		return common.Address{}, 0, fmt.Errorf("Invalid L1 Request headers")
+	}
+	return common.HexToAddress(address), nonce, nil
+}
+
+// authL2 checks the L2 headers of r, and its builder headers if any, and
+// returns the API key used
+func (s *Server) authL2(r *http.Request, body []byte) (*apiKey, error) {
+	s.mu.Lock()
+	key, ok := s.keys[r.Header.Get("POLY_API_KEY")]
+	s.mu.Unlock()
+	if !ok || r.Header.Get("POLY_PASSPHRASE") != key.creds.Passphrase ||
+		!common.IsHexAddress(r.Header.Get("POLY_ADDRESS")) || common.HexToAddress(r.Header.Get("POLY_ADDRESS")) != key.address {
+		return nil, errUnauthorized
+	}
+	if err := s.verifyHmac(r, key.creds.Secret, "POLY_TIMESTAMP", "POLY_SIGNATURE", body); err != nil {
+		return nil, err
+	}
+
+	if builderKey := r.Header.Get("POLY_BUILDER_API_KEY"); builderKey != "" {
+		s.mu.Lock()
+		builder, ok := s.builders[builderKey]
+		s.mu.Unlock()
+		if !ok || r.Header.Get("POLY_BUILDER_PASSPHRASE") != builder.Passphrase {
+			return nil, fmt.Errorf("invalid builder api key")
+		}
+		if err := s.verifyHmac(r, builder.Secret, "POLY_BUILDER_TIMESTAMP", "POLY_BUILDER_SIGNATURE", body); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// authUser checks the credentials of a user channel subscription
+func (s *Server) authUser(auth userAuth) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[auth.APIKey]
+	return ok && key.creds.Secret == auth.Secret && key.creds.Passphrase == auth.Passphrase
+}
+
+// verifyHmac checks the signature in the signatureHeader of r, made with
+// secret over the method, path and body
+func (s *Server) verifyHmac(r *http.Request, secret, timestampHeader, signatureHeader string, body []byte) error {
+	timestamp, err := strconv.ParseInt(r.Header.Get(timestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s", timestampHeader)
+	}
+	if err := s.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+	var bodyStr *string
+	if len(body) > 0 {
+		b := string(body)
+		bodyStr = &b
+	}
+	if !auth.VerifyHmacSignature(secret, timestamp, r.Method, r.URL.Path, bodyStr, r.Header.Get(signatureHeader)) {
+		return errUnauthorized
+	}
+	return nil
+}
+
+// checkTimestamp rejects timestamps too far from the server clock
+func (s *Server) checkTimestamp(timestamp int64) error {
+	if s.options.MaxClockSkew <= 0 {
+		return nil
+	}
+	skew := s.options.Now().Sub(time.Unix(timestamp, 0)).Abs()
+	if skew > s.options.MaxClockSkew {
+		return fmt.Errorf("timestamp %d is %s off the server time", timestamp, skew)
+	}
+	return nil
+}
+
+// l2 wraps h with L2 authentication
+func (s *Server) l2(h l2Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		key, err := s.authL2(r, body)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		h(w, r, key, body)
+	}
+}
+
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	address, nonce, err := s.authL1(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findKey(address, nonce) != nil {
+		writeError(w, http.StatusBadRequest, "Could not create api key")
+		return
+	}
+	key := &apiKey{creds: newCreds(), address: address, nonce: nonce}
+	s.keys[key.creds.Key] = key
+	writeJSON(w, http.StatusOK, rawCreds(key.creds))
+}
+
+func (s *Server) handleDeriveAPIKey(w http.ResponseWriter, r *http.Request) {
+	address, nonce, err := s.authL1(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := s.findKey(address, nonce)
+	if key == nil {
+		writeError(w, http.StatusBadRequest, "Could not derive api key!")
+		return
+	}
+	writeJSON(w, http.StatusOK, rawCreds(key.creds))
+}
+
+// findKey returns the key created for address with nonce; s.mu must be held
+func (s *Server) findKey(address common.Address, nonce uint64) *apiKey {
+	for _, key := range s.keys {
+		if key.address == address && key.nonce == nonce {
+			return key
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleGetAPIKeys(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := types.ApiKeysResponse{APIKeys: []string{}}
+	for _, k := range s.keys {
+		if k.address == key.address {
+			result.APIKeys = append(result.APIKeys, k.creds.Key)
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleDeleteAPIKey(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	s.mu.Lock()
+	delete(s.keys, key.creds.Key)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, "OK")
+}
+
+// newCreds generates a random key, a base64 URL encoded secret and a
+// passphrase, shaped like the ones the CLOB issues
+func newCreds() types.ApiKeyCreds {
+	b := make([]byte, 16)
+	rand.Read(b)
+	key := fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	passphrase := make([]byte, 32)
+	rand.Read(passphrase)
+	return types.ApiKeyCreds{
+		Key:        key,
+		Secret:     base64.URLEncoding.EncodeToString(secret),
+		Passphrase: hex.EncodeToString(passphrase),
+	}
+}
+
+func rawCreds(creds types.ApiKeyCreds) types.ApiKeyRaw {
+	return types.ApiKeyRaw{APIKey: creds.Key, Secret: creds.Secret, Passphrase: creds.Passphrase}
+}
+
+// verifyOrder checks that order is signed by its signer for the exchange
+// contract of its market
+func (s *Server) verifyOrder(order *types.SignedOrder) error {
+	q, err := s.exchange.quote(order.TokenID)
+	if err != nil {
+		return err
+	}
+	contracts, err := types.GetContractConfig(s.options.ChainID)
+	if err != nil {
+		return err
+	}
+	exchange := contracts.Exchange
+	if q.market.NegRisk {
+		exchange = contracts.NegRiskExchange
+	}
+	hash, err := auth.OrderHash(order, int64(s.options.ChainID), exchange)
+	if err != nil {
+		return err
+	}
+	signer, err := auth.RecoverAddress(hash, order.Signature)
+	if err != nil || signer != common.HexToAddress(order.Signer) {
+		return fmt.Errorf("invalid order signature")
+	}
+	return nil
+}
+
+// placeOrder submits a signed order for key
+func (s *Server) placeOrder(key *apiKey, o types.NewOrder) (types.OrderResponse, int) {
+	if o.Owner != key.creds.Key {
+		return types.OrderResponse{ErrorMsg: "the order owner has to be the owner of the API KEY"}, http.StatusBadRequest
+	}
+	if !common.IsHexAddress(o.Order.Signer) || common.HexToAddress(o.Order.Signer) != key.address {
+		return types.OrderResponse{ErrorMsg: "the order signer address has to be the address of the API KEY"}, http.StatusBadRequest
+	}
+	if err := s.verifyOrder(&o.Order); err != nil {
+		return types.OrderResponse{ErrorMsg: err.Error()}, http.StatusBadRequest
+	}
+	req, err := orderFromSigned(o.Owner, o.Order, o.OrderType)
+	if err != nil {
+		return types.OrderResponse{ErrorMsg: err.Error()}, http.StatusBadRequest
+	}
+	result, err := s.exchange.place(req)
+	if err != nil {
+		return types.OrderResponse{ErrorMsg: err.Error()}, http.StatusBadRequest
+	}
+	s.hub.publish(result.events)
+
+	return types.OrderResponse{
+		Success:            true,
+		OrderID:            result.order.id,
+		TransactionsHashes: []string{},
+		Status:             result.status,
+		MakingAmount:       formatFloat(result.makingAmount),
+		TakingAmount:       formatFloat(result.takingAmount),
+	}, http.StatusOK
+}
+
+func (s *Server) handlePostOrder(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	var o types.NewOrder
+	if err := json.Unmarshal(body, &o); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order payload")
+		return
+	}
+	resp, status := s.placeOrder(key, o)
+	writeJSON(w, status, resp)
+}
+
+func (s *Server) handlePostOrders(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	var orders []types.NewOrder
+	if err := json.Unmarshal(body, &orders); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order payload")
+		return
+	}
+	if len(orders) > maxBatchOrders {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Too many orders in payload: %d, max allowed: %d", len(orders), maxBatchOrders))
+		return
+	}
+	result := make([]types.OrderResponse, len(orders))
+	for i, o := range orders {
+		result[i], _ = s.placeOrder(key, o)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// cancel cancels the orders of key matched by keep. Requested IDs that are
+// not canceled are reported in NotCanceled.
+func (s *Server) cancel(w http.ResponseWriter, key *apiKey, requested []string, keep func(*order) bool) {
+	canceled, events := s.exchange.cancel(key.creds.Key, keep)
+	s.hub.publish(events)
+
+	resp := types.CancelOrdersResponse{Canceled: canceled, NotCanceled: map[string]string{}}
+	if resp.Canceled == nil {
+		resp.Canceled = []string{}
+	}
+	done := make(map[string]bool)
+	for _, id := range canceled {
+		done[id] = true
+	}
+	for _, id := range requested {
+		if !done[id] {
+			resp.NotCanceled[id] = "order can't be found - already canceled or matched"
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCancelOrder(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	var payload types.OrderPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.OrderID == "" {
+		writeError(w, http.StatusBadRequest, "Invalid order payload")
+		return
+	}
+	s.cancel(w, key, []string{payload.OrderID}, func(o *order) bool { return o.id == payload.OrderID })
+}
+
+func (s *Server) handleCancelOrders(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	var ids []string
+	if err := json.Unmarshal(body, &ids); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid order payload")
+		return
+	}
+	wanted := make(map[string]bool)
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	s.cancel(w, key, ids, func(o *order) bool { return wanted[o.id] })
+}
+
+func (s *Server) handleCancelAll(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	s.cancel(w, key, nil, func(*order) bool { return true })
+}
+
+func (s *Server) handleCancelMarketOrders(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	var params types.OrderMarketCancelParams
+	if err := json.Unmarshal(body, &params); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+	s.cancel(w, key, nil, func(o *order) bool {
+		if params.AssetID != nil && o.tokenID != *params.AssetID {
+			return false
+		}
+		return params.Market == nil || s.exchange.market(o.tokenID).ConditionID == *params.Market
+	})
+}
+
+func (s *Server) handleGetOrder(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	o, err := s.exchange.order(key.creds.Key, r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (s *Server) handleOpenOrders(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	q := r.URL.Query()
+	orders := s.exchange.openOrders(key.creds.Key, func(o *order) bool {
+		if id := q.Get("id"); id != "" && o.id != id {
+			return false
+		}
+		if asset := q.Get("asset_id"); asset != "" && o.tokenID != asset {
+			return false
+		}
+		market := q.Get("market")
+		return market == "" || s.exchange.market(o.tokenID).ConditionID == market
+	})
+	writeJSON(w, http.StatusOK, types.PaginationPayload{Limit: len(orders), Count: len(orders), NextCursor: types.END_CURSOR, Data: orders})
+}
+
+func (s *Server) handleTrades(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	q := r.URL.Query()
+	before, _ := strconv.ParseInt(q.Get("before"), 10, 64)
+	after, _ := strconv.ParseInt(q.Get("after"), 10, 64)
+
+	trades := []types.Trade{}
+	for _, t := range s.exchange.tradesOf(key.creds.Key) {
+		matchTime, _ := strconv.ParseInt(t.MatchTime, 10, 64)
+		switch {
+		case q.Get("id") != "" && t.ID != q.Get("id"),
+			q.Get("market") != "" && t.Market != q.Get("market"),
+			q.Get("asset_id") != "" && t.AssetID != q.Get("asset_id"),
+			q.Get("maker_address") != "" && !common.IsHexAddress(q.Get("maker_address")),
+			before > 0 && matchTime >= before,
+			after > 0 && matchTime <= after:
+			continue
+		}
+		if maker := q.Get("maker_address"); maker != "" && !tradedBy(t, common.HexToAddress(maker)) {
+			continue
+		}
+		trades = append(trades, t)
+	}
+	writeJSON(w, http.StatusOK, types.PaginationPayload{Limit: len(trades), Count: len(trades), NextCursor: types.END_CURSOR, Data: trades})
+}
+
+// tradedBy reports whether address was the taker or a maker of t
+func tradedBy(t types.Trade, address common.Address) bool {
+	if common.IsHexAddress(t.MakerAddress) && common.HexToAddress(t.MakerAddress) == address {
+		return true
+	}
+	for _, m := range t.MakerOrders {
+		if common.IsHexAddress(m.MakerAddress) && common.HexToAddress(m.MakerAddress) == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleMarkets(w http.ResponseWriter, r *http.Request) {
+	markets := s.exchange.clobMarkets("")
+	if markets == nil {
+		markets = []clobMarket{}
+	}
+	writeJSON(w, http.StatusOK, types.PaginationPayload{Limit: len(markets), Count: len(markets), NextCursor: types.END_CURSOR, Data: markets})
+}
+
+func (s *Server) handleMarket(w http.ResponseWriter, r *http.Request) {
+	markets := s.exchange.clobMarkets(r.PathValue("condition_id"))
+	if len(markets) == 0 {
+		writeError(w, http.StatusNotFound, "market not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, markets[0])
+}
+
+func (s *Server) handleBook(w http.ResponseWriter, r *http.Request) {
+	book, err := s.exchange.summary(r.URL.Query().Get("token_id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, book)
+}
+
+func (s *Server) handleBooks(w http.ResponseWriter, r *http.Request) {
+	var params []types.BookParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+	books := []types.OrderBookSummary{}
+	for _, p := range params {
+		if book, err := s.exchange.summary(p.TokenID); err == nil {
+			books = append(books, book)
+		}
+	}
+	writeJSON(w, http.StatusOK, books)
+}
+
+// handleTokenInfo serves the tick size, neg risk and fee rate endpoints
+func (s *Server) handleTokenInfo(w http.ResponseWriter, r *http.Request) {
+	q, err := s.exchange.quote(r.URL.Query().Get("token_id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	switch r.URL.Path {
+	case client.GetTickSize:
+		tick, _ := strconv.ParseFloat(string(q.market.TickSize), 64)
+		writeJSON(w, http.StatusOK, map[string]float64{"minimum_tick_size": tick})
+	case client.GetNegRisk:
+		writeJSON(w, http.StatusOK, map[string]bool{"neg_risk": q.market.NegRisk})
+	default:
+		writeJSON(w, http.StatusOK, map[string]int{"base_fee": q.market.FeeRateBps})
+	}
+}
+
+// price returns the best price on side of the book: the best bid for BUY and
+// the best ask for SELL
+func (q quote) price(side types.Side) float64 {
+	if side == types.SideSell {
+		return q.ask
+	}
+	return q.bid
+}
+
+// handleQuote serves the single token midpoint, price, spread and last trade
+// price endpoints
+func (s *Server) handleQuote(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q, err := s.exchange.quote(params.Get("token_id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	switch r.URL.Path {
+	case client.GetMidpoint:
+		writeJSON(w, http.StatusOK, map[string]string{"mid": formatFloat(q.midpoint())})
+	case client.GetPrice:
+		side := types.Side(params.Get("side"))
+		if side != types.SideBuy && side != types.SideSell {
+			writeError(w, http.StatusBadRequest, "Invalid side")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"price": formatFloat(q.price(side))})
+	case client.GetSpread:
+		writeJSON(w, http.StatusOK, map[string]string{"spread": formatFloat(q.spread())})
+	default:
+		writeJSON(w, http.StatusOK, lastTrade(q))
+	}
+}
+
+// handleQuotes serves the batch midpoint, price, spread and last trade
+// price endpoints
+func (s *Server) handleQuotes(w http.ResponseWriter, r *http.Request) {
+	var params []types.BookParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	var lastTrades []map[string]string
+	values := make(map[string]any)
+	for _, p := range params {
+		q, err := s.exchange.quote(p.TokenID)
+		if err != nil {
+			continue
+		}
+		switch r.URL.Path {
+		case client.GetMidpoints:
+			values[p.TokenID] = formatFloat(q.midpoint())
+		case client.GetPrices:
+			sides, _ := values[p.TokenID].(map[string]string)
+			if sides == nil {
+				sides = make(map[string]string)
+				values[p.TokenID] = sides
+			}
+			sides[string(p.Side)] = formatFloat(q.price(p.Side))
+		case client.GetSpreads:
+			values[p.TokenID] = formatFloat(q.spread())
+		default:
+			last := lastTrade(q)
+			last["token_id"] = p.TokenID
+			lastTrades = append(lastTrades, last)
+		}
+	}
+	if r.URL.Path == client.GetLastTradesPrices {
+		if lastTrades == nil {
+			lastTrades = []map[string]string{}
+		}
+		writeJSON(w, http.StatusOK, lastTrades)
+		return
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+func lastTrade(q quote) map[string]string {
+	return map[string]string{"price": formatFloat(q.lastTradePrice), "side": string(q.lastTradeSide)}
+}
+
+func (s *Server) handlePricesHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, _ := strconv.ParseInt(q.Get("startTs"), 10, 64)
+	end, _ := strconv.ParseInt(q.Get("endTs"), 10, 64)
+	writeJSON(w, http.StatusOK, map[string]any{"history": s.exchange.history(q.Get("market"), start, end)})
+}
+
+// handleEmpty serves endpoints the fake has no data for
+func (s *Server) handleEmpty(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []any{})
+}
+
+func (s *Server) handleEmptyL2(w http.ResponseWriter, r *http.Request, key *apiKey, body []byte) {
+	s.handleEmpty(w, r)
+}
@@ -0,0 +1,741 @@
+package polytest
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// Market is a CLOB market the fake exchange trades
+type Market struct {
+	ConditionID string
+	Question    string
+	Tokens      []Token
+	// TickSize is the minimum price increment (default 0.01)
+	TickSize types.TickSize
+	// MinOrderSize is the smallest resting order in tokens (default 5)
+	MinOrderSize float64
+	NegRisk      bool
+	FeeRateBps   int
+}
+
+// Token is one outcome token of a market
+type Token struct {
+	TokenID string
+	Outcome string
+}
+
+// tokenAmountScale converts between on-chain amounts and tokens or USDC,
+// both of which have 6 decimals
+const tokenAmountScale = 1e6
+
+// epsilon absorbs float rounding when comparing prices and sizes
+const epsilon = 1e-9
+
+var (
+	errUnknownToken = errors.New("no orderbook exists for the requested token id")
+	errNotFound     = errors.New("order not found")
+)
+
+// order is a resting or processed order
+type order struct {
+	id           string
+	owner        string // API key that placed the order
+	maker        string // Maker address
+	tokenID      string
+	side         types.Side
+	price        float64
+	originalSize float64
+	matched      float64
+	orderType    types.OrderType
+	expiration   string
+	status       string
+	createdAt    int64
+	trades       []string
+}
+
+func (o *order) remaining() float64 {
+	return o.originalSize - o.matched
+}
+
+// book is the order book of one token. Bids are sorted best (highest) first,
+// asks best (lowest) first; orders at the same price keep arrival order.
+type book struct {
+	market         *Market
+	token          Token
+	bids           []*order
+	asks           []*order
+	lastTradePrice float64
+	lastTradeSide  types.Side
+}
+
+// levels aggregates one side of the book by price
+func levels(orders []*order) []types.OrderSummary {
+	var result []types.OrderSummary
+	for _, o := range orders {
+		price := formatFloat(o.price)
+		if n := len(result); n > 0 && result[n-1].Price == price {
+			size, _ := strconv.ParseFloat(result[n-1].Size, 64)
+			result[n-1].Size = formatFloat(size + o.remaining())
+			continue
+		}
+		result = append(result, types.OrderSummary{Price: price, Size: formatFloat(o.remaining())})
+	}
+	return result
+}
+
+// levelSize returns the total size resting at price on one side
+func levelSize(orders []*order, price float64) float64 {
+	size := 0.0
+	for _, o := range orders {
+		if math.Abs(o.price-price) < epsilon {
+			size += o.remaining()
+		}
+	}
+	return size
+}
+
+// summary returns the book in its REST representation
+func (b *book) summary(now time.Time) types.OrderBookSummary {
+	// The API lists bids and asks from the worst price to the best
+	bids, asks := levels(b.bids), levels(b.asks)
+	slices.Reverse(bids)
+	slices.Reverse(asks)
+
+	s := types.OrderBookSummary{
+		Market:       b.market.ConditionID,
+		AssetID:      b.token.TokenID,
+		Timestamp:    strconv.FormatInt(now.UnixMilli(), 10),
+		Bids:         bids,
+		Asks:         asks,
+		MinOrderSize: formatFloat(b.market.MinOrderSize),
+		TickSize:     string(b.market.TickSize),
+		NegRisk:      b.market.NegRisk,
+	}
+	if s.Bids == nil {
+		s.Bids = []types.OrderSummary{}
+	}
+	if s.Asks == nil {
+		s.Asks = []types.OrderSummary{}
+	}
+	data, _ := json.Marshal(s)
+	s.Hash = fmt.Sprintf("%x", sha1.Sum(data))
+	return s
+}
+
+func (b *book) bestBid() (float64, bool) {
+	if len(b.bids) == 0 {
+		return 0, false
+	}
+	return b.bids[0].price, true
+}
+
+func (b *book) bestAsk() (float64, bool) {
+	if len(b.asks) == 0 {
+		return 0, false
+	}
+	return b.asks[0].price, true
+}
+
+// rest inserts o behind every order at a better or equal price
+func (b *book) rest(o *order) {
+	side := &b.asks
+	better := func(p float64) bool { return p <= o.price+epsilon }
+	if o.side == types.SideBuy {
+		side = &b.bids
+		better = func(p float64) bool { return p >= o.price-epsilon }
+	}
+	i := 0
+	for i < len(*side) && better((*side)[i].price) {
+		i++
+	}
+	*side = slices.Insert(*side, i, o)
+}
+
+// remove takes o off the book
+func (b *book) remove(o *order) {
+	for _, side := range []*[]*order{&b.bids, &b.asks} {
+		if i := slices.Index(*side, o); i >= 0 {
+			*side = slices.Delete(*side, i, i+1)
+		}
+	}
+}
+
+// fill is one match between an incoming order and a resting one
+type fill struct {
+	maker *order
+	size  float64
+	price float64
+}
+
+// exchange holds the markets, books, orders and trades of the fake CLOB
+type exchange struct {
+	mu      sync.Mutex
+	markets []*Market
+	books   map[string]*book
+	orders  map[string]*order
+	trades  []types.Trade
+	seq     int
+	now     func() time.Time
+}
+
+func newExchange(now func() time.Time) *exchange {
+	return &exchange{
+		books:  make(map[string]*book),
+		orders: make(map[string]*order),
+		now:    now,
+	}
+}
+
+// addMarket registers m and opens an empty book for each of its tokens
+func (e *exchange) addMarket(m Market) {
+	if m.TickSize == "" {
+		m.TickSize = types.TickSize001
+	}
+	if m.MinOrderSize == 0 {
+		m.MinOrderSize = 5
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	market := &m
+	e.markets = append(e.markets, market)
+	for _, token := range m.Tokens {
+		e.books[token.TokenID] = &book{market: market, token: token}
+	}
+}
+
+// marketEvent is a market channel message for one asset
+type marketEvent struct {
+	assetID string
+	message any
+}
+
+// orderRequest is an order as the exchange sees it
+type orderRequest struct {
+	owner      string
+	maker      string
+	tokenID    string
+	side       types.Side
+	price      float64
+	size       float64
+	orderType  types.OrderType
+	expiration string
+}
+
+// orderFromSigned converts the amounts of a signed order into a price and a
+// size in tokens
+func orderFromSigned(owner string, signed types.SignedOrder, orderType types.OrderType) (orderRequest, error) {
+	if signed.MakerAmount == nil || signed.TakerAmount == nil || signed.MakerAmount.Sign() <= 0 || signed.TakerAmount.Sign() <= 0 {
+		return orderRequest{}, fmt.Errorf("invalid order amounts")
+	}
+	maker, _ := new(big.Float).SetInt(signed.MakerAmount).Float64()
+	taker, _ := new(big.Float).SetInt(signed.TakerAmount).Float64()
+
+	req := orderRequest{
+		owner:      owner,
+		maker:      signed.Maker,
+		tokenID:    signed.TokenID,
+		side:       signed.Side,
+		orderType:  orderType,
+		expiration: signed.Expiration,
+	}
+	switch signed.Side {
+	case types.SideBuy:
+		// Pays makerAmount USDC for takerAmount tokens
+		req.price, req.size = maker/taker, taker/tokenAmountScale
+	case types.SideSell:
+		// Gives makerAmount tokens for takerAmount USDC
+		req.price, req.size = taker/maker, maker/tokenAmountScale
+	default:
+		return orderRequest{}, fmt.Errorf("invalid order side %q", signed.Side)
+	}
+	req.price = math.Round(req.price*1e6) / 1e6
+	if req.orderType == "" {
+		req.orderType = types.OrderTypeGTC
+	}
+	return req, nil
+}
+
+// orderResult is the outcome of placing an order
+type orderResult struct {
+	order        *order
+	status       string
+	makingAmount float64
+	takingAmount float64
+	events       []marketEvent
+}
+
+// place validates req, matches it against the book and rests what is left
+// of a GTC or GTD order
+func (e *exchange) place(req orderRequest) (*orderResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, ok := e.books[req.tokenID]
+	if !ok {
+		return nil, errUnknownToken
+	}
+
+	tick, _ := strconv.ParseFloat(string(b.market.TickSize), 64)
+	if req.price < tick-epsilon || req.price > 1-tick+epsilon {
+		return nil, fmt.Errorf("invalid price (%s), min: %s - max: %s", formatFloat(req.price), formatFloat(tick), formatFloat(1-tick))
+	}
+	if ticks := req.price / tick; math.Abs(ticks-math.Round(ticks)) > 1e-6 {
+		return nil, fmt.Errorf("order price %s breaks minimum tick size rule: %s", formatFloat(req.price), b.market.TickSize)
+	}
+	resting := req.orderType == types.OrderTypeGTC || req.orderType == types.OrderTypeGTD
+	if resting && req.size < b.market.MinOrderSize-epsilon {
+		return nil, fmt.Errorf("order size %s lower than the minimum: %s", formatFloat(req.size), formatFloat(b.market.MinOrderSize))
+	}
+
+	// Find the resting orders the incoming one crosses
+	opposite := b.asks
+	crosses := func(p float64) bool { return p <= req.price+epsilon }
+	if req.side == types.SideSell {
+		opposite = b.bids
+		crosses = func(p float64) bool { return p >= req.price-epsilon }
+	}
+	var fills []fill
+	left := req.size
+	for _, maker := range opposite {
+		if left <= epsilon || !crosses(maker.price) {
+			break
+		}
+		size := min(left, maker.remaining())
+		fills = append(fills, fill{maker: maker, size: size, price: maker.price})
+		left -= size
+	}
+
+	if req.orderType == types.OrderTypeFOK && left > epsilon {
+		return nil, fmt.Errorf("order couldn't be fully filled. FOK orders are fully filled or killed.")
+	}
+
+	now := e.now()
+	e.seq++
+	o := &order{
+		id:           fmt.Sprintf("0x%064x", e.seq),
+		owner:        req.owner,
+		maker:        req.maker,
+		tokenID:      req.tokenID,
+		side:         req.side,
+		price:        req.price,
+		originalSize: req.size,
+		orderType:    req.orderType,
+		expiration:   req.expiration,
+		status:       "LIVE",
+		createdAt:    now.Unix(),
+	}
+	e.orders[o.id] = o
+
+	result := &orderResult{order: o}
+	changed := make(map[float64]types.Side)
+	for _, f := range fills {
+		trade := e.execute(b, o, f, now)
+		result.events = append(result.events, marketEvent{assetID: b.token.TokenID, message: &types.LastTradePriceMessage{
+			EventType:  types.EventTypeLastTradePrice,
+			AssetID:    b.token.TokenID,
+			Market:     b.market.ConditionID,
+			Price:      trade.Price,
+			Side:       o.side,
+			Size:       trade.Size,
+			FeeRateBps: trade.FeeRateBps,
+			Timestamp:  strconv.FormatInt(now.UnixMilli(), 10),
+		}})
+		changed[f.price] = f.maker.side
+
+		usdc := f.size * f.price
+		if o.side == types.SideBuy {
+			result.makingAmount += usdc
+			result.takingAmount += f.size
+		} else {
+			result.makingAmount += f.size
+			result.takingAmount += usdc
+		}
+	}
+
+	switch {
+	case o.remaining() <= epsilon:
+		o.status = "MATCHED"
+		result.status = "matched"
+	case resting:
+		b.rest(o)
+		changed[o.price] = o.side
+		result.status = "live"
+	case o.matched > 0:
+		o.status = "CANCELED"
+		result.status = "matched"
+	default:
+		o.status = "CANCELED"
+		result.status = "unmatched"
+	}
+
+	if len(changed) > 0 {
+		result.events = append(result.events, e.priceChange(b, changed, now))
+	}
+	return result, nil
+}
+
+// execute books a fill between taker and the resting maker order
+func (e *exchange) execute(b *book, taker *order, f fill, now time.Time) types.Trade {
+	f.maker.matched += f.size
+	taker.matched += f.size
+	if f.maker.remaining() <= epsilon {
+		f.maker.status = "MATCHED"
+		b.remove(f.maker)
+	}
+	b.lastTradePrice, b.lastTradeSide = f.price, taker.side
+
+	trade := types.Trade{
+		ID:           fmt.Sprintf("trade-%d", len(e.trades)+1),
+		TakerOrderID: taker.id,
+		Market:       b.market.ConditionID,
+		AssetID:      b.token.TokenID,
+		Side:         taker.side,
+		Size:         formatFloat(f.size),
+		FeeRateBps:   strconv.Itoa(b.market.FeeRateBps),
+		Price:        formatFloat(f.price),
+		Status:       "MATCHED",
+		MatchTime:    strconv.FormatInt(now.Unix(), 10),
+		LastUpdate:   strconv.FormatInt(now.Unix(), 10),
+		Outcome:      b.token.Outcome,
+		Owner:        taker.owner,
+		MakerAddress: taker.maker,
+		MakerOrders: []types.MakerOrder{{
+			OrderID:       f.maker.id,
+			Owner:         f.maker.owner,
+			MakerAddress:  f.maker.maker,
+			MatchedAmount: formatFloat(f.size),
+			Price:         formatFloat(f.price),
+			FeeRateBps:    strconv.Itoa(b.market.FeeRateBps),
+			AssetID:       b.token.TokenID,
+			Outcome:       b.token.Outcome,
+			Side:          f.maker.side,
+		}},
+		TraderSide: "TAKER",
+	}
+	e.trades = append(e.trades, trade)
+	taker.trades = append(taker.trades, trade.ID)
+	f.maker.trades = append(f.maker.trades, trade.ID)
+	return trade
+}
+
+// priceChange reports the new aggregate size of the changed price levels
+func (e *exchange) priceChange(b *book, changed map[float64]types.Side, now time.Time) marketEvent {
+	summary := b.summary(now)
+	bestBid, bestAsk := "0", "1"
+	if p, ok := b.bestBid(); ok {
+		bestBid = formatFloat(p)
+	}
+	if p, ok := b.bestAsk(); ok {
+		bestAsk = formatFloat(p)
+	}
+
+	prices := make([]float64, 0, len(changed))
+	for price := range changed {
+		prices = append(prices, price)
+	}
+	slices.Sort(prices)
+
+	msg := &types.PriceChangeMessage{
+		EventType: types.EventTypePriceChange,
+		Market:    b.market.ConditionID,
+		Timestamp: summary.Timestamp,
+	}
+	for _, price := range prices {
+		side := changed[price]
+		orders := b.asks
+		if side == types.SideBuy {
+			orders = b.bids
+		}
+		msg.PriceChanges = append(msg.PriceChanges, types.PriceChange{
+			AssetID: b.token.TokenID,
+			Price:   formatFloat(price),
+			Size:    formatFloat(levelSize(orders, price)),
+			Side:    side,
+			Hash:    summary.Hash,
+			BestBid: bestBid,
+			BestAsk: bestAsk,
+		})
+	}
+	return marketEvent{assetID: b.token.TokenID, message: msg}
+}
+
+// cancel cancels the live orders that owner placed and match keep
+func (e *exchange) cancel(owner string, keep func(*order) bool) (canceled []string, events []marketEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	changed := make(map[string]map[float64]types.Side)
+	for _, o := range e.orders {
+		if o.owner != owner || o.status != "LIVE" || !keep(o) {
+			continue
+		}
+		o.status = "CANCELED"
+		b := e.books[o.tokenID]
+		b.remove(o)
+		canceled = append(canceled, o.id)
+		if changed[o.tokenID] == nil {
+			changed[o.tokenID] = make(map[float64]types.Side)
+		}
+		changed[o.tokenID][o.price] = o.side
+	}
+
+	for tokenID, levels := range changed {
+		events = append(events, e.priceChange(e.books[tokenID], levels, now))
+	}
+	slices.Sort(canceled)
+	return canceled, events
+}
+
+// openOrder returns the order in its REST representation
+func (e *exchange) openOrder(o *order) types.OpenOrder {
+	b := e.books[o.tokenID]
+	return types.OpenOrder{
+		ID:              o.id,
+		Status:          o.status,
+		Owner:           o.owner,
+		MakerAddress:    o.maker,
+		Market:          b.market.ConditionID,
+		AssetID:         o.tokenID,
+		Side:            string(o.side),
+		OriginalSize:    formatFloat(o.originalSize),
+		SizeMatched:     formatFloat(o.matched),
+		Price:           formatFloat(o.price),
+		AssociateTrades: slices.Clone(o.trades),
+		Outcome:         b.token.Outcome,
+		CreatedAt:       o.createdAt,
+		Expiration:      o.expiration,
+		OrderType:       string(o.orderType),
+	}
+}
+
+// formatFloat renders a price or size the way the CLOB API does
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+}
+
+// quote is a snapshot of the top of a book
+type quote struct {
+	market         *Market
+	bid, ask       float64
+	lastTradePrice float64
+	lastTradeSide  types.Side
+}
+
+func (q quote) midpoint() float64 { return (q.bid + q.ask) / 2 }
+func (q quote) spread() float64   { return q.ask - q.bid }
+
+// quote returns the top of the book of tokenID. An empty side quotes as 0
+// for bids and 1 for asks.
+func (e *exchange) quote(tokenID string) (quote, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, ok := e.books[tokenID]
+	if !ok {
+		return quote{}, errUnknownToken
+	}
+	q := quote{market: b.market, bid: 0, ask: 1, lastTradePrice: b.lastTradePrice, lastTradeSide: b.lastTradeSide}
+	if p, ok := b.bestBid(); ok {
+		q.bid = p
+	}
+	if p, ok := b.bestAsk(); ok {
+		q.ask = p
+	}
+	return q, nil
+}
+
+// summary returns the book of tokenID
+func (e *exchange) summary(tokenID string) (types.OrderBookSummary, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, ok := e.books[tokenID]
+	if !ok {
+		return types.OrderBookSummary{}, errUnknownToken
+	}
+	return b.summary(e.now()), nil
+}
+
+// snapshots returns a book message for every known token in assetIDs
+func (e *exchange) snapshots(assetIDs []string) []types.BookMessage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	var msgs []types.BookMessage
+	for _, id := range assetIDs {
+		b, ok := e.books[id]
+		if !ok {
+			continue
+		}
+		s := b.summary(now)
+		msg := types.BookMessage{
+			EventType:    types.EventTypeBook,
+			AssetID:      s.AssetID,
+			Market:       s.Market,
+			Timestamp:    s.Timestamp,
+			Hash:         s.Hash,
+			Bids:         s.Bids,
+			Asks:         s.Asks,
+			MinOrderSize: s.MinOrderSize,
+			TickSize:     s.TickSize,
+			NegRisk:      s.NegRisk,
+		}
+		if b.lastTradePrice > 0 {
+			msg.LastTradePrice = formatFloat(b.lastTradePrice)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// clobMarket is a market in the representation of the CLOB markets endpoints
+type clobMarket struct {
+	ConditionID      string      `json:"condition_id"`
+	Question         string      `json:"question"`
+	Tokens           []clobToken `json:"tokens"`
+	MinimumOrderSize float64     `json:"minimum_order_size"`
+	MinimumTickSize  float64     `json:"minimum_tick_size"`
+	NegRisk          bool        `json:"neg_risk"`
+	Active           bool        `json:"active"`
+	Closed           bool        `json:"closed"`
+	AcceptingOrders  bool        `json:"accepting_orders"`
+	EnableOrderBook  bool        `json:"enable_order_book"`
+}
+
+type clobToken struct {
+	TokenID string  `json:"token_id"`
+	Outcome string  `json:"outcome"`
+	Price   float64 `json:"price"`
+}
+
+// clobMarkets returns every market, or only the one with conditionID when
+// it is set
+func (e *exchange) clobMarkets(conditionID string) []clobMarket {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var result []clobMarket
+	for _, m := range e.markets {
+		if conditionID != "" && m.ConditionID != conditionID {
+			continue
+		}
+		tick, _ := strconv.ParseFloat(string(m.TickSize), 64)
+		cm := clobMarket{
+			ConditionID:      m.ConditionID,
+			Question:         m.Question,
+			MinimumOrderSize: m.MinOrderSize,
+			MinimumTickSize:  tick,
+			NegRisk:          m.NegRisk,
+			Active:           true,
+			AcceptingOrders:  true,
+			EnableOrderBook:  true,
+		}
+		for _, token := range m.Tokens {
+			b := e.books[token.TokenID]
+			price := b.lastTradePrice
+			bid, hasBid := b.bestBid()
+			ask, hasAsk := b.bestAsk()
+			if hasBid && hasAsk {
+				price = math.Round((bid+ask)/2*1e6) / 1e6
+			}
+			cm.Tokens = append(cm.Tokens, clobToken{TokenID: token.TokenID, Outcome: token.Outcome, Price: price})
+		}
+		result = append(result, cm)
+	}
+	return result
+}
+
+// order looks up an order owner placed
+func (e *exchange) order(owner, id string) (types.OpenOrder, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o, ok := e.orders[id]
+	if !ok || o.owner != owner {
+		return types.OpenOrder{}, errNotFound
+	}
+	return e.openOrder(o), nil
+}
+
+// openOrders returns the live orders of owner that match keep
+func (e *exchange) openOrders(owner string, keep func(*order) bool) []types.OpenOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []types.OpenOrder{}
+	for _, o := range e.orders {
+		if o.owner == owner && o.status == "LIVE" && keep(o) {
+			result = append(result, e.openOrder(o))
+		}
+	}
+	slices.SortFunc(result, func(a, b types.OpenOrder) int { return strings.Compare(a.ID, b.ID) })
+	return result
+}
+
+// market returns the market tokenID belongs to; e.mu must be held
+func (e *exchange) market(tokenID string) *Market {
+	if b, ok := e.books[tokenID]; ok {
+		return b.market
+	}
+	return nil
+}
+
+// tradesOf returns the trades owner took part in, from owner's side
+func (e *exchange) tradesOf(owner string) []types.Trade {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []types.Trade{}
+	for _, t := range e.trades {
+		if t.Owner == owner {
+			result = append(result, t)
+			continue
+		}
+		for _, m := range t.MakerOrders {
+			if m.Owner == owner {
+				t.TraderSide = "MAKER"
+				result = append(result, t)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// allTrades returns every trade executed
+func (e *exchange) allTrades() []types.Trade {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.trades)
+}
+
+// history returns the price of every trade in tokenID between start and end
+func (e *exchange) history(tokenID string, start, end int64) []types.MarketPrice {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	result := []types.MarketPrice{}
+	for _, t := range e.trades {
+		ts, _ := strconv.ParseInt(t.MatchTime, 10, 64)
+		if t.AssetID != tokenID || (start > 0 && ts < start) || (end > 0 && ts > end) {
+			continue
+		}
+		price, _ := strconv.ParseFloat(t.Price, 64)
+		result = append(result, types.MarketPrice{T: ts, P: price})
+	}
+	return result
+}
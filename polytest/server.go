@@ -0,0 +1,296 @@
+// Package polytest runs in-process fakes of the Polymarket CLOB REST API, the
+// CLOB market and user WebSocket channels and the Gamma and Data APIs, so code
+// built on the SDK can be tested end to end without network access.
+//
+// The CLOB fake keeps an in-memory order book per token and matches orders by
+// price-time priority. It checks L1 (EIP-712) and L2 (HMAC) authentication
+// headers with the auth package, exactly as the SDK builds them, and builder
+// headers when builder credentials are registered. Orders must be signed by
+// their signer, which has to be the address of the API key, for the exchange
+// contract of their market (auth.OrderHash).
+//
+// The Gamma and Data fakes serve fixtures registered per path.
+package polytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// Options configures a Server
+type Options struct {
+	// Chain ID the L1 signatures are checked against (default ChainAmoy)
+	ChainID types.Chain
+
+	// Clock used for timestamps and auth checks (default time.Now)
+	Now func() time.Time
+
+	// Reject auth timestamps further than this from Now (0 = no check)
+	MaxClockSkew time.Duration
+
+	// Balance and allowance reported by /balance-allowance, in 1e6 units
+	// (default 1000 USDC)
+	Balance string
+}
+
+// Server is a set of fake Polymarket APIs listening on loopback
+type Server struct {
+	options  Options
+	exchange *exchange
+	hub      *hub
+
+	clob  *httptest.Server
+	gamma *httptest.Server
+	data  *httptest.Server
+
+	gammaFixtures *fixtures
+	dataFixtures  *fixtures
+
+	mu       sync.Mutex
+	keys     map[string]*apiKey // by API key
+	builders map[string]auth.BuilderConfig
+}
+
+// apiKey is a set of L2 credentials and the address they belong to
+type apiKey struct {
+	creds   types.ApiKeyCreds
+	address common.Address
+	nonce   uint64
+}
+
+// New starts the fake servers. Call Close when done.
+func New(options *Options) *Server {
+	s := &Server{
+		keys:          make(map[string]*apiKey),
+		builders:      make(map[string]auth.BuilderConfig),
+		gammaFixtures: newFixtures(),
+		dataFixtures:  newFixtures(),
+	}
+	if options != nil {
+		s.options = *options
+	}
+	if s.options.ChainID == 0 {
+		s.options.ChainID = types.ChainAmoy
+	}
+	if s.options.Now == nil {
+		s.options.Now = time.Now
+	}
+	if s.options.Balance == "" {
+		s.options.Balance = "1000000000"
+	}
+
+	s.exchange = newExchange(s.options.Now)
+	s.hub = newHub(s.exchange, s.authUser)
+	s.clob = httptest.NewServer(s.clobHandler())
+	s.gamma = httptest.NewServer(s.gammaFixtures)
+	s.data = httptest.NewServer(s.dataFixtures)
+	return s
+}
+
+// Close closes every WebSocket connection and shuts the servers down
+func (s *Server) Close() {
+	s.hub.closeAll()
+	s.clob.Close()
+	s.gamma.Close()
+	s.data.Close()
+}
+
+// CLOBURL returns the base URL of the CLOB REST API, for ClientConfig.Host
+func (s *Server) CLOBURL() string {
+	return s.clob.URL
+}
+
+// WebSocketURL returns the base URL of the CLOB WebSocket API, for
+// WebSocketClientOptions.URL
+func (s *Server) WebSocketURL() string {
+	return "ws" + strings.TrimPrefix(s.clob.URL, "http")
+}
+
+// GammaURL returns the base URL of the Gamma API
+func (s *Server) GammaURL() string {
+	return s.gamma.URL
+}
+
+// DataURL returns the base URL of the Data API
+func (s *Server) DataURL() string {
+	return s.data.URL
+}
+
+// Transport returns a RoundTripper that sends requests for the production
+// CLOB, Gamma and Data API hosts to the fakes instead, for code that builds
+// its clients with the default base URLs:
+//
+//	sdk := data.NewDataSDK(&data.DataSDKConfig{Transport: srv.Transport()})
+func (s *Server) Transport() http.RoundTripper {
+	hosts := make(map[string]*url.URL)
+	for host, target := range map[string]string{
+		"clob.polymarket.com":      s.clob.URL,
+		"gamma-api.polymarket.com": s.gamma.URL,
+		"data-api.polymarket.com":  s.data.URL,
+	} {
+		hosts[host], _ = url.Parse(target)
+	}
+	return &rewriteTransport{hosts: hosts, base: http.DefaultTransport}
+}
+
+// rewriteTransport redirects requests by host
+type rewriteTransport struct {
+	hosts map[string]*url.URL
+	base  http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if target, ok := t.hosts[r.URL.Host]; ok {
+		r = r.Clone(r.Context())
+		r.URL.Scheme, r.URL.Host, r.Host = target.Scheme, target.Host, target.Host
+	}
+	return t.base.RoundTrip(r)
+}
+
+// AddMarket lists m on the CLOB with an empty book for each of its tokens
+func (s *Server) AddMarket(m Market) {
+	s.exchange.addMarket(m)
+}
+
+// PlaceLimit rests a GTC order of owner's on the book, matching it first
+// against crossing orders like any other. Use it to seed liquidity.
+func (s *Server) PlaceLimit(owner, tokenID string, side types.Side, price, size float64) (string, error) {
+	result, err := s.exchange.place(orderRequest{
+		owner:     owner,
+		tokenID:   tokenID,
+		side:      side,
+		price:     price,
+		size:      size,
+		orderType: types.OrderTypeGTC,
+	})
+	if err != nil {
+		return "", err
+	}
+	s.hub.publish(result.events)
+	return result.order.id, nil
+}
+
+// OrderBook returns the current book of tokenID
+func (s *Server) OrderBook(tokenID string) (types.OrderBookSummary, error) {
+	return s.exchange.summary(tokenID)
+}
+
+// Trades returns every trade executed so far, from the taker's side
+func (s *Server) Trades() []types.Trade {
+	return s.exchange.allTrades()
+}
+
+// RegisterAPIKey makes creds valid L2 credentials for address, as if they had
+// been created with nonce 0
+func (s *Server) RegisterAPIKey(address string, creds types.ApiKeyCreds) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[creds.Key] = &apiKey{creds: creds, address: common.HexToAddress(address)}
+}
+
+// RegisterBuilder accepts requests carrying builder headers made with config.
+// Builder headers with an unregistered key are rejected.
+func (s *Server) RegisterBuilder(config auth.BuilderConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.builders[config.APIKey] = config
+}
+
+// Publish sends msg to every market channel subscriber of assetID, or to all
+// of them when assetID is empty. Use it for events the fake exchange does not
+// generate itself, such as tick size changes or resolutions.
+func (s *Server) Publish(assetID string, msg any) {
+	s.hub.publish([]marketEvent{{assetID: assetID, message: msg}})
+}
+
+// DropWebSockets closes every WebSocket connection, as a server restart would
+func (s *Server) DropWebSockets() {
+	s.hub.closeAll()
+}
+
+// StallWebSockets stops answering PINGs while stalled is true, as a half-open
+// connection would, so that clients hit their PONG timeout
+func (s *Server) StallWebSockets(stalled bool) {
+	s.hub.stalled.Store(stalled)
+}
+
+// SetGamma serves v as JSON on path of the Gamma API. Slices are paged by the
+// limit and offset query parameters; an http.Handler handles the request
+// itself.
+func (s *Server) SetGamma(path string, v any) {
+	s.gammaFixtures.set(path, v)
+}
+
+// SetData serves v on path of the Data API, like SetGamma
+func (s *Server) SetData(path string, v any) {
+	s.dataFixtures.set(path, v)
+}
+
+// fixtures serves registered values by path
+type fixtures struct {
+	mu     sync.RWMutex
+	routes map[string]any
+}
+
+func newFixtures() *fixtures {
+	return &fixtures{routes: make(map[string]any)}
+}
+
+func (f *fixtures) set(path string, v any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routes[path] = v
+}
+
+func (f *fixtures) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.RLock()
+	v, ok := f.routes[r.URL.Path]
+	f.mu.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if h, ok := v.(http.Handler); ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+	writeJSON(w, http.StatusOK, page(v, r.URL.Query()))
+}
+
+// page applies the limit and offset query parameters to slices
+func page(v any, query url.Values) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v
+	}
+	n := rv.Len()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	start, end := min(max(offset, 0), n), n
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit >= 0 {
+		end = min(start+limit, n)
+	}
+	return rv.Slice(start, end).Interface()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError replies with the API's error body
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
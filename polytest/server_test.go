@@ -0,0 +1,552 @@
+package polytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ybina/polymarket-sdk-go/auth"
+	"github.com/ybina/polymarket-sdk-go/client"
+	"github.com/ybina/polymarket-sdk-go/data"
+	"github.com/ybina/polymarket-sdk-go/gamma"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+const (
+	testCondition = "0xc0nd"
+	yesToken      = "1111"
+	noToken       = "2222"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	srv := New(nil)
+	t.Cleanup(srv.Close)
+	srv.AddMarket(Market{
+		ConditionID: testCondition,
+		Tokens:      []Token{{TokenID: yesToken, Outcome: "Yes"}, {TokenID: noToken, Outcome: "No"}},
+	})
+	return srv
+}
+
+// trader is a wallet with L2 credentials on srv
+type trader struct {
+	wallet *auth.Wallet
+	creds  *types.ApiKeyCreds
+	client *client.ClobClient
+}
+
+func newTrader(t *testing.T, srv *Server) *trader {
+	t.Helper()
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1, err := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet.GetPrivateKey())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds, err := l1.CreateApiKey(nil)
+	if err != nil {
+		t.Fatalf("CreateApiKey() error = %v", err)
+	}
+	l2, err := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet.GetPrivateKey()), APIKey: creds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &trader{wallet: wallet, creds: creds, client: l2}
+}
+
+// do sends an L2 authenticated request and decodes the response into result
+func (tr *trader) do(t *testing.T, srv *Server, method, path string, payload, result any) int {
+	t.Helper()
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+	headers, err := auth.CreateL2Headers(tr.wallet.GetPrivateKey(), tr.creds, &types.L2HeaderArgs{Method: method, RequestPath: path, Body: string(body)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(method, srv.CLOBURL()+path, bytes.NewReader(body))
+	req.Header.Set("POLY_ADDRESS", headers.POLYAddress)
+	req.Header.Set("POLY_SIGNATURE", headers.POLYSignature)
+	req.Header.Set("POLY_TIMESTAMP", headers.POLYTimestamp)
+	req.Header.Set("POLY_API_KEY", headers.POLYAPIKey)
+	req.Header.Set("POLY_PASSPHRASE", headers.POLYPassphrase)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if result != nil {
+		if err := json.Unmarshal(data, result); err != nil {
+			t.Fatalf("%s %s: decoding %s: %v", method, path, data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// order builds an order of tr's for size tokens at price
+func (tr *trader) order(tokenID string, side types.Side, price, size float64, orderType types.OrderType) types.NewOrder {
+	tokens := big.NewInt(int64(size * 1e6))
+	usdc := big.NewInt(int64(size * price * 1e6))
+	maker, taker := usdc, tokens
+	if side == types.SideSell {
+		maker, taker = tokens, usdc
+	}
+	address := tr.wallet.GetAddressHex()
+	order := types.SignedOrder{
+		Maker:       address,
+		Signer:      address,
+		Taker:       common.Address{}.Hex(),
+		TokenID:     tokenID,
+		MakerAmount: maker,
+		TakerAmount: taker,
+		Side:        side,
+	}
+	contracts, _ := types.GetContractConfig(types.ChainAmoy)
+	order.Signature, _ = auth.SignOrder(tr.wallet.GetPrivateKey(), &order, int64(types.ChainAmoy), contracts.Exchange)
+	return types.NewOrder{Order: order, Owner: tr.creds.Key, OrderType: orderType}
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	srv := newTestServer(t)
+	tr := newTrader(t, srv)
+
+	wallet := tr.wallet.GetPrivateKey()
+	l1, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet)})
+	if _, err := l1.CreateApiKey(nil); err == nil {
+		t.Error("CreateApiKey() twice with the same nonce should fail")
+	}
+	derived, err := l1.DeriveApiKey(nil)
+	if err != nil || *derived != *tr.creds {
+		t.Errorf("DeriveApiKey() = %+v, %v, want %+v", derived, err, tr.creds)
+	}
+
+	otherChain, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainPolygon, PrivateKey: auth.PrivateKeyToHex(wallet)})
+	if _, err := otherChain.DeriveApiKey(nil); err == nil {
+		t.Error("DeriveApiKey() signed for another chain should fail")
+	}
+
+	keys, err := tr.client.GetApiKeys()
+	if err != nil || len(keys.APIKeys) != 1 || keys.APIKeys[0] != tr.creds.Key {
+		t.Errorf("GetApiKeys() = %+v, %v", keys, err)
+	}
+
+	forged := *tr.creds
+	forged.Secret = "c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0MTI="
+	forger, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet), APIKey: &forged})
+	if _, err := forger.GetApiKeys(); err != nil {
+		t.Errorf("GetApiKeys() with the wrong secret should derive the key again, error = %v", err)
+	}
+	if creds := forger.Credentials(); creds == nil || *creds != *tr.creds {
+		t.Errorf("Credentials() after a rejected secret = %+v, want %+v", creds, tr.creds)
+	}
+
+	// Deleting never derives a key only to delete it, nor retries another one
+	transport := &countingTransport{counts: map[string]int{}}
+	keyless, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, Wallet: tr.wallet, Transport: transport})
+	if _, err := keyless.DeleteApiKey(); err == nil {
+		t.Error("DeleteApiKey() without credentials should fail")
+	}
+	rejected, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet), APIKey: &forged, Transport: transport})
+	if _, err := rejected.DeleteApiKey(); err == nil {
+		t.Error("DeleteApiKey() with rejected credentials should fail")
+	}
+	if len(transport.counts) != 1 || transport.counts[client.DeleteApiKey] != 1 {
+		t.Errorf("DeleteApiKey() sent %v, want one DELETE", transport.counts)
+	}
+
+	if _, err := tr.client.DeleteApiKey(); err != nil {
+		t.Fatalf("DeleteApiKey() error = %v", err)
+	}
+	if tr.client.Credentials() != nil {
+		t.Error("DeleteApiKey() kept the deleted credentials")
+	}
+	keys, err = tr.client.GetApiKeys()
+	if err != nil || len(keys.APIKeys) != 1 || keys.APIKeys[0] == tr.creds.Key {
+		t.Errorf("GetApiKeys() after DeleteApiKey() = %+v, %v, want a new key", keys, err)
+	}
+}
+
+// countingTransport counts the requests sent per path
+type countingTransport struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.counts[r.URL.Path]++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestDeriveApiKeyCache(t *testing.T) {
+	srv := newTestServer(t)
+	tr := newTrader(t, srv)
+
+	transport := &countingTransport{counts: map[string]int{}}
+	c, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, Wallet: tr.wallet, Transport: transport})
+	if _, err := c.GetApiKeys(); err != nil {
+		t.Errorf("GetApiKeys() without credentials should derive them, error = %v", err)
+	}
+	for range 2 {
+		derived, err := c.DeriveApiKey(nil)
+		if err != nil || *derived != *tr.creds {
+			t.Fatalf("DeriveApiKey() = %+v, %v, want %+v", derived, err, tr.creds)
+		}
+	}
+	if n := transport.counts[client.DeriveApiKey]; n != 1 {
+		t.Errorf("DeriveApiKey() sent %d requests, want 1", n)
+	}
+	if creds := c.Credentials(); creds == nil || *creds != *tr.creds {
+		t.Errorf("Credentials() = %+v, want the derived key", creds)
+	}
+	if _, err := c.GetApiKeys(); err != nil {
+		t.Errorf("GetApiKeys() with derived credentials error = %v", err)
+	}
+
+	c.Close()
+	if _, err := c.GetApiKeys(); err == nil {
+		t.Error("GetApiKeys() after Close() should fail")
+	}
+	if tr.wallet.GetPrivateKey().D.Sign() != 0 {
+		t.Error("Close() should zero the configured wallet")
+	}
+}
+
+func TestApiKeyRotation(t *testing.T) {
+	srv := newTestServer(t)
+	wallet, err := auth.NewRandomWallet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, Wallet: wallet})
+
+	// Without a key to derive, the first authenticated call creates one
+	if _, err := c.GetOpenOrders(nil); err != nil {
+		t.Fatalf("GetOpenOrders() without credentials error = %v", err)
+	}
+	old := c.Credentials()
+	if old == nil {
+		t.Fatal("GetOpenOrders() did not set credentials")
+	}
+
+	rotated, err := c.RotateApiKey(nil)
+	if err != nil {
+		t.Fatalf("RotateApiKey() error = %v", err)
+	}
+	if rotated.Key == old.Key || *c.Credentials() != *rotated {
+		t.Errorf("RotateApiKey() = %+v, Credentials() = %+v, old key %s", rotated, c.Credentials(), old.Key)
+	}
+	keys, err := c.GetApiKeys()
+	if err != nil || len(keys.APIKeys) != 1 || keys.APIKeys[0] != rotated.Key {
+		t.Errorf("GetApiKeys() after RotateApiKey() = %+v, %v, want only %s", keys, err, rotated.Key)
+	}
+
+	// A client still holding the deleted key recovers on the next call
+	stale, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet.GetPrivateKey()), APIKey: old, APIKeyNonce: c.ApiKeyNonce()})
+	if _, err := stale.GetOpenOrders(nil); err != nil {
+		t.Errorf("GetOpenOrders() with a rotated key error = %v", err)
+	}
+
+	// A restarted client given the persisted nonce derives the rotated key
+	restarted, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, Wallet: wallet, APIKeyNonce: c.ApiKeyNonce()})
+	if _, err := restarted.GetOpenOrders(nil); err != nil || *restarted.Credentials() != *rotated {
+		t.Errorf("restarted client: Credentials() = %+v, %v, want %+v", restarted.Credentials(), err, rotated)
+	}
+
+	// Rotating from a nonce whose successor has a key skips to a free one
+	forgetful, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, Wallet: wallet})
+	if _, err := forgetful.CreateApiKey(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := forgetful.RotateApiKey(nil); err != nil || forgetful.ApiKeyNonce() != 2 {
+		t.Errorf("RotateApiKey() with nonce 1 taken = %v, ApiKeyNonce() = %d, want 2", err, forgetful.ApiKeyNonce())
+	}
+}
+
+func TestRejectsSkewedTimestamps(t *testing.T) {
+	srv := New(&Options{
+		Now:          func() time.Time { return time.Now().Add(time.Hour) },
+		MaxClockSkew: 30 * time.Second,
+	})
+	defer srv.Close()
+
+	wallet, _ := auth.NewRandomWallet()
+	c, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, PrivateKey: auth.PrivateKeyToHex(wallet.GetPrivateKey())})
+	if _, err := c.CreateApiKey(nil); err == nil {
+		t.Fatal("CreateApiKey() with the local clock should fail")
+	}
+
+	transport := &countingTransport{counts: map[string]int{}}
+	var drift time.Duration
+	c, _ = client.NewClobClient(&client.ClientConfig{
+		Host:          srv.CLOBURL(),
+		ChainID:       types.ChainAmoy,
+		PrivateKey:    auth.PrivateKeyToHex(wallet.GetPrivateKey()),
+		UseServerTime: true,
+		Clock:         &client.ClockOptions{OnDrift: func(offset time.Duration) { drift = offset }},
+		Transport:     transport,
+	})
+	if _, err := c.CreateApiKey(nil); err != nil {
+		t.Fatalf("CreateApiKey() with the server clock error = %v", err)
+	}
+	for range 3 {
+		if _, err := c.GetOpenOrders(nil); err != nil {
+			t.Fatalf("GetOpenOrders() with the server clock error = %v", err)
+		}
+	}
+	if n := transport.counts[client.Time]; n != 1 {
+		t.Errorf("fetched the server time %d times, want 1", n)
+	}
+	if offset := c.Clock().Offset(); (offset-time.Hour).Abs() > 2*time.Second || drift != offset {
+		t.Errorf("Clock().Offset() = %v, OnDrift() got %v, want about 1h", offset, drift)
+	}
+
+	// Builder headers follow the server clock too
+	srv.AddMarket(Market{ConditionID: testCondition, Tokens: []Token{{TokenID: yesToken, Outcome: "Yes"}}})
+	builder := &auth.BuilderConfig{APIKey: "builder", Secret: "c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0MTI=", Passphrase: "pass"}
+	srv.RegisterBuilder(*builder)
+	c, _ = client.NewClobClient(&client.ClientConfig{
+		Host:          srv.CLOBURL(),
+		ChainID:       types.ChainAmoy,
+		PrivateKey:    auth.PrivateKeyToHex(wallet.GetPrivateKey()),
+		UseServerTime: true,
+		BuilderConfig: builder,
+	})
+	if _, err := c.CreateAndPostOrder(&types.UserOrder{TokenID: yesToken, Price: 0.45, Size: 10, Side: types.SideBuy}, nil, types.OrderTypeGTC); err != nil {
+		t.Errorf("CreateAndPostOrder() with builder headers and the server clock error = %v", err)
+	}
+}
+
+func TestOrderMatching(t *testing.T) {
+	srv := newTestServer(t)
+	maker := newTrader(t, srv)
+	taker := newTrader(t, srv)
+
+	for _, o := range []types.NewOrder{
+		maker.order(yesToken, types.SideSell, 0.53, 10, types.OrderTypeGTC),
+		maker.order(yesToken, types.SideSell, 0.52, 10, types.OrderTypeGTC),
+	} {
+		var resp types.OrderResponse
+		if status := maker.do(t, srv, "POST", client.PostOrder, o, &resp); status != http.StatusOK || resp.Status != "live" {
+			t.Fatalf("POST /order = %d %+v", status, resp)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		order      types.NewOrder
+		wantStatus int
+		wantResult string
+		wantMaking string
+		wantAsks   []types.OrderSummary
+	}{
+		{
+			name:       "FOK sweeps two levels at the maker prices",
+			order:      taker.order(yesToken, types.SideBuy, 0.53, 15, types.OrderTypeFOK),
+			wantStatus: http.StatusOK,
+			wantResult: "matched",
+			wantMaking: "7.85",
+			wantAsks:   []types.OrderSummary{{Price: "0.53", Size: "5"}},
+		},
+		{
+			name:       "FOK that cannot fill is killed",
+			order:      taker.order(yesToken, types.SideBuy, 0.53, 10, types.OrderTypeFOK),
+			wantStatus: http.StatusBadRequest,
+			wantAsks:   []types.OrderSummary{{Price: "0.53", Size: "5"}},
+		},
+		{
+			name:       "price off the tick is rejected",
+			order:      taker.order(yesToken, types.SideBuy, 0.505, 10, types.OrderTypeGTC),
+			wantStatus: http.StatusBadRequest,
+			wantAsks:   []types.OrderSummary{{Price: "0.53", Size: "5"}},
+		},
+		{
+			name:       "GTC takes what crosses and rests the rest",
+			order:      taker.order(yesToken, types.SideBuy, 0.53, 8, types.OrderTypeGTC),
+			wantStatus: http.StatusOK,
+			wantResult: "live",
+			wantMaking: "2.65",
+			wantAsks:   []types.OrderSummary{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp types.OrderResponse
+			status := taker.do(t, srv, "POST", client.PostOrder, tt.order, &resp)
+			if status != tt.wantStatus || resp.Status != tt.wantResult || resp.MakingAmount != tt.wantMaking && tt.wantStatus == http.StatusOK {
+				t.Errorf("POST /order = %d %+v", status, resp)
+			}
+			book, _ := srv.OrderBook(yesToken)
+			if fmt.Sprint(book.Asks) != fmt.Sprint(tt.wantAsks) {
+				t.Errorf("asks = %v, want %v", book.Asks, tt.wantAsks)
+			}
+		})
+	}
+
+	book, _ := srv.OrderBook(yesToken)
+	if len(book.Bids) != 1 || book.Bids[0] != (types.OrderSummary{Price: "0.53", Size: "3"}) {
+		t.Errorf("bids = %v, want 3 @ 0.53", book.Bids)
+	}
+
+	trades, err := taker.client.GetTrades(nil, false, "")
+	if err != nil || len(trades) != 3 {
+		t.Fatalf("GetTrades() = %d trades, %v, want 3", len(trades), err)
+	}
+	makerTrades, _ := maker.client.GetTrades(nil, false, "")
+	if len(makerTrades) != 3 || makerTrades[0].TraderSide != "MAKER" {
+		t.Errorf("maker GetTrades() = %+v", makerTrades)
+	}
+
+	resting := trades[2].TakerOrderID
+	order, err := taker.client.GetOrder(resting)
+	if err != nil || order.Status != "LIVE" || order.SizeMatched != "5" || order.OriginalSize != "8" {
+		t.Errorf("GetOrder() = %+v, %v", order, err)
+	}
+
+	var canceled types.CancelOrdersResponse
+	taker.do(t, srv, "DELETE", client.CancelOrder, types.OrderPayload{OrderID: resting}, &canceled)
+	if len(canceled.Canceled) != 1 || canceled.Canceled[0] != resting {
+		t.Errorf("DELETE /order = %+v", canceled)
+	}
+	if book, _ := srv.OrderBook(yesToken); len(book.Bids) != 0 {
+		t.Errorf("bids after cancel = %v", book.Bids)
+	}
+}
+
+func TestOrderOwnerMustMatchAPIKey(t *testing.T) {
+	srv := newTestServer(t)
+	alice, bob := newTrader(t, srv), newTrader(t, srv)
+
+	var resp types.OrderResponse
+	if status := bob.do(t, srv, "POST", client.PostOrder, alice.order(yesToken, types.SideBuy, 0.5, 10, types.OrderTypeGTC), &resp); status != http.StatusBadRequest {
+		t.Errorf("POST /order with another owner = %d %+v", status, resp)
+	}
+}
+
+func TestOrderSignatureIsVerified(t *testing.T) {
+	srv := newTestServer(t)
+	tr := newTrader(t, srv)
+
+	tampered := tr.order(yesToken, types.SideBuy, 0.5, 10, types.OrderTypeGTC)
+	tampered.Order.TakerAmount = big.NewInt(20e6)
+	unsigned := tr.order(yesToken, types.SideBuy, 0.5, 10, types.OrderTypeGTC)
+	unsigned.Order.Signature = ""
+
+	for name, o := range map[string]types.NewOrder{"tampered": tampered, "unsigned": unsigned} {
+		var resp types.OrderResponse
+		if status := tr.do(t, srv, "POST", client.PostOrder, o, &resp); status != http.StatusBadRequest || resp.ErrorMsg != "invalid order signature" {
+			t.Errorf("POST /order %s = %d %+v", name, status, resp)
+		}
+	}
+}
+
+func TestClientOrders(t *testing.T) {
+	srv := newTestServer(t)
+	tr := newTrader(t, srv)
+
+	resp, err := tr.client.CreateAndPostOrder(&types.UserOrder{TokenID: yesToken, Price: 0.45, Size: 12.5, Side: types.SideBuy}, nil, types.OrderTypeGTC)
+	if err != nil || !resp.Success || resp.Status != "live" {
+		t.Fatalf("CreateAndPostOrder() = %+v, %v", resp, err)
+	}
+	if book, _ := srv.OrderBook(yesToken); len(book.Bids) != 1 || book.Bids[0] != (types.OrderSummary{Price: "0.45", Size: "12.5"}) {
+		t.Errorf("bids = %v, want 12.5 @ 0.45", book.Bids)
+	}
+
+	open, err := tr.client.GetOpenOrders(nil)
+	if err != nil || len(open) != 1 || open[0].ID != resp.OrderID {
+		t.Fatalf("GetOpenOrders() = %+v, %v", open, err)
+	}
+
+	canceled, err := tr.client.CancelOrders([]string{resp.OrderID, "missing"})
+	if err != nil || len(canceled.Canceled) != 1 || canceled.NotCanceled["missing"] == "" {
+		t.Errorf("CancelOrders() = %+v, %v", canceled, err)
+	}
+	if canceled, err := tr.client.CancelAll(); err != nil || len(canceled.Canceled) != 0 {
+		t.Errorf("CancelAll() = %+v, %v", canceled, err)
+	}
+}
+
+func TestMarketWebSocket(t *testing.T) {
+	srv := newTestServer(t)
+	if _, err := srv.PlaceLimit("mm", yesToken, types.SideBuy, 0.4, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	ws := client.NewWebSocketClient(nil, &client.WebSocketClientOptions{URL: srv.WebSocketURL(), AssetIDs: []string{yesToken}})
+	messages := ws.Messages()
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer ws.Disconnect()
+
+	next := func() types.MarketChannelMessage {
+		t.Helper()
+		select {
+		case msg := <-messages:
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a message")
+			return nil
+		}
+	}
+
+	book, ok := next().(*types.BookMessage)
+	if !ok || len(book.Bids) != 1 || book.Bids[0].Price != "0.4" {
+		t.Fatalf("first message = %+v, want the book", book)
+	}
+
+	if _, err := srv.PlaceLimit("mm", noToken, types.SideBuy, 0.4, 100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.PlaceLimit("taker", yesToken, types.SideSell, 0.4, 30); err != nil {
+		t.Fatal(err)
+	}
+	trade, ok := next().(*types.LastTradePriceMessage)
+	if !ok || trade.Price != "0.4" || trade.Size != "30" || trade.Side != types.SideSell {
+		t.Fatalf("second message = %+v, want the trade on %s only", trade, yesToken)
+	}
+	change, ok := next().(*types.PriceChangeMessage)
+	if !ok || len(change.PriceChanges) != 1 || change.PriceChanges[0].Size != "70" {
+		t.Fatalf("third message = %+v, want the price change", change)
+	}
+}
+
+func TestTransportServesFixtures(t *testing.T) {
+	srv := New(nil)
+	defer srv.Close()
+
+	user := "0xabc"
+	srv.SetData("/positions", []data.Position{{Asset: "1"}, {Asset: "2"}, {Asset: "3"}})
+	srv.SetGamma("/markets", []gamma.Market{{ID: "1"}, {ID: "2"}})
+
+	dataSDK := data.NewDataSDK(&data.DataSDKConfig{BaseURL: srv.DataURL()})
+	limit, offset := 2, 1
+	positions, err := dataSDK.GetCurrentPositions(&data.PositionsQuery{User: &user, Limit: &limit, Offset: &offset})
+	if err != nil || len(positions) != 2 || positions[0].Asset != "2" {
+		t.Errorf("GetCurrentPositions() = %+v, %v", positions, err)
+	}
+
+	gammaSDK := gamma.NewGammaSDK(&gamma.GammaSDKConfig{Transport: srv.Transport()})
+	markets, err := gammaSDK.GetMarkets(nil)
+	if err != nil || len(markets) != 2 {
+		t.Errorf("GetMarkets() = %+v, %v", markets, err)
+	}
+	if market, err := gammaSDK.GetMarketById(1, nil); market != nil || err != nil {
+		t.Errorf("GetMarketById() without a fixture = %+v, %v, want not found", market, err)
+	}
+}
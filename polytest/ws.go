@@ -0,0 +1,219 @@
+package polytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const wsWriteTimeout = 5 * time.Second
+
+// hub tracks market and user channel connections and fans market events out
+type hub struct {
+	exchange *exchange
+	upgrader websocket.Upgrader
+	stalled  atomic.Bool // stop answering PINGs
+
+	// authenticate checks the credentials of a user channel subscription
+	authenticate func(auth userAuth) bool
+
+	mu    sync.Mutex
+	conns map[*wsConn]struct{}
+}
+
+// wsConn is one market or user channel subscriber
+type wsConn struct {
+	conn    *websocket.Conn
+	user    bool
+	writeMu sync.Mutex
+
+	mu     sync.Mutex
+	assets map[string]bool
+}
+
+// subscription is a market channel subscribe or unsubscribe message
+type subscription struct {
+	AssetIDs  []string `json:"assets_ids"`
+	Type      string   `json:"type"`
+	Operation string   `json:"operation"`
+}
+
+// userSubscription is the user channel subscribe message
+type userSubscription struct {
+	Markets []string `json:"markets"`
+	Type    string   `json:"type"`
+	Auth    userAuth `json:"auth"`
+}
+
+// userAuth is the L2 credentials a user channel subscription carries
+type userAuth struct {
+	APIKey     string `json:"apiKey"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase"`
+}
+
+func newHub(e *exchange, authenticate func(userAuth) bool) *hub {
+	return &hub{
+		exchange:     e,
+		upgrader:     websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+		authenticate: authenticate,
+		conns:        make(map[*wsConn]struct{}),
+	}
+}
+
+// ServeHTTP upgrades the request and serves the market channel on it until
+// the client goes away
+func (h *hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := h.accept(w, r, false)
+	if c == nil {
+		return
+	}
+	defer h.release(c)
+
+	for {
+		data, ok := h.read(c)
+		if !ok {
+			return
+		}
+
+		var sub subscription
+		if err := json.Unmarshal(data, &sub); err != nil {
+			continue
+		}
+		switch {
+		case sub.Type == "market" || sub.Operation == "subscribe":
+			c.mu.Lock()
+			for _, id := range sub.AssetIDs {
+				c.assets[id] = true
+			}
+			c.mu.Unlock()
+			if books := h.exchange.snapshots(sub.AssetIDs); len(books) > 0 && c.writeJSON(books) != nil {
+				return
+			}
+		case sub.Operation == "unsubscribe":
+			c.mu.Lock()
+			for _, id := range sub.AssetIDs {
+				delete(c.assets, id)
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// serveUser upgrades the request and serves the user channel on it. The first
+// message must subscribe with valid credentials, or the connection is closed
+// with a policy violation. The fake generates no user events.
+func (h *hub) serveUser(w http.ResponseWriter, r *http.Request) {
+	c := h.accept(w, r, true)
+	if c == nil {
+		return
+	}
+	defer h.release(c)
+
+	data, ok := h.read(c)
+	if !ok {
+		return
+	}
+	var sub userSubscription
+	if err := json.Unmarshal(data, &sub); err != nil || sub.Type != "user" || !h.authenticate(sub.Auth) {
+		c.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "invalid api key"))
+		return
+	}
+	for {
+		if _, ok := h.read(c); !ok {
+			return
+		}
+	}
+}
+
+// accept upgrades the request and registers the connection
+func (h *hub) accept(w http.ResponseWriter, r *http.Request, user bool) *wsConn {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil
+	}
+	c := &wsConn{conn: conn, user: user, assets: make(map[string]bool)}
+	h.mu.Lock()
+	h.conns[c] = struct{}{}
+	h.mu.Unlock()
+	return c
+}
+
+// release unregisters and closes c
+func (h *hub) release(c *wsConn) {
+	h.mu.Lock()
+	delete(h.conns, c)
+	h.mu.Unlock()
+	c.conn.Close()
+}
+
+// read returns the next message of c other than a PING, answering PINGs
+// unless the hub is stalled. It returns false once the connection is gone.
+func (h *hub) read(c *wsConn) ([]byte, bool) {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return nil, false
+		}
+		if string(data) != "PING" {
+			return data, true
+		}
+		if !h.stalled.Load() && c.write(websocket.TextMessage, []byte("PONG")) != nil {
+			return nil, false
+		}
+	}
+}
+
+// publish sends each event to the connections subscribed to its asset
+func (h *hub) publish(events []marketEvent) {
+	if len(events) == 0 {
+		return
+	}
+	h.mu.Lock()
+	conns := make([]*wsConn, 0, len(h.conns))
+	for c := range h.conns {
+		if !c.user {
+			conns = append(conns, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		for _, ev := range events {
+			c.mu.Lock()
+			subscribed := ev.assetID == "" || c.assets[ev.assetID]
+			c.mu.Unlock()
+			if subscribed {
+				c.writeJSON(ev.message)
+			}
+		}
+	}
+}
+
+// closeAll closes every connection; their read loops then unregister them
+func (h *hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.conns {
+		c.conn.Close()
+	}
+}
+
+func (c *wsConn) write(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return c.conn.WriteMessage(messageType, data)
+}
+
+func (c *wsConn) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.write(websocket.TextMessage, data)
+}
@@ -0,0 +1,291 @@
+package polytest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ybina/polymarket-sdk-go/client"
+	"github.com/ybina/polymarket-sdk-go/types"
+)
+
+// events collects WebSocket callbacks for assertions from the test goroutine
+type events struct {
+	mu          sync.Mutex
+	books       map[string]int // by asset ID
+	reconnects  []int
+	disconnects []string
+	changed     chan struct{}
+}
+
+func newEvents() *events {
+	return &events{books: make(map[string]int), changed: make(chan struct{}, 1)}
+}
+
+func (e *events) callbacks() *client.WebSocketCallbacks {
+	return &client.WebSocketCallbacks{
+		OnBook: func(msg *types.BookMessage) {
+			e.record(func() { e.books[msg.AssetID]++ })
+		},
+		OnReconnect: func(attempt int) {
+			e.record(func() { e.reconnects = append(e.reconnects, attempt) })
+		},
+		OnDisconnect: func(code int, reason string) {
+			e.record(func() { e.disconnects = append(e.disconnects, reason) })
+		},
+	}
+}
+
+func (e *events) record(update func()) {
+	e.mu.Lock()
+	update()
+	e.mu.Unlock()
+	select {
+	case e.changed <- struct{}{}:
+	default:
+	}
+}
+
+// waitFor blocks until cond holds, checked after every event
+func (e *events) waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		e.mu.Lock()
+		ok := cond()
+		e.mu.Unlock()
+		if ok {
+			return
+		}
+		select {
+		case <-e.changed:
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestWebSocketReconnectResubscribes(t *testing.T) {
+	srv := newTestServer(t)
+	ev := newEvents()
+	ws := client.NewWebSocketClient(nil, &client.WebSocketClientOptions{
+		URL:            srv.WebSocketURL(),
+		AssetIDs:       []string{yesToken},
+		AutoReconnect:  true,
+		ReconnectDelay: 10 * time.Millisecond,
+	}).On(ev.callbacks())
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer ws.Disconnect()
+
+	ev.waitFor(t, "the first book", func() bool { return ev.books[yesToken] == 1 })
+	if err := ws.Subscribe([]string{noToken}); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	ev.waitFor(t, "the book of the added asset", func() bool { return ev.books[noToken] == 1 })
+
+	srv.DropWebSockets()
+	ev.waitFor(t, "both books after reconnecting", func() bool {
+		return ev.books[yesToken] == 2 && ev.books[noToken] == 2
+	})
+	ev.mu.Lock()
+	if len(ev.reconnects) != 1 || ev.reconnects[0] != 1 || len(ev.disconnects) != 1 {
+		t.Errorf("reconnects = %v, disconnects = %v, want one of each", ev.reconnects, ev.disconnects)
+	}
+	ev.mu.Unlock()
+	if state := ws.State(); state != client.StateConnected {
+		t.Errorf("State() = %v, want %v", state, client.StateConnected)
+	}
+}
+
+func TestWebSocketMaxReconnectAttempts(t *testing.T) {
+	srv := newTestServer(t)
+	ev := newEvents()
+	ws := client.NewWebSocketClient(nil, &client.WebSocketClientOptions{
+		URL:                  srv.WebSocketURL(),
+		AssetIDs:             []string{yesToken},
+		AutoReconnect:        true,
+		ReconnectDelay:       time.Millisecond,
+		MaxReconnectAttempts: 3,
+	}).On(ev.callbacks())
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer ws.Disconnect()
+
+	// With the server gone every attempt fails to dial
+	srv.Close()
+	done := make(chan error, 1)
+	go func() { done <- ws.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, client.ErrMaxReconnectAttempts) {
+			t.Errorf("Wait() = %v, want %v", err, client.ErrMaxReconnectAttempts)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the client to give up")
+	}
+	ev.mu.Lock()
+	if len(ev.reconnects) != 3 || ev.reconnects[2] != 3 {
+		t.Errorf("reconnects = %v, want attempts 1 to 3", ev.reconnects)
+	}
+	ev.mu.Unlock()
+	if state := ws.State(); state != client.StateStopped {
+		t.Errorf("State() = %v, want %v", state, client.StateStopped)
+	}
+}
+
+func TestWebSocketPongTimeout(t *testing.T) {
+	srv := newTestServer(t)
+	ev := newEvents()
+	ws := client.NewWebSocketClient(nil, &client.WebSocketClientOptions{
+		URL:            srv.WebSocketURL(),
+		AssetIDs:       []string{yesToken},
+		AutoReconnect:  true,
+		ReconnectDelay: 10 * time.Millisecond,
+		PingInterval:   20 * time.Millisecond,
+		PongTimeout:    100 * time.Millisecond,
+	}).On(ev.callbacks())
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer ws.Disconnect()
+	ev.waitFor(t, "the first book", func() bool { return ev.books[yesToken] == 1 })
+
+	// PONGs keep a quiet connection alive past the timeout
+	time.Sleep(300 * time.Millisecond)
+	ev.mu.Lock()
+	if len(ev.disconnects) != 0 {
+		t.Fatalf("disconnects = %v with PONGs flowing, want none", ev.disconnects)
+	}
+	ev.mu.Unlock()
+
+	srv.StallWebSockets(true)
+	ev.waitFor(t, "the PONG timeout", func() bool { return len(ev.disconnects) > 0 })
+	srv.StallWebSockets(false)
+	ev.mu.Lock()
+	if ev.disconnects[0] != "PONG timeout" {
+		t.Errorf("disconnect reason = %q, want PONG timeout", ev.disconnects[0])
+	}
+	ev.mu.Unlock()
+	ev.waitFor(t, "the book after reconnecting", func() bool { return ev.books[yesToken] >= 2 })
+}
+
+func TestWebSocketReconnectAfterDisconnect(t *testing.T) {
+	srv := newTestServer(t)
+
+	// Hold the read loop of the first connection inside a callback so it only
+	// ends once the next session is connected
+	var once sync.Once
+	blocked, release := make(chan struct{}), make(chan struct{})
+	ws := client.NewWebSocketClient(nil, &client.WebSocketClientOptions{URL: srv.WebSocketURL(), AssetIDs: []string{yesToken}})
+	ws.On(&client.WebSocketCallbacks{
+		OnBook: func(*types.BookMessage) {
+			once.Do(func() {
+				close(blocked)
+				<-release
+			})
+		},
+	})
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer ws.Disconnect()
+	<-blocked
+
+	ws.Disconnect()
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() after Disconnect() error = %v", err)
+	}
+	messages := ws.Messages()
+	close(release)
+
+	// The stale read loop has ended by the time a new message arrives
+	// through the new connection
+	time.Sleep(50 * time.Millisecond)
+	if _, err := srv.PlaceLimit("mm", yesToken, types.SideBuy, 0.5, 10); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-messages:
+		if !ok {
+			t.Fatalf("Messages() closed, Err() = %v", ws.Err())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message on the new session")
+	}
+	if err := ws.Err(); err != nil || ws.State() != client.StateConnected || !ws.IsConnected() {
+		t.Errorf("after the stale connection closed: Err() = %v, State() = %v, IsConnected() = %v", err, ws.State(), ws.IsConnected())
+	}
+}
+
+func TestWebSocketChannelCredentials(t *testing.T) {
+	srv := newTestServer(t)
+	tr := newTrader(t, srv)
+
+	transport := &countingTransport{counts: map[string]int{}}
+	c, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, Wallet: tr.wallet, Transport: transport})
+
+	// The market channel is public: connecting never derives credentials
+	market := newEvents()
+	ws := client.NewWebSocketClient(c, &client.WebSocketClientOptions{URL: srv.WebSocketURL(), AssetIDs: []string{yesToken}}).On(market.callbacks())
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() to the market channel error = %v", err)
+	}
+	market.waitFor(t, "the first book", func() bool { return market.books[yesToken] == 1 })
+	ws.Disconnect()
+	if n := transport.counts[client.DeriveApiKey] + transport.counts[client.CreateApiKey]; n != 0 || c.Credentials() != nil {
+		t.Errorf("market channel sent %d key requests, Credentials() = %+v, want none", n, c.Credentials())
+	}
+
+	// The user channel derives them on connect and authenticates with them
+	user := newEvents()
+	ws = client.NewWebSocketClient(c, &client.WebSocketClientOptions{
+		URL:          srv.WebSocketURL(),
+		Channel:      client.ChannelUser,
+		Markets:      []string{testCondition},
+		PingInterval: 20 * time.Millisecond,
+	}).On(user.callbacks())
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() to the user channel error = %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	user.mu.Lock()
+	if len(user.disconnects) != 0 {
+		t.Errorf("user channel disconnected: %v", user.disconnects)
+	}
+	user.mu.Unlock()
+	ws.Disconnect()
+	if n := transport.counts[client.DeriveApiKey]; n != 1 {
+		t.Errorf("user channel sent %d derive requests, want 1", n)
+	}
+	if creds := c.Credentials(); creds == nil || *creds != *tr.creds {
+		t.Errorf("Credentials() = %+v, want %+v", creds, tr.creds)
+	}
+
+	// Without a wallet or credentials the user channel cannot connect
+	ws = client.NewWebSocketClient(nil, &client.WebSocketClientOptions{URL: srv.WebSocketURL(), Channel: client.ChannelUser})
+	if err := ws.Connect(); err == nil {
+		ws.Disconnect()
+		t.Error("Connect() to the user channel without a CLOB client should fail")
+	}
+
+	// Credentials the server does not know are rejected
+	forged := *tr.creds
+	forged.Secret = "Zm9yZ2Vk"
+	keyed, _ := client.NewClobClient(&client.ClientConfig{Host: srv.CLOBURL(), ChainID: types.ChainAmoy, APIKey: &forged})
+	rejected := newEvents()
+	ws = client.NewWebSocketClient(keyed, &client.WebSocketClientOptions{URL: srv.WebSocketURL(), Channel: client.ChannelUser}).On(rejected.callbacks())
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() with forged credentials error = %v", err)
+	}
+	defer ws.Disconnect()
+	rejected.waitFor(t, "the server to close the connection", func() bool { return len(rejected.disconnects) > 0 })
+	rejected.mu.Lock()
+	if rejected.disconnects[0] != "invalid api key" {
+		t.Errorf("disconnect reason = %q, want invalid api key", rejected.disconnects[0])
+	}
+	rejected.mu.Unlock()
+}
@@ -0,0 +1,248 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoHealthyProxy is returned by Pool.Pick when every proxy is evicted
+var ErrNoHealthyProxy = errors.New("no healthy proxy in pool")
+
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// Rotation selects how a Pool spreads requests over its proxies
+type Rotation int
+
+const (
+	// RoundRobin uses the healthy proxies in turn
+	RoundRobin Rotation = iota
+	// Sticky pins each key, e.g. a wallet address, to one proxy for as long
+	// as it stays healthy. Requests without a key rotate round-robin.
+	Sticky
+)
+
+// PoolOptions configures a Pool
+type PoolOptions struct {
+	// How requests are spread over the proxies (default RoundRobin)
+	Rotation Rotation
+
+	// Interval between background health checks (0 = no background checks)
+	HealthCheckInterval time.Duration
+
+	// Timeout of one proxy's health check (0 = 5 seconds)
+	HealthCheckTimeout time.Duration
+
+	// Address dialled through each proxy to check it, e.g.
+	// "clob.polymarket.com:443" (default: only connect to the proxy)
+	HealthCheckTarget string
+
+	// Check replaces the default health check
+	Check func(ctx context.Context, proxy *Config) error
+
+	// Called when a proxy is evicted (healthy false, with the cause) or
+	// restored by a health check
+	OnStateChange func(proxy *Config, healthy bool, err error)
+}
+
+// Pool rotates requests over a set of proxies and evicts the ones that fail
+// their health checks until they pass again. It is safe for concurrent use.
+type Pool struct {
+	options PoolOptions
+	members []*member
+	next    atomic.Uint64
+
+	mu      sync.RWMutex
+	healthy []*member
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// member is a proxy in a pool and its health
+type member struct {
+	config  *Config
+	id      string // stable identity used for sticky hashing
+	healthy bool
+	lastErr error
+}
+
+// NewPool creates a pool of proxies, all initially healthy. When options set
+// a HealthCheckInterval, checks run in the background until Close.
+func NewPool(proxies []*Config, options *PoolOptions) (*Pool, error) {
+	if len(proxies) == 0 {
+		return nil, errors.New("proxy pool needs at least one proxy")
+	}
+	p := &Pool{}
+	if options != nil {
+		p.options = *options
+	}
+	if p.options.HealthCheckTimeout <= 0 {
+		p.options.HealthCheckTimeout = defaultHealthCheckTimeout
+	}
+
+	for i, config := range proxies {
+		if err := config.Validate(); err != nil {
+			return nil, fmt.Errorf("proxy %d: %w", i, err)
+		}
+		m := &member{config: config, id: config.String(), healthy: true}
+		p.members = append(p.members, m)
+	}
+	p.healthy = append([]*member(nil), p.members...)
+
+	if p.options.HealthCheckInterval > 0 {
+		p.stop = make(chan struct{})
+		p.done = make(chan struct{})
+		go p.checkLoop()
+	}
+	return p, nil
+}
+
+// Pick returns the proxy for key according to the pool's rotation
+func (p *Pool) Pick(key string) (*Config, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.healthy) == 0 {
+		return nil, ErrNoHealthyProxy
+	}
+	if p.options.Rotation == Sticky && key != "" {
+		return rendezvous(p.healthy, key).config, nil
+	}
+	n := p.next.Add(1) - 1
+	return p.healthy[n%uint64(len(p.healthy))].config, nil
+}
+
+// rendezvous picks the member with the highest hash of key and its identity,
+// so a key only moves when its proxy is evicted
+func rendezvous(members []*member, key string) *member {
+	var best *member
+	var bestScore uint64
+	for _, m := range members {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(m.id))
+		if score := h.Sum64(); best == nil || score > bestScore {
+			best, bestScore = m, score
+		}
+	}
+	return best
+}
+
+// Healthy returns the proxies currently in rotation
+func (p *Pool) Healthy() []*Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	configs := make([]*Config, len(p.healthy))
+	for i, m := range p.healthy {
+		configs[i] = m.config
+	}
+	return configs
+}
+
+// Evict takes proxy out of rotation until a health check passes, e.g. after
+// the caller saw it fail
+func (p *Pool) Evict(proxy *Config, err error) {
+	for _, m := range p.members {
+		if m.config == proxy {
+			p.setHealth(m, err)
+		}
+	}
+}
+
+// CheckHealth checks every proxy concurrently, evicting the failing ones and
+// restoring the ones that recovered
+func (p *Pool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, m := range p.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, p.options.HealthCheckTimeout)
+			defer cancel()
+			p.setHealth(m, p.check(checkCtx, m.config))
+		}()
+	}
+	wg.Wait()
+}
+
+// check runs the configured or the default health check
+func (p *Pool) check(ctx context.Context, config *Config) error {
+	if p.options.Check != nil {
+		return p.options.Check(ctx, config)
+	}
+
+	var conn net.Conn
+	var err error
+	if p.options.HealthCheckTarget != "" {
+		conn, err = Dial(ctx, config, "tcp", p.options.HealthCheckTarget)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", config.Address())
+	}
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// setHealth records the outcome of a check; a nil err marks m healthy
+func (p *Pool) setHealth(m *member, err error) {
+	healthy := err == nil
+
+	p.mu.Lock()
+	changed := m.healthy != healthy
+	m.healthy, m.lastErr = healthy, err
+	if changed {
+		p.healthy = p.healthy[:0:0]
+		for _, member := range p.members {
+			if member.healthy {
+				p.healthy = append(p.healthy, member)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	if changed && p.options.OnStateChange != nil {
+		p.options.OnStateChange(m.config, healthy, err)
+	}
+}
+
+func (p *Pool) checkLoop() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.options.HealthCheckInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.stop
+		cancel()
+	}()
+
+	for {
+		p.CheckHealth(ctx)
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the background health checks
+func (p *Pool) Close() {
+	p.closeOnce.Do(func() {
+		if p.stop != nil {
+			close(p.stop)
+			<-p.done
+		}
+	})
+}
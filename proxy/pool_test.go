@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testPool(t *testing.T, n int, options *PoolOptions) (*Pool, []*Config) {
+	t.Helper()
+	configs := make([]*Config, n)
+	for i := range configs {
+		configs[i] = &Config{Host: fmt.Sprintf("proxy%d.example.com", i), Port: 8080}
+	}
+	pool, err := NewPool(configs, options)
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool, configs
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	pool, configs := testPool(t, 3, nil)
+
+	for i := range 6 {
+		got, err := pool.Pick("0xwallet")
+		if err != nil {
+			t.Fatalf("Pick() error = %v", err)
+		}
+		if want := configs[i%3]; got != want {
+			t.Errorf("Pick() #%d = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestPoolSticky(t *testing.T) {
+	pool, _ := testPool(t, 4, &PoolOptions{Rotation: Sticky})
+
+	wallets := []string{"0xaaa", "0xbbb", "0xccc", "0xddd", "0xeee"}
+	assigned := make(map[string]*Config)
+	for _, w := range wallets {
+		assigned[w], _ = pool.Pick(w)
+		for range 3 {
+			if got, _ := pool.Pick(w); got != assigned[w] {
+				t.Fatalf("Pick(%s) = %s, want sticky %s", w, got, assigned[w])
+			}
+		}
+	}
+
+	// Evicting one proxy moves only the wallets pinned to it
+	evicted := assigned[wallets[0]]
+	pool.Evict(evicted, errors.New("down"))
+	for _, w := range wallets {
+		got, _ := pool.Pick(w)
+		if got == evicted {
+			t.Errorf("Pick(%s) returned the evicted proxy", w)
+		}
+		if assigned[w] != evicted && got != assigned[w] {
+			t.Errorf("Pick(%s) = %s, moved from healthy %s", w, got, assigned[w])
+		}
+	}
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	down := map[string]bool{}
+	var changes []string
+	pool, configs := testPool(t, 2, &PoolOptions{
+		Check: func(ctx context.Context, p *Config) error {
+			if down[p.Host] {
+				return errors.New("unreachable")
+			}
+			return nil
+		},
+		OnStateChange: func(p *Config, healthy bool, err error) {
+			changes = append(changes, fmt.Sprintf("%s=%v", p.Host, healthy))
+		},
+	})
+
+	down[configs[0].Host] = true
+	pool.CheckHealth(t.Context())
+	if got := pool.Healthy(); len(got) != 1 || got[0] != configs[1] {
+		t.Fatalf("Healthy() = %v, want [%s]", got, configs[1])
+	}
+	for range 3 {
+		if got, _ := pool.Pick(""); got != configs[1] {
+			t.Errorf("Pick() = %s, want %s", got, configs[1])
+		}
+	}
+
+	down[configs[1].Host] = true
+	pool.CheckHealth(t.Context())
+	if _, err := pool.Pick(""); !errors.Is(err, ErrNoHealthyProxy) {
+		t.Errorf("Pick() error = %v, want ErrNoHealthyProxy", err)
+	}
+
+	clear(down)
+	pool.CheckHealth(t.Context())
+	if got := len(pool.Healthy()); got != 2 {
+		t.Errorf("len(Healthy()) = %d, want 2", got)
+	}
+	if len(changes) != 4 {
+		t.Errorf("OnStateChange calls = %v, want 4", changes)
+	}
+}
+
+func TestNewPoolRejectsInvalidProxy(t *testing.T) {
+	if _, err := NewPool(nil, nil); err == nil {
+		t.Error("NewPool(nil) succeeded, want error")
+	}
+	socks := "socks4"
+	if _, err := NewPool([]*Config{{Host: "proxy.example.com", Protocol: &socks}}, nil); err == nil {
+		t.Error("NewPool() with socks4 succeeded, want error")
+	}
+}
@@ -0,0 +1,160 @@
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// SOCKS5 protocol constants (RFC 1928, RFC 1929)
+const (
+	socks5Version       = 5
+	socks5AuthNone      = 0
+	socks5AuthPassword  = 2
+	socks5NoAcceptable  = 0xff
+	socks5PasswordAuthV = 1
+	socks5CmdConnect    = 1
+	socks5AddrIPv4      = 1
+	socks5AddrDomain    = 3
+	socks5AddrIPv6      = 4
+)
+
+var noDeadline time.Time
+
+// socks5Replies describes the reply codes of a failed CONNECT
+var socks5Replies = map[byte]string{
+	1: "general SOCKS server failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// socks5Connect negotiates authentication on conn and asks the proxy to
+// connect to addr. Hostnames are always sent to the proxy to resolve.
+func socks5Connect(conn net.Conn, addr string, username, password *string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %q", portStr)
+	}
+
+	// Greeting: offer password authentication only when there are credentials
+	methods := []byte{socks5AuthNone}
+	if username != nil {
+		methods = append(methods, socks5AuthPassword)
+	}
+	greeting := append([]byte{socks5Version, byte(len(methods))}, methods...)
+	if _, err := conn.Write(greeting); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 greeting: %w", err)
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 greeting: %w", err)
+	}
+	if reply[0] != socks5Version {
+		return fmt.Errorf("unexpected SOCKS version %d", reply[0])
+	}
+
+	switch reply[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if username == nil {
+			return errors.New("SOCKS5 proxy chose password authentication, which was not offered")
+		}
+		if err := socks5Authenticate(conn, *username, password); err != nil {
+			return err
+		}
+	case socks5NoAcceptable:
+		return errors.New("SOCKS5 proxy accepts none of the offered authentication methods")
+	default:
+		return fmt.Errorf("SOCKS5 proxy chose unsupported authentication method %d", reply[1])
+	}
+
+	// CONNECT request
+	req := []byte{socks5Version, socks5CmdConnect, 0}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(append(req, socks5AddrIPv4), ip4...)
+		} else {
+			req = append(append(req, socks5AddrIPv6), ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("hostname too long: %s", host)
+		}
+		req = append(append(req, socks5AddrDomain, byte(len(host))), host...)
+	}
+	req = append(req, byte(port>>8), byte(port))
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 connect: %w", err)
+	}
+
+	// Reply: VER REP RSV ATYP BND.ADDR BND.PORT
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 reply: %w", err)
+	}
+	if header[1] != 0 {
+		reason, ok := socks5Replies[header[1]]
+		if !ok {
+			reason = fmt.Sprintf("reply code %d", header[1])
+		}
+		return fmt.Errorf("SOCKS5 connect to %s failed: %s", addr, reason)
+	}
+	var skip int
+	switch header[3] {
+	case socks5AddrIPv4:
+		skip = net.IPv4len
+	case socks5AddrIPv6:
+		skip = net.IPv6len
+	case socks5AddrDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return fmt.Errorf("failed to read SOCKS5 reply: %w", err)
+		}
+		skip = int(length[0])
+	default:
+		return fmt.Errorf("unexpected SOCKS5 address type %d", header[3])
+	}
+	if _, err := io.ReadFull(conn, make([]byte, skip+2)); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 reply: %w", err)
+	}
+	return nil
+}
+
+// socks5Authenticate runs the username/password sub-negotiation
+func socks5Authenticate(conn net.Conn, username string, password *string) error {
+	pass := ""
+	if password != nil {
+		pass = *password
+	}
+	if len(username) > 255 || len(pass) > 255 {
+		return errors.New("SOCKS5 username or password too long")
+	}
+
+	req := []byte{socks5PasswordAuthV, byte(len(username))}
+	req = append(req, username...)
+	req = append(req, byte(len(pass)))
+	req = append(req, pass...)
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("failed to send SOCKS5 credentials: %w", err)
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 authentication reply: %w", err)
+	}
+	if reply[1] != 0 {
+		return errors.New("SOCKS5 authentication failed")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import "fmt"
+
+// ContractConfig holds the addresses of the Polymarket contracts on a chain
+type ContractConfig struct {
+	Exchange          string `json:"exchange"`          // CTF Exchange, verifies regular orders
+	NegRiskExchange   string `json:"negRiskExchange"`   // Neg Risk CTF Exchange, verifies neg risk orders
+	NegRiskAdapter    string `json:"negRiskAdapter"`    // Neg Risk Adapter, converts and redeems neg risk positions
+	Collateral        string `json:"collateral"`        // USDC collateral token
+	ConditionalTokens string `json:"conditionalTokens"` // Gnosis Conditional Tokens Framework
+}
+
+var contractConfigs = map[Chain]ContractConfig{
+	ChainPolygon: {
+		Exchange:          "0x4bFb41d5B3570DeFd03C39a9A4D8dE6Bd8B8982E",
+		NegRiskExchange:   "0xC5d563A36AE78145C45a50134d48A1215220f80a",
+		NegRiskAdapter:    "0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296",
+		Collateral:        "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174",
+		ConditionalTokens: "0x4D97DCd97eC945f40cF65F87097ACe5EA0476045",
+	},
+	ChainAmoy: {
+		Exchange:          "0xdFE02Eb6733538f8Ea35D585af8DE5958AD99E40",
+		NegRiskExchange:   "0xC5d563A36AE78145C45a50134d48A1215220f80a",
+		NegRiskAdapter:    "0xd91E80cF2E7be2e162c6513ceD06f1dD0dA35296",
+		Collateral:        "0x9c4e1703476e875070ee25b56a58b008cfb8fa78",
+		ConditionalTokens: "0x69308FB512518e39F9b16112fA8d994F4e2Bf8bB",
+	},
+}
+
+// GetContractConfig returns the contract addresses of chain
+func GetContractConfig(chain Chain) (ContractConfig, error) {
+	config, ok := contractConfigs[chain]
+	if !ok {
+		return ContractConfig{}, fmt.Errorf("no contracts known for chain %d", chain)
+	}
+	return config, nil
+}
+
+// RoundingConfig returns how prices, sizes and amounts are rounded for
+// orders on markets with tick size t, in decimal places
+func (t TickSize) RoundingConfig() (RoundConfig, error) {
+	switch t {
+	case TickSize01:
+		return RoundConfig{Price: 1, Size: 2, Amount: 3}, nil
+	case TickSize001:
+		return RoundConfig{Price: 2, Size: 2, Amount: 4}, nil
+	case TickSize0001:
+		return RoundConfig{Price: 3, Size: 2, Amount: 5}, nil
+	case TickSize00001:
+		return RoundConfig{Price: 4, Size: 2, Amount: 6}, nil
+	}
+	return RoundConfig{}, fmt.Errorf("unsupported tick size %q", string(t))
+}
+
+// CancelOrdersResponse is the result of the cancel endpoints
+type CancelOrdersResponse struct {
+	Canceled    []string          `json:"canceled"`
+	NotCanceled map[string]string `json:"not_canceled"` // reason by order ID
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"log/slog"
 	"math/big"
 	"time"
 )
@@ -35,7 +36,11 @@ const (
 type SignatureType int
 
 const (
-	SignatureTypeEIP712  SignatureType = 0
+	SignatureTypeEIP712         SignatureType = 0 // The signing wallet makes the order
+	SignatureTypePolyProxy      SignatureType = 1 // A Polymarket proxy wallet makes the order
+	SignatureTypePolyGnosisSafe SignatureType = 2 // A Polymarket Gnosis Safe makes the order
+
+	// Deprecated: use SignatureTypePolyGnosisSafe
 	SignatureTypeEthSign SignatureType = 2
 )
 
@@ -46,6 +51,18 @@ type ApiKeyCreds struct {
 	Passphrase string `json:"passphrase"`
 }
 
+// LogValue keeps the secret and passphrase out of logs
+func (c ApiKeyCreds) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("key", c.Key),
+		slog.String("secret", redacted),
+		slog.String("passphrase", redacted),
+	)
+}
+
+// redacted replaces secrets in logs
+const redacted = "[REDACTED]"
+
 // ApiKeyRaw represents raw API key response
 type ApiKeyRaw struct {
 	APIKey     string `json:"apiKey"`
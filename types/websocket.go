@@ -16,19 +16,41 @@ const (
 	EventTypePriceChange    EventType = "price_change"
 	EventTypeTickSizeChange EventType = "tick_size_change"
 	EventTypeLastTradePrice EventType = "last_trade_price"
+	EventTypeBestBidAsk     EventType = "best_bid_ask"
+	EventTypeNewMarket      EventType = "new_market"
+	EventTypeMarketResolved EventType = "market_resolved"
 )
 
 // Note: OrderSummary and Side types are already defined in types.go
 
 // BookMessage represents a full orderbook snapshot or update
 type BookMessage struct {
-	EventType EventType      `json:"event_type"`
-	AssetID   string         `json:"asset_id"`
-	Market    string         `json:"market"`
-	Timestamp string         `json:"timestamp"`
-	Hash      string         `json:"hash"`
-	Bids      []OrderSummary `json:"bids"`
-	Asks      []OrderSummary `json:"asks"`
+	EventType      EventType      `json:"event_type"`
+	AssetID        string         `json:"asset_id"`
+	Market         string         `json:"market"`
+	Timestamp      string         `json:"timestamp"`
+	Hash           string         `json:"hash"`
+	Bids           []OrderSummary `json:"bids"`
+	Asks           []OrderSummary `json:"asks"`
+	MinOrderSize   string         `json:"min_order_size,omitempty"`
+	TickSize       string         `json:"tick_size,omitempty"`
+	NegRisk        bool           `json:"neg_risk,omitempty"`
+	LastTradePrice string         `json:"last_trade_price,omitempty"`
+}
+
+// ToOrderBookSummary converts the snapshot to the REST order book representation
+func (m *BookMessage) ToOrderBookSummary() *OrderBookSummary {
+	return &OrderBookSummary{
+		Market:       m.Market,
+		AssetID:      m.AssetID,
+		Timestamp:    m.Timestamp,
+		Bids:         m.Bids,
+		Asks:         m.Asks,
+		MinOrderSize: m.MinOrderSize,
+		TickSize:     m.TickSize,
+		NegRisk:      m.NegRisk,
+		Hash:         m.Hash,
+	}
 }
 
 // Validate validates the BookMessage
@@ -183,6 +205,113 @@ func (m *LastTradePriceMessage) Validate() error {
 	return nil
 }
 
+// BestBidAskMessage reports a change of the top of book for an asset
+type BestBidAskMessage struct {
+	EventType EventType `json:"event_type"`
+	AssetID   string    `json:"asset_id"`
+	Market    string    `json:"market"`
+	BestBid   string    `json:"best_bid"`
+	BestAsk   string    `json:"best_ask"`
+	Spread    string    `json:"spread"`
+	Timestamp string    `json:"timestamp"`
+}
+
+// Validate validates the BestBidAskMessage
+func (m *BestBidAskMessage) Validate() error {
+	if m.EventType != EventTypeBestBidAsk {
+		return fmt.Errorf("invalid event_type: expected 'best_bid_ask', got '%s'", m.EventType)
+	}
+	if m.AssetID == "" {
+		return fmt.Errorf("asset_id is required")
+	}
+	if m.Market == "" {
+		return fmt.Errorf("market is required")
+	}
+	if m.Timestamp == "" {
+		return fmt.Errorf("timestamp is required")
+	}
+	return nil
+}
+
+// MarketEventMessage carries the event a new or resolved market belongs to
+type MarketEventMessage struct {
+	ID          string `json:"id"`
+	Ticker      string `json:"ticker"`
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// NewMarketMessage announces a market that was just created
+type NewMarketMessage struct {
+	EventType    EventType           `json:"event_type"`
+	ID           string              `json:"id"`
+	Question     string              `json:"question"`
+	Market       string              `json:"market"`
+	Slug         string              `json:"slug"`
+	Description  string              `json:"description"`
+	AssetIDs     []string            `json:"assets_ids"`
+	Outcomes     []string            `json:"outcomes"`
+	EventMessage *MarketEventMessage `json:"event_message,omitempty"`
+	Timestamp    string              `json:"timestamp"`
+}
+
+// Validate validates the NewMarketMessage
+func (m *NewMarketMessage) Validate() error {
+	if m.EventType != EventTypeNewMarket {
+		return fmt.Errorf("invalid event_type: expected 'new_market', got '%s'", m.EventType)
+	}
+	if m.Market == "" {
+		return fmt.Errorf("market is required")
+	}
+	if m.Timestamp == "" {
+		return fmt.Errorf("timestamp is required")
+	}
+	return nil
+}
+
+// MarketResolvedMessage announces the winning outcome of a market
+type MarketResolvedMessage struct {
+	EventType      EventType           `json:"event_type"`
+	ID             string              `json:"id"`
+	Question       string              `json:"question"`
+	Market         string              `json:"market"`
+	Slug           string              `json:"slug"`
+	Description    string              `json:"description"`
+	AssetIDs       []string            `json:"assets_ids"`
+	Outcomes       []string            `json:"outcomes"`
+	WinningAssetID string              `json:"winning_asset_id"`
+	WinningOutcome string              `json:"winning_outcome"`
+	EventMessage   *MarketEventMessage `json:"event_message,omitempty"`
+	Timestamp      string              `json:"timestamp"`
+}
+
+// Validate validates the MarketResolvedMessage
+func (m *MarketResolvedMessage) Validate() error {
+	if m.EventType != EventTypeMarketResolved {
+		return fmt.Errorf("invalid event_type: expected 'market_resolved', got '%s'", m.EventType)
+	}
+	if m.Market == "" {
+		return fmt.Errorf("market is required")
+	}
+	if m.Timestamp == "" {
+		return fmt.Errorf("timestamp is required")
+	}
+	return nil
+}
+
+// RawMessage is a message whose event_type this SDK does not know yet.
+// It is surfaced instead of an error so new server events do not break clients.
+type RawMessage struct {
+	EventType EventType       `json:"event_type"`
+	Data      json.RawMessage `json:"-"`
+}
+
+// Validate always succeeds for raw messages
+func (m *RawMessage) Validate() error {
+	return nil
+}
+
 // MarketChannelMessage is a union type for all market channel messages
 type MarketChannelMessage interface {
 	Validate() error
@@ -209,7 +338,28 @@ func (m *LastTradePriceMessage) GetEventType() EventType {
 	return m.EventType
 }
 
-// ParseMarketChannelMessage parses and validates a WebSocket message
+// GetEventType returns the event type for BestBidAskMessage
+func (m *BestBidAskMessage) GetEventType() EventType {
+	return m.EventType
+}
+
+// GetEventType returns the event type for NewMarketMessage
+func (m *NewMarketMessage) GetEventType() EventType {
+	return m.EventType
+}
+
+// GetEventType returns the event type for MarketResolvedMessage
+func (m *MarketResolvedMessage) GetEventType() EventType {
+	return m.EventType
+}
+
+// GetEventType returns the event type for RawMessage
+func (m *RawMessage) GetEventType() EventType {
+	return m.EventType
+}
+
+// ParseMarketChannelMessage parses and validates a WebSocket message.
+// Messages with an unknown event_type are returned as *RawMessage.
 func ParseMarketChannelMessage(data []byte) (MarketChannelMessage, error) {
 	// First, unmarshal just to get the event_type
 	var eventTypeWrapper struct {
@@ -262,8 +412,40 @@ func ParseMarketChannelMessage(data []byte) (MarketChannelMessage, error) {
 		}
 		return &msg, nil
 
+	case EventTypeBestBidAsk:
+		var msg BestBidAskMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse best_bid_ask message: %w", err)
+		}
+		if err := msg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid best_bid_ask message: %w", err)
+		}
+		return &msg, nil
+
+	case EventTypeNewMarket:
+		var msg NewMarketMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse new_market message: %w", err)
+		}
+		if err := msg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid new_market message: %w", err)
+		}
+		return &msg, nil
+
+	case EventTypeMarketResolved:
+		var msg MarketResolvedMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse market_resolved message: %w", err)
+		}
+		if err := msg.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid market_resolved message: %w", err)
+		}
+		return &msg, nil
+
 	default:
-		return nil, fmt.Errorf("unknown event_type: %s", eventTypeWrapper.EventType)
+		raw := make(json.RawMessage, len(data))
+		copy(raw, data)
+		return &RawMessage{EventType: eventTypeWrapper.EventType, Data: raw}, nil
 	}
 }
 
@@ -300,3 +482,35 @@ func AsLastTradePriceMessage(msg MarketChannelMessage) (*LastTradePriceMessage,
 	}
 	return nil, false
 }
+
+// AsBestBidAskMessage attempts to cast to BestBidAskMessage
+func AsBestBidAskMessage(msg MarketChannelMessage) (*BestBidAskMessage, bool) {
+	if m, ok := msg.(*BestBidAskMessage); ok {
+		return m, true
+	}
+	return nil, false
+}
+
+// AsNewMarketMessage attempts to cast to NewMarketMessage
+func AsNewMarketMessage(msg MarketChannelMessage) (*NewMarketMessage, bool) {
+	if m, ok := msg.(*NewMarketMessage); ok {
+		return m, true
+	}
+	return nil, false
+}
+
+// AsMarketResolvedMessage attempts to cast to MarketResolvedMessage
+func AsMarketResolvedMessage(msg MarketChannelMessage) (*MarketResolvedMessage, bool) {
+	if m, ok := msg.(*MarketResolvedMessage); ok {
+		return m, true
+	}
+	return nil, false
+}
+
+// AsRawMessage attempts to cast to RawMessage
+func AsRawMessage(msg MarketChannelMessage) (*RawMessage, bool) {
+	if m, ok := msg.(*RawMessage); ok {
+		return m, true
+	}
+	return nil, false
+}
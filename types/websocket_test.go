@@ -0,0 +1,209 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testMarket = "0xbd31dc8a20211944f6b70f31557f1001557b59905b7738480ca09bd4532f84af"
+	yesAsset   = "65818619657568813474341868652308942079804919287380422192892211131408793125422"
+	noAsset    = "52114319501245915516055106046884209969926127482827954674443846427813813222426"
+)
+
+func TestParseMarketChannelMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    MarketChannelMessage
+		wantErr string
+	}{
+		{
+			name: "book",
+			payload: `{"event_type":"book","asset_id":"` + yesAsset + `","market":"` + testMarket + `",
+				"bids":[{"price":"0.48","size":"30"},{"price":"0.49","size":"20"}],
+				"asks":[{"price":"0.52","size":"25"}],
+				"timestamp":"1757908892351","hash":"0x5e2a01b9b9e0c5",
+				"min_order_size":"5","tick_size":"0.01","neg_risk":true,"last_trade_price":"0.5"}`,
+			want: &BookMessage{
+				EventType:      EventTypeBook,
+				AssetID:        yesAsset,
+				Market:         testMarket,
+				Timestamp:      "1757908892351",
+				Hash:           "0x5e2a01b9b9e0c5",
+				Bids:           []OrderSummary{{Price: "0.48", Size: "30"}, {Price: "0.49", Size: "20"}},
+				Asks:           []OrderSummary{{Price: "0.52", Size: "25"}},
+				MinOrderSize:   "5",
+				TickSize:       "0.01",
+				NegRisk:        true,
+				LastTradePrice: "0.5",
+			},
+		},
+		{
+			name: "best bid ask",
+			payload: `{"event_type":"best_bid_ask","market":"` + testMarket + `","asset_id":"` + yesAsset + `",
+				"best_bid":"0.73","best_ask":"0.77","spread":"0.04","timestamp":"1766789469958"}`,
+			want: &BestBidAskMessage{
+				EventType: EventTypeBestBidAsk,
+				AssetID:   yesAsset,
+				Market:    testMarket,
+				BestBid:   "0.73",
+				BestAsk:   "0.77",
+				Spread:    "0.04",
+				Timestamp: "1766789469958",
+			},
+		},
+		{
+			name: "new market",
+			payload: `{"event_type":"new_market","id":"1031769","question":"Will NVIDIA (NVDA) close above $240 end of January?",
+				"market":"` + testMarket + `","slug":"nvda-above-240-on-january-30-2026","description":"This market will resolve to Yes if...",
+				"assets_ids":["` + yesAsset + `","` + noAsset + `"],"outcomes":["Yes","No"],
+				"event_message":{"id":"125819","ticker":"nvda-above-in-january-2026","slug":"nvda-above-in-january-2026","title":"Will NVIDIA (NVDA) close above ___ end of January?","description":""},
+				"timestamp":"1766790415550"}`,
+			want: &NewMarketMessage{
+				EventType:   EventTypeNewMarket,
+				ID:          "1031769",
+				Question:    "Will NVIDIA (NVDA) close above $240 end of January?",
+				Market:      testMarket,
+				Slug:        "nvda-above-240-on-january-30-2026",
+				Description: "This market will resolve to Yes if...",
+				AssetIDs:    []string{yesAsset, noAsset},
+				Outcomes:    []string{"Yes", "No"},
+				EventMessage: &MarketEventMessage{
+					ID:     "125819",
+					Ticker: "nvda-above-in-january-2026",
+					Slug:   "nvda-above-in-january-2026",
+					Title:  "Will NVIDIA (NVDA) close above ___ end of January?",
+				},
+				Timestamp: "1766790415550",
+			},
+		},
+		{
+			name: "market resolved",
+			payload: `{"event_type":"market_resolved","id":"1031769","question":"Will NVIDIA (NVDA) close above $240 end of January?",
+				"market":"` + testMarket + `","slug":"nvda-above-240-on-january-30-2026","description":"",
+				"assets_ids":["` + yesAsset + `","` + noAsset + `"],"outcomes":["Yes","No"],
+				"winning_asset_id":"` + noAsset + `","winning_outcome":"No",
+				"event_message":{"id":"125819","ticker":"nvda-above-in-january-2026","slug":"nvda-above-in-january-2026","title":"Will NVIDIA (NVDA) close above ___ end of January?","description":""},
+				"timestamp":"1766790415550"}`,
+			want: &MarketResolvedMessage{
+				EventType:      EventTypeMarketResolved,
+				ID:             "1031769",
+				Question:       "Will NVIDIA (NVDA) close above $240 end of January?",
+				Market:         testMarket,
+				Slug:           "nvda-above-240-on-january-30-2026",
+				AssetIDs:       []string{yesAsset, noAsset},
+				Outcomes:       []string{"Yes", "No"},
+				WinningAssetID: noAsset,
+				WinningOutcome: "No",
+				EventMessage: &MarketEventMessage{
+					ID:     "125819",
+					Ticker: "nvda-above-in-january-2026",
+					Slug:   "nvda-above-in-january-2026",
+					Title:  "Will NVIDIA (NVDA) close above ___ end of January?",
+				},
+				Timestamp: "1766790415550",
+			},
+		},
+		{
+			name:    "unknown event type",
+			payload: `{"event_type":"market_paused","market":"` + testMarket + `","reason":"maintenance"}`,
+			want: &RawMessage{
+				EventType: "market_paused",
+				Data:      []byte(`{"event_type":"market_paused","market":"` + testMarket + `","reason":"maintenance"}`),
+			},
+		},
+		{
+			name:    "missing event type",
+			payload: `{"market":"` + testMarket + `"}`,
+			want:    &RawMessage{Data: []byte(`{"market":"` + testMarket + `"}`)},
+		},
+		{
+			name:    "invalid best bid ask",
+			payload: `{"event_type":"best_bid_ask","market":"` + testMarket + `","best_bid":"0.73","timestamp":"1766789469958"}`,
+			wantErr: "invalid best_bid_ask message: asset_id is required",
+		},
+		{
+			name:    "invalid new market",
+			payload: `{"event_type":"new_market","market":"` + testMarket + `","assets_ids":"not a list","timestamp":"1"}`,
+			wantErr: "failed to parse new_market message",
+		},
+		{
+			name:    "invalid market resolved",
+			payload: `{"event_type":"market_resolved","market":"` + testMarket + `"}`,
+			wantErr: "invalid market_resolved message: timestamp is required",
+		},
+		{
+			name:    "not an object",
+			payload: `"PONG"`,
+			wantErr: "failed to parse event_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMarketChannelMessage([]byte(tt.payload))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseMarketChannelMessage() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMarketChannelMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMarketChannelMessage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawMessageOwnsItsData(t *testing.T) {
+	payload := []byte(`{"event_type":"market_paused"}`)
+	msg, err := ParseMarketChannelMessage(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := AsRawMessage(msg)
+	if !ok {
+		t.Fatalf("ParseMarketChannelMessage() = %T, want *RawMessage", msg)
+	}
+
+	// The read buffer is reused by the connection; the message must not change
+	copy(payload, "XXXXXXXXXXXX")
+	if string(raw.Data) != `{"event_type":"market_paused"}` {
+		t.Errorf("RawMessage.Data = %s after the buffer was reused", raw.Data)
+	}
+}
+
+func TestBookMessageToOrderBookSummary(t *testing.T) {
+	msg := &BookMessage{
+		EventType:      EventTypeBook,
+		AssetID:        yesAsset,
+		Market:         testMarket,
+		Timestamp:      "1757908892351",
+		Hash:           "0x5e2a01b9b9e0c5",
+		Bids:           []OrderSummary{{Price: "0.48", Size: "30"}},
+		Asks:           []OrderSummary{{Price: "0.52", Size: "25"}},
+		MinOrderSize:   "5",
+		TickSize:       "0.01",
+		NegRisk:        true,
+		LastTradePrice: "0.5",
+	}
+	want := &OrderBookSummary{
+		Market:       testMarket,
+		AssetID:      yesAsset,
+		Timestamp:    "1757908892351",
+		Bids:         []OrderSummary{{Price: "0.48", Size: "30"}},
+		Asks:         []OrderSummary{{Price: "0.52", Size: "25"}},
+		MinOrderSize: "5",
+		TickSize:     "0.01",
+		NegRisk:      true,
+		Hash:         "0x5e2a01b9b9e0c5",
+	}
+	if got := msg.ToOrderBookSummary(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToOrderBookSummary() = %+v, want %+v", got, want)
+	}
+}